@@ -20,6 +20,50 @@ const (
 	IngestRole NodeRole = "ingest"
 )
 
+type UpgradePhase string
+
+const (
+	// UpgradeInProgress means nodes are being upgraded
+	UpgradeInProgress UpgradePhase = "InProgress"
+	// UpgradePaused means the upgrade is waiting for the cluster to become healthy
+	UpgradePaused UpgradePhase = "Paused"
+	// UpgradeCompleted means all nodes run the target version
+	UpgradeCompleted UpgradePhase = "Completed"
+	// UpgradeRejected means the version change is not supported, and the nodes keep their current image
+	UpgradeRejected UpgradePhase = "Rejected"
+	// UpgradeRollingBack means upgraded nodes are being restored to the previous image
+	UpgradeRollingBack UpgradePhase = "RollingBack"
+	// UpgradeRolledBack means all nodes were restored to the previous version
+	UpgradeRolledBack UpgradePhase = "RolledBack"
+	// UpgradeFailed means a node failed after a master was upgraded, so the upgrade cannot be rolled back
+	UpgradeFailed UpgradePhase = "Failed"
+)
+
+type NodeDrainPhase string
+
+const (
+	// NodeDraining means shards are being moved off the node
+	NodeDraining NodeDrainPhase = "Draining"
+	// NodeDrained means the node holds no shards, and is waiting to leave the cluster
+	NodeDrained NodeDrainPhase = "Drained"
+	// NodeDrainStalled means the node still holds shards after the drain timeout, e.g., because the remaining nodes
+	// cannot hold the replicas. The drain goes on until the shards are moved, or the node is kept.
+	NodeDrainStalled NodeDrainPhase = "Stalled"
+)
+
+type IndexMigrationPhase string
+
+const (
+	// IndexMigrationPending means the index is waiting to be reindexed
+	IndexMigrationPending IndexMigrationPhase = "Pending"
+	// IndexMigrationReindexing means an OpenSearch task is reindexing the index into its data stream
+	IndexMigrationReindexing IndexMigrationPhase = "Reindexing"
+	// IndexMigrationCompleted means the documents were reindexed, and the source index was deleted
+	IndexMigrationCompleted IndexMigrationPhase = "Completed"
+	// IndexMigrationFailed means all reindex attempts failed, and the source index was kept
+	IndexMigrationFailed IndexMigrationPhase = "Failed"
+)
+
 type (
 
 	// VerrazzanoMonitoringInstanceSpec defines the attributes a user can specify when creating a VerrazzanoMonitoringInstance
@@ -107,6 +151,19 @@ type (
 		RetentionPeriod        int32     `json:"retentionPeriod,omitempty"`
 		Replicas               int32     `json:"replicas,omitempty"`
 		HTTP2Enabled           bool      `json:"http2Enabled,omitempty" yaml:"http2Enabled"`
+		// Scrape configs merged after the default scrape configs, in order. Job names must be unique.
+		AdditionalScrapeConfigs []ScrapeConfigSource `json:"additionalScrapeConfigs,omitempty"`
+		// Remote storages which Prometheus sends its samples to
+		RemoteWrite []RemoteWrite `json:"remoteWrite,omitempty"`
+	}
+
+	// ScrapeConfigSource is a key of a Secret or ConfigMap in the VMI namespace, which holds a YAML list of Prometheus scrape configs.
+	// The scrape configs are merged into the Prometheus ConfigMap, so the scrape configs of a Secret may not hold inline
+	// credentials: the Secret is mounted at /etc/prometheus/scrape-configs/<secret name>, and its keys are referenced with
+	// the *_file fields, such as password_file.
+	ScrapeConfigSource struct {
+		Secret    *corev1.SecretKeySelector    `json:"secret,omitempty"`
+		ConfigMap *corev1.ConfigMapKeySelector `json:"configMap,omitempty"`
 	}
 
 	// AlertManager details
@@ -128,6 +185,176 @@ type (
 		DataNode   ElasticsearchNode       `json:"dataNode,omitempty"`
 		Policies   []IndexManagementPolicy `json:"policies,omitempty"`
 		Nodes      []ElasticsearchNode     `json:"nodes,omitempty"`
+		// Persistent cluster settings applied through the _cluster/settings API. cluster.routing.allocation.exclude._name
+		// and cluster.routing.allocation.enable are managed by the operator while nodes are drained and restarted, and are rejected.
+		ClusterSettings map[string]string `json:"clusterSettings,omitempty"`
+		// Settings which throttle the migration of old indices to data streams
+		Migration *IndexMigrationSettings `json:"migration,omitempty"`
+		// Remote clusters searched through cross-cluster search, configured as cluster.remote.* cluster settings
+		RemoteClusters []RemoteCluster `json:"remoteClusters,omitempty"`
+		// Index patterns created in OpenSearch Dashboards, which search the local cluster and all remote clusters,
+		// e.g., verrazzano-application-*
+		CrossClusterIndexPatterns []string `json:"crossClusterIndexPatterns,omitempty"`
+		// Plugins installed on all OpenSearch nodes. Changing the plugins restarts the nodes one at a time.
+		Plugins []OpenSearchPlugin `json:"plugins,omitempty"`
+		// Roles, role mappings and internal users managed through the OpenSearch security plugin
+		Security *OpenSearchSecurity `json:"security,omitempty"`
+		// Monitors managed through the OpenSearch Alerting plugin
+		Monitors []AlertingMonitor `json:"monitors,omitempty"`
+		// Channels managed through the OpenSearch Notifications plugin, which monitors send alerts to
+		NotificationChannels []NotificationChannel `json:"notificationChannels,omitempty"`
+	}
+
+	// AlertingMonitor runs a query on a schedule, and alerts when a trigger condition is met
+	AlertingMonitor struct {
+		Name string `json:"name"`
+		// Disabled monitors are not run, defaults to true
+		Enabled *bool `json:"enabled,omitempty"`
+		// Indices or index patterns the query runs on, e.g., verrazzano-application-*
+		Indices []string `json:"indices"`
+		// Search request body as JSON, e.g., {"size": 0, "query": {"match": {"level": "error"}}}
+		Query    string           `json:"query"`
+		Schedule MonitorSchedule  `json:"schedule"`
+		Triggers []MonitorTrigger `json:"triggers,omitempty"`
+	}
+
+	// MonitorSchedule is either an interval or a cron expression
+	MonitorSchedule struct {
+		// Time between runs, e.g., 5m
+		// +kubebuilder:validation:Pattern:=^[0-9]+(m|h|d)$
+		Interval string `json:"interval,omitempty"`
+		// Cron expression, used instead of the interval
+		Cron string `json:"cron,omitempty"`
+		// Time zone of the cron expression, defaults to UTC
+		Timezone string `json:"timezone,omitempty"`
+	}
+
+	// MonitorTrigger runs actions when its condition on the query results is true
+	MonitorTrigger struct {
+		Name string `json:"name"`
+		// Severity from 1, the highest, to 5
+		// +kubebuilder:validation:Pattern:=^[1-5]$
+		Severity string `json:"severity"`
+		// Painless script condition, e.g., ctx.results[0].hits.total.value > 100
+		Condition string          `json:"condition"`
+		Actions   []MonitorAction `json:"actions,omitempty"`
+	}
+
+	// MonitorAction sends a message to a notification channel
+	MonitorAction struct {
+		Name string `json:"name"`
+		// Name of a notification channel, or the ID of a channel created outside of the VMI
+		Channel string `json:"channel"`
+		// Mustache template of the message subject
+		Subject string `json:"subject,omitempty"`
+		// Mustache template of the message
+		Message string `json:"message"`
+		// Minimum time between messages, e.g., 10m
+		// +kubebuilder:validation:Pattern:=^[0-9]+m$
+		Throttle string `json:"throttle,omitempty"`
+	}
+
+	// NotificationChannel is a Slack, webhook or email destination for alerts
+	NotificationChannel struct {
+		Name string `json:"name"`
+		// +kubebuilder:validation:Enum=slack;webhook;email
+		Type string `json:"type"`
+		// Key of a Secret in the VMI namespace which holds the Slack or webhook URL
+		URLSecret *corev1.SecretKeySelector `json:"urlSecret,omitempty"`
+		Email     *EmailChannel             `json:"email,omitempty"`
+	}
+
+	// EmailChannel sends alerts through an SMTP server
+	EmailChannel struct {
+		Host string `json:"host"`
+		Port int32  `json:"port"`
+		// +kubebuilder:validation:Enum=none;ssl;start_tls
+		Method     string   `json:"method,omitempty"`
+		From       string   `json:"from"`
+		Recipients []string `json:"recipients"`
+		// Secret in the VMI namespace with the username and password keys of the SMTP server. The credentials are added
+		// to the OpenSearch keystore as the master and data nodes start, so changed credentials apply once they restart.
+		CredentialsSecret *corev1.LocalObjectReference `json:"credentialsSecret,omitempty"`
+	}
+
+	// OpenSearchSecurity configures the OpenSearch security plugin. Roles, role mappings and internal users
+	// created by the operator are deleted once they are removed from this list. Existing ones which were not
+	// created by the operator, such as the all_access role mapping, are not taken over.
+	OpenSearchSecurity struct {
+		Roles         []SecurityRole         `json:"roles,omitempty"`
+		RoleMappings  []SecurityRoleMapping  `json:"roleMappings,omitempty"`
+		InternalUsers []SecurityInternalUser `json:"internalUsers,omitempty"`
+	}
+
+	// SecurityRole grants actions on the cluster and on indices
+	SecurityRole struct {
+		Name string `json:"name"`
+		// Cluster wide actions or action groups, e.g., cluster_monitor
+		ClusterPermissions []string                  `json:"clusterPermissions,omitempty"`
+		IndexPermissions   []SecurityIndexPermission `json:"indexPermissions,omitempty"`
+	}
+
+	// SecurityIndexPermission allows actions on the indices matching the index patterns
+	SecurityIndexPermission struct {
+		// Index patterns, e.g., verrazzano-application-*
+		IndexPatterns []string `json:"indexPatterns"`
+		// Actions or action groups allowed on the indices, e.g., read
+		AllowedActions []string `json:"allowedActions"`
+	}
+
+	// SecurityRoleMapping maps backend roles, such as Keycloak roles, and internal users to a role
+	SecurityRoleMapping struct {
+		Role         string   `json:"role"`
+		BackendRoles []string `json:"backendRoles,omitempty"`
+		Users        []string `json:"users,omitempty"`
+	}
+
+	// SecurityInternalUser is a user of the OpenSearch internal user database
+	SecurityInternalUser struct {
+		Username string `json:"username"`
+		// Key of a Secret in the VMI namespace which holds the user's password
+		PasswordSecret corev1.SecretKeySelector `json:"passwordSecret"`
+		BackendRoles   []string                 `json:"backendRoles,omitempty"`
+	}
+
+	// IndexMigrationSettings limits the load the reindex of old indices puts on the cluster
+	IndexMigrationSettings struct {
+		// Documents reindexed per second by each index migration, unthrottled when unset
+		// +kubebuilder:validation:Minimum:=1
+		RequestsPerSecond *int32 `json:"requestsPerSecond,omitempty"`
+		// Documents reindexed per second by each index migration while the cluster health is not green
+		// +kubebuilder:validation:Minimum:=1
+		DegradedRequestsPerSecond *int32 `json:"degradedRequestsPerSecond,omitempty"`
+		// Number of slices each reindex is divided into, either auto or a number
+		// +kubebuilder:validation:Pattern:=^(auto|[1-9][0-9]*)$
+		Slices string `json:"slices,omitempty"`
+		// Maximum number of indices reindexed at the same time, defaults to 1
+		// +kubebuilder:validation:Minimum:=1
+		MaxConcurrentIndices int32 `json:"maxConcurrentIndices,omitempty"`
+		// Number of documents in each reindex batch, defaults to the OpenSearch batch size of 1000
+		// +kubebuilder:validation:Minimum:=1
+		BatchSize *int32 `json:"batchSize,omitempty"`
+		// Ordered rules which map old indices to data streams. The first matching rule applies,
+		// and the built-in Verrazzano rules are evaluated after these rules.
+		Rules []IndexMigrationRule `json:"rules,omitempty"`
+		// Report what the migration would do in a ConfigMap, without reindexing or deleting any index
+		DryRun bool `json:"dryRun,omitempty"`
+	}
+
+	// IndexMigrationRule maps old indices to the data stream they are reindexed into
+	IndexMigrationRule struct {
+		// Regular expression which must match the whole index name. Hidden and system indices, whose names start
+		// with a dot, are never migrated.
+		Source string `json:"source"`
+		// Name of the destination data stream, where ${1} is replaced by the first capture group of the source.
+		// Matching indices are not migrated when empty.
+		Destination string `json:"destination,omitempty"`
+		// Regular expressions of index names the rule does not apply to
+		Exclude []string `json:"exclude,omitempty"`
+		// Age of the oldest documents which are reindexed, e.g., 7d.
+		// Defaults to the minimum index age of the ISM policy matching the destination.
+		// +kubebuilder:validation:Pattern:=^[0-9]+(d|h|m|s)$
+		Retention *string `json:"retention,omitempty"`
 	}
 
 	// ElasticsearchNode Type details
@@ -138,6 +365,62 @@ type (
 		Resources Resources  `json:"resources,omitempty"`
 		Storage   *Storage   `json:"storage,omitempty"`
 		Roles     []NodeRole `json:"roles,omitempty"`
+		// Opt-in policy which grows the storage of data nodes as their disks fill up. The grown size is recorded in the
+		// status, and the larger of the spec and status sizes is applied. Master eligible nodes run as StatefulSets and
+		// cannot be grown, so the policy is rejected on them.
+		AutoGrow *StorageAutoGrow `json:"autoGrow,omitempty"`
+		// Plugins installed on the nodes of this pool, in addition to the plugins of all nodes
+		Plugins []OpenSearchPlugin `json:"plugins,omitempty"`
+		// Tier of the nodes in this pool, e.g., hot or warm. Exported as the node.attr.tier attribute.
+		// +kubebuilder:validation:Pattern:=`^[a-z0-9_-]+$`
+		Tier string `json:"tier,omitempty"`
+		// Custom attributes of the nodes in this pool, exported as node.attr.<name> attributes
+		Attributes map[string]string `json:"attributes,omitempty"`
+	}
+
+	// RemoteCluster is an OpenSearch cluster searched through cross-cluster search
+	RemoteCluster struct {
+		// Name of the remote cluster, used as the index prefix in cross-cluster searches, e.g., managed1:verrazzano-system
+		// +kubebuilder:validation:Pattern:=^[a-z0-9-]+$
+		Name string `json:"name"`
+		// Transport addresses of the remote cluster's seed nodes, e.g., opensearch.managed1.example.com:9300
+		// +kubebuilder:validation:MinItems:=1
+		Seeds []string `json:"seeds"`
+		// Searches skip the remote cluster while it is unavailable, instead of failing
+		SkipUnavailable bool `json:"skipUnavailable,omitempty"`
+		// CA certificate of the remote cluster's transport layer. It is trusted by the transport layer of the OpenSearch
+		// nodes along with the transport CA certificates of the image, once the nodes restart.
+		CASecret *corev1.SecretKeySelector `json:"caSecret,omitempty"`
+	}
+
+	// OpenSearchPlugin is installed before OpenSearch starts, either by name or from a URL
+	OpenSearchPlugin struct {
+		// Name of an official OpenSearch plugin, e.g., analysis-icu. Its checksum is verified by opensearch-plugin.
+		// +kubebuilder:validation:Pattern:=^[a-z0-9-]+$
+		Name string `json:"name,omitempty"`
+		// URL of a plugin zip file, used instead of the name
+		// +kubebuilder:validation:Pattern:=`^https?://`
+		URL string `json:"url,omitempty"`
+		// SHA-512 checksum of the plugin zip file, required with a URL
+		// +kubebuilder:validation:Pattern:=`^[0-9a-f]{128}$`
+		Checksum string `json:"checksum,omitempty"`
+	}
+
+	// StorageAutoGrow grows the node's PVCs by a step whenever disk usage crosses the threshold
+	StorageAutoGrow struct {
+		// Disk usage percentage which triggers growth, defaults to 80
+		// +kubebuilder:validation:Minimum:=1
+		// +kubebuilder:validation:Maximum:=99
+		ThresholdPercent int32 `json:"thresholdPercent,omitempty"`
+		// Amount of storage added on each growth, e.g., 10Gi
+		// +kubebuilder:validation:Pattern:=^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$
+		Step string `json:"step"`
+		// Size the storage will not grow beyond, e.g., 500Gi
+		// +kubebuilder:validation:Pattern:=^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$
+		MaxSize string `json:"maxSize"`
+		// Minimum time between two growths of the storage, defaults to 1h
+		// +kubebuilder:validation:Pattern:=^[0-9]+(h|m|s)$
+		MinInterval string `json:"minInterval,omitempty"`
 	}
 
 	//IndexManagementPolicy Defines a policy for managing indices
@@ -150,6 +433,19 @@ type (
 		// +kubebuilder:validation:Pattern:=^[0-9]+(d|h|m|s|ms|micros|nanos)$
 		MinIndexAge *string        `json:"minIndexAge,omitempty"`
 		Rollover    RolloverPolicy `json:"rollover,omitempty"`
+		// Node tiers the indices are moved to as they age, in ascending order of their minimum index age, which must be
+		// smaller than the minimum age before deletion. The tiers ingest and delete are reserved for the policy.
+		Tiers []TierAllocationPolicy `json:"tiers,omitempty"`
+	}
+
+	//TierAllocationPolicy moves indices to the nodes of a tier once they reach a minimum age
+	TierAllocationPolicy struct {
+		// Tier of the nodes the indices are moved to, e.g., warm
+		// +kubebuilder:validation:Pattern:=`^[a-z0-9_-]+$`
+		Tier string `json:"tier"`
+		// Minimum age of an index before it is moved to the tier
+		// +kubebuilder:validation:Pattern:=^[0-9]+(d|h|m|s|ms|micros|nanos)$
+		MinIndexAge string `json:"minIndexAge"`
 	}
 
 	//RolloverPolicy Settings for Index Management rollover
@@ -169,7 +465,48 @@ type (
 	Kibana struct {
 		Enabled   bool      `json:"enabled" yaml:"enabled"`
 		Resources Resources `json:"resources,omitempty"`
-		Replicas  int32     `json:"replicas,omitempty"`
+		// Number of OpenSearch Dashboards pods. Several pods are rolled out one at a time when their settings change,
+		// and are all recreated when their image changes, so that pods of different versions never run together.
+		Replicas int32 `json:"replicas,omitempty"`
+		// Settings of opensearch_dashboards.yml, such as server.basePath or map.tilemap.url. Values are parsed as YAML,
+		// and used as strings when they are not valid YAML. The settings are merged over the opensearch_dashboards.yml
+		// of the image, and changing them rolls out the OpenSearch Dashboards pods.
+		Config map[string]string `json:"config,omitempty"`
+		// Title of the default index pattern of OpenSearch Dashboards, the Verrazzano system data stream by default
+		DefaultIndexPattern string `json:"defaultIndexPattern,omitempty"`
+		// Tenants of OpenSearch Dashboards, which keep the saved objects of each team apart
+		Tenants []DashboardsTenant `json:"tenants,omitempty"`
+		// Scheduled export of the saved objects of OpenSearch Dashboards
+		Backup *SavedObjectsBackup `json:"backup,omitempty"`
+	}
+
+	// SavedObjectsBackup exports the saved objects of the global tenant and of each tenant of the VMI on a schedule.
+	// Each export is kept in its own ConfigMap or Secret, which is not deleted with the VMI.
+	SavedObjectsBackup struct {
+		// Time between exports, 24h by default
+		// +kubebuilder:validation:Pattern:=^[0-9]+(h|m)$
+		Interval string `json:"interval,omitempty"`
+		// Kind of the resources holding the exports, ConfigMap by default
+		// +kubebuilder:validation:Enum=ConfigMap;Secret
+		Kind string `json:"kind,omitempty"`
+		// Number of exports kept, 7 by default
+		// +kubebuilder:validation:Minimum:=1
+		Retain int32 `json:"retain,omitempty"`
+		// Name of the export to restore, which is imported again whenever the name changes
+		Restore string `json:"restore,omitempty"`
+	}
+
+	// DashboardsTenant is an OpenSearch Dashboards tenant, whose saved objects are only visible to the members of its groups
+	DashboardsTenant struct {
+		Name string `json:"name"`
+		// Keycloak groups whose members may read and change the saved objects of the tenant
+		Groups []string `json:"groups,omitempty"`
+		// Keycloak groups whose members may only read the saved objects of the tenant
+		ReadOnlyGroups []string `json:"readOnlyGroups,omitempty"`
+		// Seed the tenant with the default index patterns
+		SeedIndexPatterns bool `json:"seedIndexPatterns,omitempty"`
+		// Seed the tenant with the saved objects of the ConfigMaps labelled for the VMI
+		SeedSavedObjects bool `json:"seedSavedObjects,omitempty"`
 	}
 
 	// API details
@@ -184,6 +521,288 @@ type (
 		State        string       `json:"state" yaml:"state"`
 		CreationTime *metav1.Time `json:"creationTime,omitempty" yaml:"creationTime"`
 		Hash         uint32       `json:"hash"`
+		// Observed state of the OpenSearch cluster
+		Elasticsearch ElasticsearchStatus `json:"elasticsearch,omitempty"`
+		// Observed state of OpenSearch Dashboards
+		Kibana KibanaStatus `json:"kibana,omitempty"`
+		// Observed state of the Prometheus configuration
+		Prometheus PrometheusStatus `json:"prometheus,omitempty"`
+	}
+
+	// RemoteWrite sends the samples scraped by Prometheus to a remote storage. Credentials and certificates are mounted
+	// into Prometheus at /etc/prometheus/remote-write/<name>, so they are not written to the Prometheus configuration.
+	RemoteWrite struct {
+		// +kubebuilder:validation:Pattern:=^[a-z0-9-]+$
+		Name string `json:"name"`
+		// +kubebuilder:validation:Pattern:=`^https?://`
+		URL       string                `json:"url"`
+		BasicAuth *RemoteWriteBasicAuth `json:"basicAuth,omitempty"`
+		// Key of a Secret in the VMI namespace which holds the bearer token, exclusive with basicAuth
+		BearerTokenSecret *corev1.SecretKeySelector `json:"bearerTokenSecret,omitempty"`
+		TLS               *RemoteWriteTLS           `json:"tls,omitempty"`
+		QueueConfig       *RemoteWriteQueueConfig   `json:"queueConfig,omitempty"`
+		// Relabel configs applied to the samples before they are sent, e.g., to drop series
+		WriteRelabelConfigs []RelabelConfig `json:"writeRelabelConfigs,omitempty"`
+	}
+
+	// RemoteWriteBasicAuth authenticates to the remote storage with a username and password
+	RemoteWriteBasicAuth struct {
+		Username string `json:"username"`
+		// Key of a Secret in the VMI namespace which holds the password
+		PasswordSecret corev1.SecretKeySelector `json:"passwordSecret"`
+	}
+
+	// RemoteWriteTLS configures the TLS connection to the remote storage
+	RemoteWriteTLS struct {
+		// Key of a Secret in the VMI namespace which holds the CA certificate of the remote storage
+		CASecret *corev1.SecretKeySelector `json:"caSecret,omitempty"`
+		// Keys of Secrets in the VMI namespace which hold the client certificate and key
+		CertSecret         *corev1.SecretKeySelector `json:"certSecret,omitempty"`
+		KeySecret          *corev1.SecretKeySelector `json:"keySecret,omitempty"`
+		ServerName         string                    `json:"serverName,omitempty"`
+		InsecureSkipVerify bool                      `json:"insecureSkipVerify,omitempty"`
+	}
+
+	// RemoteWriteQueueConfig tunes the queue of samples sent to the remote storage. Unset fields keep the Prometheus defaults.
+	RemoteWriteQueueConfig struct {
+		Capacity          int32 `json:"capacity,omitempty"`
+		MinShards         int32 `json:"minShards,omitempty"`
+		MaxShards         int32 `json:"maxShards,omitempty"`
+		MaxSamplesPerSend int32 `json:"maxSamplesPerSend,omitempty"`
+		// Durations, e.g., 5s or 100ms
+		// +kubebuilder:validation:Pattern:=^[0-9]+(ms|s|m)$
+		BatchSendDeadline string `json:"batchSendDeadline,omitempty"`
+		// +kubebuilder:validation:Pattern:=^[0-9]+(ms|s|m)$
+		MinBackoff string `json:"minBackoff,omitempty"`
+		// +kubebuilder:validation:Pattern:=^[0-9]+(ms|s|m)$
+		MaxBackoff string `json:"maxBackoff,omitempty"`
+	}
+
+	// RelabelConfig is a Prometheus relabel config
+	RelabelConfig struct {
+		SourceLabels []string `json:"sourceLabels,omitempty"`
+		Separator    string   `json:"separator,omitempty"`
+		Regex        string   `json:"regex,omitempty"`
+		Modulus      int64    `json:"modulus,omitempty"`
+		TargetLabel  string   `json:"targetLabel,omitempty"`
+		Replacement  string   `json:"replacement,omitempty"`
+		// +kubebuilder:validation:Enum=replace;keep;drop;hashmod;labelmap;labeldrop;labelkeep
+		Action string `json:"action,omitempty"`
+	}
+
+	// PrometheusStatus tracks the Prometheus configuration managed by the operator
+	PrometheusStatus struct {
+		// Jobs of the additional scrape configs merged into the Prometheus configuration, so they are removed once dropped
+		AdditionalScrapeConfigJobs []string `json:"additionalScrapeConfigJobs,omitempty"`
+		// Error which kept the additional scrape configs from being merged
+		AdditionalScrapeConfigsError string `json:"additionalScrapeConfigsError,omitempty"`
+		// Error which kept the remote write configs from being rendered. While it is set, the remote write configs rendered before and their
+		// mounted Secrets are kept.
+		RemoteWriteError string `json:"remoteWriteError,omitempty"`
+	}
+
+	// KibanaStatus tracks the OpenSearch Dashboards state managed by the operator
+	KibanaStatus struct {
+		// Saved objects imported from ConfigMaps, so they are imported again when changed, and deleted once removed
+		SavedObjects []SavedObjectStatus `json:"savedObjects,omitempty"`
+		// Tenants created by the operator, so they are deleted once removed from the spec
+		Tenants []TenantStatus `json:"tenants,omitempty"`
+		// Exports and restores of the saved objects
+		Backup *SavedObjectsBackupStatus `json:"backup,omitempty"`
+	}
+
+	// SavedObjectsBackupStatus tracks the exports and restores of the saved objects
+	SavedObjectsBackupStatus struct {
+		// Name of the most recent export
+		LastExport string `json:"lastExport,omitempty"`
+		// Time of the most recent export
+		LastExportTime *metav1.Time `json:"lastExportTime,omitempty"`
+		// Reason the most recent export failed, e.g., because it exceeded the size limit. The export is attempted again
+		// once the backup interval has passed.
+		LastExportError string `json:"lastExportError,omitempty"`
+		// Time of the most recent failed export
+		LastExportErrorTime *metav1.Time `json:"lastExportErrorTime,omitempty"`
+		// Name of the most recently restored export
+		Restored string `json:"restored,omitempty"`
+	}
+
+	// TenantStatus is an OpenSearch Dashboards tenant created by the operator
+	TenantStatus struct {
+		Name string `json:"name"`
+		// Hash of the saved objects seeded into the tenant, so objects added later are seeded as well
+		SeedHash string `json:"seedHash,omitempty"`
+	}
+
+	// SavedObjectStatus is an OpenSearch Dashboards saved object imported from a ConfigMap
+	SavedObjectStatus struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+		// ConfigMap and key holding the object, as <name>/<key>
+		Source string `json:"source"`
+		// Hash of the imported object
+		Hash string `json:"hash"`
+	}
+
+	// ElasticsearchStatus tracks the OpenSearch cluster state managed by the operator
+	ElasticsearchStatus struct {
+		// Effective persistent cluster settings applied by the operator
+		ClusterSettings map[string]string `json:"clusterSettings,omitempty"`
+		// Cross-cluster index patterns created in OpenSearch Dashboards, so they are deleted once removed from the spec
+		CrossClusterIndexPatterns []string `json:"crossClusterIndexPatterns,omitempty"`
+		// True while the operator manages Alerting monitors or notification channels, so they are deleted once removed from the spec
+		AlertingManaged bool `json:"alertingManaged,omitempty"`
+		// Progress of the node currently being drained before removal
+		Drain *NodeDrainStatus `json:"drain,omitempty"`
+		// Master eligible nodes excluded from the voting configuration before removal
+		VotingConfigExclusions []string `json:"votingConfigExclusions,omitempty"`
+		// Node currently being restarted, while shard allocation is restricted to primaries
+		Restart *NodeRestartStatus `json:"restart,omitempty"`
+		// Progress of the most recent OpenSearch version upgrade
+		Upgrade *UpgradeStatus `json:"upgrade,omitempty"`
+		// Health of the OpenSearch cluster, including the reasons shards cannot be allocated
+		Health *ClusterHealthStatus `json:"health,omitempty"`
+		// Most recent automatic storage growth of each data node pool
+		StorageGrowth []StorageGrowthStatus `json:"storageGrowth,omitempty"`
+		// Progress of the migration of old indices to data streams. Once the other indices are migrated, the migrations
+		// which failed are kept, and are not retried.
+		IndexMigrations []IndexMigrationStatus `json:"indexMigrations,omitempty"`
+		// Summary of the migration dry run report
+		MigrationReport *IndexMigrationReportSummary `json:"migrationReport,omitempty"`
+	}
+
+	// IndexMigrationReportSummary summarizes the migration dry run report
+	IndexMigrationReportSummary struct {
+		// Name of the ConfigMap holding the report
+		ConfigMap string `json:"configMap"`
+		// Number of indices which would be migrated
+		Indices int `json:"indices"`
+		// Number of documents in the indices
+		Documents int64 `json:"documents"`
+		// Number of documents outside of the retention window, which would not be reindexed
+		DroppedDocuments int64 `json:"droppedDocuments"`
+		// Total size of the indices in bytes
+		SizeBytes int64 `json:"sizeBytes"`
+		// Hash of the migrations and migration settings the report was built from. The report is only built again
+		// when they change.
+		Hash string `json:"hash,omitempty"`
+	}
+
+	// IndexMigrationStatus tracks the reindex of an old index into a data stream
+	IndexMigrationStatus struct {
+		// Name of the source index
+		Index string `json:"index"`
+		// Name of the destination data stream
+		DataStream string `json:"dataStream"`
+		// Phase of the migration
+		Phase IndexMigrationPhase `json:"phase"`
+		// ID of the OpenSearch reindex task
+		TaskID string `json:"taskID,omitempty"`
+		// Number of source documents to reindex
+		SourceDocs int64 `json:"sourceDocs"`
+		// Number of documents reindexed so far
+		ReindexedDocs int64 `json:"reindexedDocs"`
+		// Number of reindex attempts
+		Attempts int `json:"attempts"`
+		// Documents per second the reindex task is throttled to, unthrottled when unset
+		RequestsPerSecond *int32 `json:"requestsPerSecond,omitempty"`
+		// Age of the oldest documents which are reindexed, all documents are reindexed when empty
+		Retention string `json:"retention,omitempty"`
+		// Reason of the most recent failed attempt
+		Message string `json:"message,omitempty"`
+		// Time the most recent attempt was started
+		StartTime *metav1.Time `json:"startTime,omitempty"`
+	}
+
+	// StorageGrowthStatus records the most recent automatic storage growth of a node pool
+	StorageGrowthStatus struct {
+		// Name of the node pool
+		Node string `json:"node"`
+		// Storage size after the growth. It is applied while it is larger than the size in the spec.
+		Size string `json:"size"`
+		// Disk usage percentage which triggered the growth
+		DiskPercent int `json:"diskPercent"`
+		// Time of the growth
+		Time *metav1.Time `json:"time,omitempty"`
+		// Reason the autogrow policy of the node pool is rejected
+		Message string `json:"message,omitempty"`
+	}
+
+	// ClusterHealthStatus summarizes the OpenSearch cluster health
+	ClusterHealthStatus struct {
+		// Cluster health, one of green, yellow or red
+		Status string `json:"status"`
+		// Number of shards not allocated to any node
+		UnassignedShards int `json:"unassignedShards"`
+		// Number of shards being initialized
+		InitializingShards int `json:"initializingShards"`
+		// Number of shards moving between nodes
+		RelocatingShards int `json:"relocatingShards"`
+		// Number of cluster level changes not yet executed
+		PendingTasks int `json:"pendingTasks"`
+		// Reasons unassigned shards cannot be allocated, as explained by the cluster
+		BlockingReasons []string `json:"blockingReasons,omitempty"`
+		// Time the health or the blocking reasons last changed
+		LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+	}
+
+	// NodeDrainStatus tracks the removal of an OpenSearch node from the cluster
+	NodeDrainStatus struct {
+		// Name of the OpenSearch node
+		Node string `json:"node"`
+		// Name of the Deployment or StatefulSet the node belongs to
+		Owner string `json:"owner"`
+		// Phase of the drain
+		Phase NodeDrainPhase `json:"phase"`
+		// Number of shards still allocated to the node
+		Shards int `json:"shards"`
+		// Time the drain was started
+		StartTime *metav1.Time `json:"startTime,omitempty"`
+		// Why the drain is stalled
+		Message string `json:"message,omitempty"`
+	}
+
+	// NodeRestartStatus tracks the rolling restart of an OpenSearch node
+	NodeRestartStatus struct {
+		// Name of the Deployment or StatefulSet the node belongs to
+		Owner string `json:"owner"`
+		// Name of the restarted StatefulSet pod
+		Pod string `json:"pod,omitempty"`
+		// Generation of the restarted Deployment
+		Generation int64 `json:"generation,omitempty"`
+		// Time the restart was started
+		StartTime *metav1.Time `json:"startTime,omitempty"`
+	}
+
+	// UpgradeStatus tracks the rolling upgrade of OpenSearch nodes to a new version
+	UpgradeStatus struct {
+		// Phase of the upgrade
+		Phase UpgradePhase `json:"phase"`
+		// Version the cluster is upgraded from
+		FromVersion string `json:"fromVersion"`
+		// Version the cluster is upgraded to
+		TargetVersion string `json:"targetVersion"`
+		// Image the nodes ran before the upgrade, which is used to roll back
+		FromImage string `json:"fromImage,omitempty"`
+		// Image the nodes are upgraded to
+		TargetImage string `json:"targetImage"`
+		// Reason for the current phase
+		Message string `json:"message,omitempty"`
+		// Time the upgrade was started
+		StartTime *metav1.Time `json:"startTime,omitempty"`
+		// Upgrade progress of each node
+		Nodes []NodeUpgradeStatus `json:"nodes,omitempty"`
+	}
+
+	// NodeUpgradeStatus is the upgrade progress of an OpenSearch node
+	NodeUpgradeStatus struct {
+		// Name of the OpenSearch node
+		Name string `json:"name"`
+		// Roles of the OpenSearch node
+		Roles []string `json:"roles,omitempty"`
+		// Version the node is running
+		Version string `json:"version"`
+		// Whether the node is running the target version
+		Upgraded bool `json:"upgraded"`
 	}
 
 	// Storage details
@@ -167,6 +167,21 @@ const PrometheusConfig = "prometheus-config"
 // PrometheusConfigVersions Prometheus config versions
 const PrometheusConfigVersions = "prometheus-config-versions"
 
+// OpenSearchDashboardsConfig OpenSearch Dashboards config
+const OpenSearchDashboardsConfig = "osd-config"
+
+// OpenSearchDashboardsYaml OpenSearch Dashboards config file
+const OpenSearchDashboardsYaml = "opensearch_dashboards.yml"
+
+// OpenSearchDashboardsConfigPath OpenSearch Dashboards config file of the image
+const OpenSearchDashboardsConfigPath = "/usr/share/opensearch-dashboards/config/" + OpenSearchDashboardsYaml
+
+// OpenSearchDashboardsVMIConfigMountPath OpenSearch Dashboards config mountpath of the VMI settings, which are merged over the config file of the image
+const OpenSearchDashboardsVMIConfigMountPath = "/usr/share/opensearch-dashboards/config/vmi"
+
+// OpenSearchDashboardsConfigHashAnnotation pod annotation holding the hash of the OpenSearch Dashboards config file, which rolls out the pods when changed
+const OpenSearchDashboardsConfigHashAnnotation = "verrazzano.io/osd-config-hash"
+
 // PrometheusConfigMountPath Prometheus config mountpath
 const PrometheusConfigMountPath = "/etc/prometheus/config"
 
@@ -254,3 +269,9 @@ const ComponentOpenSearchValue = "opensearch"
 
 //NodeGroupLabel for specifying a node's group
 const NodeGroupLabel = "node-group"
+
+//DashboardsSavedObjectsLabel - the label of ConfigMaps holding OpenSearch Dashboards saved objects, whose value is the VMI name
+const DashboardsSavedObjectsLabel = "verrazzano.io/dashboards-saved-objects"
+
+//DashboardsSavedObjectsBackupLabel - the label of the ConfigMaps or Secrets holding exports of OpenSearch Dashboards saved objects, whose value is the VMI name
+const DashboardsSavedObjectsBackupLabel = "verrazzano.io/dashboards-saved-objects-backup"
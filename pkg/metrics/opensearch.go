@@ -0,0 +1,132 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch"
+	"sync"
+)
+
+const namespace = "vmo_opensearch"
+
+var (
+	vmiLabels  = []string{"namespace", "vmi"}
+	nodeLabels = []string{"namespace", "vmi", "node"}
+
+	upDesc              = newDesc("stats_up", "Whether the last collection of OpenSearch statistics succeeded", vmiLabels)
+	shardsDesc          = newDesc("shards", "Number of shards by state", append(vmiLabels, "state"))
+	dataStreamSizeDesc  = newDesc("data_stream_size_bytes", "Store size of a data stream, including replicas", append(vmiLabels, "data_stream"))
+	heapUsedDesc        = newDesc("jvm_heap_used_bytes", "JVM heap used by a node", nodeLabels)
+	heapMaxDesc         = newDesc("jvm_heap_max_bytes", "Maximum JVM heap of a node", nodeLabels)
+	gcCountDesc         = newDesc("jvm_gc_collections_total", "Number of garbage collections of a node", append(nodeLabels, "gc"))
+	gcTimeDesc          = newDesc("jvm_gc_collection_seconds_total", "Time spent in garbage collections of a node", append(nodeLabels, "gc"))
+	diskTotalDesc       = newDesc("fs_total_bytes", "Total disk space of a node", nodeLabels)
+	diskAvailableDesc   = newDesc("fs_available_bytes", "Available disk space of a node", nodeLabels)
+	indexingTotalDesc   = newDesc("indexing_documents_total", "Number of documents indexed by a node", nodeLabels)
+	indexingTimeDesc    = newDesc("indexing_seconds_total", "Time spent indexing documents on a node", nodeLabels)
+	searchQueryDesc     = newDesc("search_queries_total", "Number of search queries run by a node", nodeLabels)
+	searchQueryTimeDesc = newDesc("search_query_seconds_total", "Time spent running search queries on a node", nodeLabels)
+)
+
+type (
+	//OpenSearchCollector exports the most recent OpenSearch statistics of each VMI as Prometheus metrics
+	OpenSearchCollector struct {
+		lock  sync.Mutex
+		stats map[VMIKey]*opensearch.ClusterStats
+	}
+
+	//VMIKey identifies a VMI
+	VMIKey struct {
+		Namespace string
+		Name      string
+	}
+)
+
+func newDesc(name, help string, labels []string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(namespace, "", name), help, labels, nil)
+}
+
+//NewOpenSearchCollector returns a collector without statistics
+func NewOpenSearchCollector() *OpenSearchCollector {
+	return &OpenSearchCollector{
+		stats: map[VMIKey]*opensearch.ClusterStats{},
+	}
+}
+
+//Update replaces the statistics of all VMIs. A VMI whose statistics could not be collected has nil statistics,
+// and VMIs that are missing are no longer exported.
+func (c *OpenSearchCollector) Update(stats map[VMIKey]*opensearch.ClusterStats) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.stats = stats
+}
+
+//Describe implements prometheus.Collector
+func (c *OpenSearchCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{upDesc, shardsDesc, dataStreamSizeDesc, heapUsedDesc, heapMaxDesc, gcCountDesc,
+		gcTimeDesc, diskTotalDesc, diskAvailableDesc, indexingTotalDesc, indexingTimeDesc, searchQueryDesc, searchQueryTimeDesc} {
+		ch <- desc
+	}
+}
+
+//Collect implements prometheus.Collector
+func (c *OpenSearchCollector) Collect(ch chan<- prometheus.Metric) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for key, stats := range c.stats {
+		if stats == nil {
+			ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, 0, key.Namespace, key.Name)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, 1, key.Namespace, key.Name)
+		collectClusterStats(ch, key, stats)
+		for _, node := range stats.Nodes {
+			collectNodeStats(ch, key, node)
+		}
+	}
+}
+
+func collectClusterStats(ch chan<- prometheus.Metric, key VMIKey, stats *opensearch.ClusterStats) {
+	if health := stats.Health; health != nil {
+		for state, count := range map[string]int{
+			"active":         health.ActiveShards,
+			"active_primary": health.ActivePrimaryShards,
+			"relocating":     health.RelocatingShards,
+			"initializing":   health.InitializingShards,
+			"unassigned":     health.UnassignedShards,
+		} {
+			ch <- prometheus.MustNewConstMetric(shardsDesc, prometheus.GaugeValue, float64(count), key.Namespace, key.Name, state)
+		}
+	}
+	for dataStream, size := range stats.DataStreamSizes {
+		ch <- prometheus.MustNewConstMetric(dataStreamSizeDesc, prometheus.GaugeValue, float64(size), key.Namespace, key.Name, dataStream)
+	}
+}
+
+func collectNodeStats(ch chan<- prometheus.Metric, key VMIKey, node opensearch.NodeStats) {
+	gauge := func(desc *prometheus.Desc, value int64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(value), key.Namespace, key.Name, node.Name)
+	}
+	// OpenSearch reports cumulative totals, so rates are derived from counters
+	counter := func(desc *prometheus.Desc, value float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, value, append([]string{key.Namespace, key.Name, node.Name}, labels...)...)
+	}
+	gauge(heapUsedDesc, node.JVM.Mem.HeapUsedInBytes)
+	gauge(heapMaxDesc, node.JVM.Mem.HeapMaxInBytes)
+	gauge(diskTotalDesc, node.FS.Total.TotalInBytes)
+	gauge(diskAvailableDesc, node.FS.Total.AvailableInBytes)
+	for gc, gcStats := range node.JVM.GC.Collectors {
+		counter(gcCountDesc, float64(gcStats.CollectionCount), gc)
+		counter(gcTimeDesc, millisToSeconds(gcStats.CollectionTimeInMillis), gc)
+	}
+	counter(indexingTotalDesc, float64(node.Indices.Indexing.IndexTotal))
+	counter(indexingTimeDesc, millisToSeconds(node.Indices.Indexing.IndexTimeInMillis))
+	counter(searchQueryDesc, float64(node.Indices.Search.QueryTotal))
+	counter(searchQueryTimeDesc, millisToSeconds(node.Indices.Search.QueryTimeInMillis))
+}
+
+func millisToSeconds(millis int64) float64 {
+	return float64(millis) / 1000
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch"
+	"strings"
+	"testing"
+)
+
+// TestOpenSearchCollector Tests the OpenSearch statistics exported as metrics
+// GIVEN statistics of a VMI which could be collected, and of a VMI which could not
+// WHEN the metrics are collected
+// THEN the statistics are exported with VMI and node labels, and the unavailable VMI is exported as down
+func TestOpenSearchCollector(t *testing.T) {
+	node := opensearch.NodeStats{Name: "vmi-system-es-master-0"}
+	node.JVM.Mem.HeapUsedInBytes = 512
+	node.JVM.GC.Collectors = map[string]opensearch.GCStats{"old": {CollectionCount: 3, CollectionTimeInMillis: 1500}}
+	node.Indices.Search.QueryTotal = 7
+
+	collector := NewOpenSearchCollector()
+	collector.Update(map[VMIKey]*opensearch.ClusterStats{
+		{Namespace: "verrazzano-system", Name: "system"}: {
+			Health:          &opensearch.ClusterHealth{UnassignedShards: 2},
+			Nodes:           []opensearch.NodeStats{node},
+			DataStreamSizes: map[string]int64{"verrazzano-system": 2048},
+		},
+		{Namespace: "team", Name: "logs"}: nil,
+	})
+
+	expected := `
+# HELP vmo_opensearch_data_stream_size_bytes Store size of a data stream, including replicas
+# TYPE vmo_opensearch_data_stream_size_bytes gauge
+vmo_opensearch_data_stream_size_bytes{data_stream="verrazzano-system",namespace="verrazzano-system",vmi="system"} 2048
+# HELP vmo_opensearch_jvm_gc_collection_seconds_total Time spent in garbage collections of a node
+# TYPE vmo_opensearch_jvm_gc_collection_seconds_total counter
+vmo_opensearch_jvm_gc_collection_seconds_total{gc="old",namespace="verrazzano-system",node="vmi-system-es-master-0",vmi="system"} 1.5
+# HELP vmo_opensearch_jvm_heap_used_bytes JVM heap used by a node
+# TYPE vmo_opensearch_jvm_heap_used_bytes gauge
+vmo_opensearch_jvm_heap_used_bytes{namespace="verrazzano-system",node="vmi-system-es-master-0",vmi="system"} 512
+# HELP vmo_opensearch_search_queries_total Number of search queries run by a node
+# TYPE vmo_opensearch_search_queries_total counter
+vmo_opensearch_search_queries_total{namespace="verrazzano-system",node="vmi-system-es-master-0",vmi="system"} 7
+# HELP vmo_opensearch_stats_up Whether the last collection of OpenSearch statistics succeeded
+# TYPE vmo_opensearch_stats_up gauge
+vmo_opensearch_stats_up{namespace="team",vmi="logs"} 0
+vmo_opensearch_stats_up{namespace="verrazzano-system",vmi="system"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"vmo_opensearch_data_stream_size_bytes",
+		"vmo_opensearch_jvm_gc_collection_seconds_total",
+		"vmo_opensearch_jvm_heap_used_bytes",
+		"vmo_opensearch_search_queries_total",
+		"vmo_opensearch_stats_up",
+	))
+	// one shard metric for each state
+	assert.Equal(t, 5, testutil.CollectAndCount(collector, "vmo_opensearch_shards"))
+
+	// VMIs which no longer exist are not exported
+	collector.Update(map[VMIKey]*opensearch.ClusterStats{})
+	assert.Equal(t, 0, testutil.CollectAndCount(collector))
+}
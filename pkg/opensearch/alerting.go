@@ -0,0 +1,542 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	"hash/fnv"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	Monitor struct {
+		Type        string                 `json:"type"`
+		MonitorType string                 `json:"monitor_type"`
+		Name        string                 `json:"name"`
+		Enabled     bool                   `json:"enabled"`
+		Schedule    MonitorSchedule        `json:"schedule"`
+		Inputs      []MonitorInput         `json:"inputs"`
+		Triggers    []MonitorTrigger       `json:"triggers"`
+		UIMetadata  map[string]interface{} `json:"ui_metadata,omitempty"`
+	}
+
+	MonitorSchedule struct {
+		Period *SchedulePeriod `json:"period,omitempty"`
+		Cron   *ScheduleCron   `json:"cron,omitempty"`
+	}
+
+	SchedulePeriod struct {
+		Interval int    `json:"interval"`
+		Unit     string `json:"unit"`
+	}
+
+	ScheduleCron struct {
+		Expression string `json:"expression"`
+		Timezone   string `json:"timezone"`
+	}
+
+	MonitorInput struct {
+		Search MonitorSearch `json:"search"`
+	}
+
+	MonitorSearch struct {
+		Indices []string               `json:"indices"`
+		Query   map[string]interface{} `json:"query"`
+	}
+
+	MonitorTrigger struct {
+		Name      string           `json:"name"`
+		Severity  string           `json:"severity"`
+		Condition TriggerCondition `json:"condition"`
+		Actions   []TriggerAction  `json:"actions"`
+	}
+
+	TriggerCondition struct {
+		Script Script `json:"script"`
+	}
+
+	Script struct {
+		Source string `json:"source"`
+		Lang   string `json:"lang,omitempty"`
+	}
+
+	TriggerAction struct {
+		Name            string          `json:"name"`
+		DestinationID   string          `json:"destination_id"`
+		MessageTemplate Script          `json:"message_template"`
+		SubjectTemplate *Script         `json:"subject_template,omitempty"`
+		ThrottleEnabled bool            `json:"throttle_enabled"`
+		Throttle        *ActionThrottle `json:"throttle,omitempty"`
+	}
+
+	ActionThrottle struct {
+		Value int    `json:"value"`
+		Unit  string `json:"unit"`
+	}
+
+	MonitorSearchResponse struct {
+		Hits struct {
+			Hits []MonitorHit `json:"hits"`
+		} `json:"hits"`
+	}
+
+	MonitorHit struct {
+		ID     string `json:"_id"`
+		Source struct {
+			Type       string                 `json:"type"`
+			Name       string                 `json:"name"`
+			UIMetadata map[string]interface{} `json:"ui_metadata"`
+		} `json:"_source"`
+	}
+
+	NotificationConfigList struct {
+		ConfigList []NotificationConfigItem `json:"config_list"`
+	}
+
+	NotificationConfigItem struct {
+		ConfigID string             `json:"config_id,omitempty"`
+		Config   NotificationConfig `json:"config"`
+	}
+
+	NotificationConfig struct {
+		Name        string             `json:"name"`
+		Description string             `json:"description"`
+		ConfigType  string             `json:"config_type"`
+		IsEnabled   bool               `json:"is_enabled"`
+		Slack       *URLConfig         `json:"slack,omitempty"`
+		Webhook     *URLConfig         `json:"webhook,omitempty"`
+		Email       *EmailConfig       `json:"email,omitempty"`
+		SMTPAccount *SMTPAccountConfig `json:"smtp_account,omitempty"`
+	}
+
+	URLConfig struct {
+		URL string `json:"url"`
+	}
+
+	EmailConfig struct {
+		EmailAccountID string           `json:"email_account_id"`
+		RecipientList  []EmailRecipient `json:"recipient_list"`
+	}
+
+	EmailRecipient struct {
+		Recipient string `json:"recipient"`
+	}
+
+	SMTPAccountConfig struct {
+		Host        string `json:"host"`
+		Port        int32  `json:"port"`
+		Method      string `json:"method"`
+		FromAddress string `json:"from_address"`
+	}
+)
+
+const (
+	alertingMonitorsAPI  = "_plugins/_alerting/monitors"
+	notificationsAPI     = "_plugins/_notifications/configs"
+	channelTypeEmail     = "email"
+	channelTypeSMTP      = "smtp_account"
+	defaultCronTimezone  = "UTC"
+	maxAlertingResources = 1000
+	// Descriptor to identify monitors and notification channels as being managed by the VMI
+	vmiManagedAlerting = "__vmi-managed__"
+	// monitorManagedKey and monitorHashKey are kept in the monitor's ui_metadata, as monitors have no description
+	monitorManagedKey = "vmi_managed"
+	monitorHashKey    = "vmi_hash"
+)
+
+//ConfigureAlerting creates or updates the notification channels and Alerting monitors of the VMI. Monitors and
+// channels managed by the VMI, but no longer in the VMI, are deleted.
+// The returned channel should be read for exactly one response, which tells whether the alerting configuration succeeded.
+// The Alerting and Notifications plugins are only used while the VMI has monitors or channels, or had them before.
+func (o *OSClient) ConfigureAlerting(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, channelURLs map[string]string) chan error {
+	ch := make(chan error)
+	enabled := vmi.Spec.Elasticsearch.Enabled
+	managed := vmi.Status.Elasticsearch.AlertingManaged || IsAlertingConfigured(vmi)
+	monitors := append([]vmcontrollerv1.AlertingMonitor{}, vmi.Spec.Elasticsearch.Monitors...)
+	channels := append([]vmcontrollerv1.NotificationChannel{}, vmi.Spec.Elasticsearch.NotificationChannels...)
+	opensearchEndpoint := resources.GetOpenSearchHTTPEndpoint(vmi)
+	// configuration is done asynchronously, as this does not need to be blocking
+	go func() {
+		if !enabled || !managed {
+			ch <- nil
+			return
+		}
+		ch <- o.configureAlerting(log, opensearchEndpoint, monitors, channels, channelURLs)
+	}()
+	return ch
+}
+
+//IsAlertingConfigured returns true if the VMI has Alerting monitors or notification channels
+func IsAlertingConfigured(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) bool {
+	return len(vmi.Spec.Elasticsearch.Monitors) > 0 || len(vmi.Spec.Elasticsearch.NotificationChannels) > 0
+}
+
+func (o *OSClient) configureAlerting(log vzlog.VerrazzanoLogger, opensearchEndpoint string, monitors []vmcontrollerv1.AlertingMonitor, channels []vmcontrollerv1.NotificationChannel, channelURLs map[string]string) error {
+	configs, err := toNotificationConfigs(channels, channelURLs)
+	if err != nil {
+		return err
+	}
+	existingConfigs, err := o.getNotificationConfigs(opensearchEndpoint)
+	if err != nil {
+		return err
+	}
+	for _, config := range configs {
+		if err := o.putNotificationConfig(log, opensearchEndpoint, config, existingConfigs); err != nil {
+			return err
+		}
+	}
+
+	existingMonitors, err := o.getMonitors(opensearchEndpoint)
+	if err != nil {
+		return err
+	}
+	expectedMonitors := map[string]bool{}
+	for _, monitor := range monitors {
+		expectedMonitors[monitor.Name] = true
+		desired, err := toMonitor(monitor)
+		if err != nil {
+			return err
+		}
+		if err := o.putMonitor(log, opensearchEndpoint, desired, existingMonitors); err != nil {
+			return err
+		}
+	}
+
+	// monitors are deleted first, as they may send alerts to the channels being deleted
+	if err := o.cleanupMonitors(opensearchEndpoint, existingMonitors, expectedMonitors); err != nil {
+		return err
+	}
+	return o.cleanupNotificationConfigs(opensearchEndpoint, existingConfigs, channels)
+}
+
+func (o *OSClient) getMonitors(opensearchEndpoint string) ([]MonitorHit, error) {
+	query := fmt.Sprintf(`{"size": %d, "query": {"match_all": {}}}`, maxAlertingResources)
+	resp, err := o.doAlertingRequest("POST", fmt.Sprintf("%s/%s/_search", opensearchEndpoint, alertingMonitorsAPI), []byte(query))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	// the alerting config index does not exist until the first monitor is created
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got status code %d when searching monitors", resp.StatusCode)
+	}
+	monitors := &MonitorSearchResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(monitors); err != nil {
+		return nil, err
+	}
+	var hits []MonitorHit
+	for _, hit := range monitors.Hits.Hits {
+		if hit.Source.Type == "monitor" {
+			hits = append(hits, hit)
+		}
+	}
+	return hits, nil
+}
+
+//putMonitor creates the monitor, or updates the VMI managed monitor of the same name if it has changed.
+// Monitors of the same name which were not created by the VMI are not taken over.
+func (o *OSClient) putMonitor(log vzlog.VerrazzanoLogger, opensearchEndpoint string, monitor *Monitor, existingMonitors []MonitorHit) error {
+	method := "POST"
+	url := fmt.Sprintf("%s/%s", opensearchEndpoint, alertingMonitorsAPI)
+	for _, existing := range existingMonitors {
+		if existing.Source.Name != monitor.Name {
+			continue
+		}
+		if existing.Source.UIMetadata[monitorManagedKey] != vmiManagedAlerting || existing.Source.UIMetadata[monitorHashKey] == nil {
+			return fmt.Errorf("OpenSearch monitor %s was not created by the VMI, and is not taken over", monitor.Name)
+		}
+		if existing.Source.UIMetadata[monitorHashKey] == monitor.UIMetadata[monitorHashKey] {
+			return nil
+		}
+		log.Oncef("Updating OpenSearch monitor %s, which differs from the VMI", monitor.Name)
+		method = "PUT"
+		url = fmt.Sprintf("%s/%s", url, existing.ID)
+		break
+	}
+	payload, err := json.Marshal(monitor)
+	if err != nil {
+		return err
+	}
+	resp, err := o.doAlertingRequest(method, url, payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		responseBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("got status code %d when updating monitor %s: %s", resp.StatusCode, monitor.Name, string(responseBody))
+	}
+	return nil
+}
+
+func (o *OSClient) cleanupMonitors(opensearchEndpoint string, existingMonitors []MonitorHit, expectedMonitors map[string]bool) error {
+	for _, monitor := range existingMonitors {
+		if isMonitorEligibleForDeletion(monitor, expectedMonitors) {
+			if err := o.deleteAlertingResource(opensearchEndpoint, alertingMonitorsAPI, monitor.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func isMonitorEligibleForDeletion(monitor MonitorHit, expectedMonitors map[string]bool) bool {
+	return monitor.Source.UIMetadata[monitorManagedKey] == vmiManagedAlerting &&
+		!expectedMonitors[monitor.Source.Name]
+}
+
+func (o *OSClient) getNotificationConfigs(opensearchEndpoint string) (map[string]NotificationConfig, error) {
+	configList := &NotificationConfigList{}
+	if err := o.getJSON(fmt.Sprintf("%s/%s?max_items=%d", opensearchEndpoint, notificationsAPI, maxAlertingResources), configList); err != nil {
+		return nil, err
+	}
+	configs := map[string]NotificationConfig{}
+	for _, item := range configList.ConfigList {
+		configs[item.ConfigID] = item.Config
+	}
+	return configs, nil
+}
+
+//putNotificationConfig creates the notification channel, or updates it if it has changed.
+// Channels of the same ID which were not created by the VMI are not taken over.
+func (o *OSClient) putNotificationConfig(log vzlog.VerrazzanoLogger, opensearchEndpoint string, config NotificationConfigItem, existingConfigs map[string]NotificationConfig) error {
+	method := "POST"
+	url := fmt.Sprintf("%s/%s", opensearchEndpoint, notificationsAPI)
+	payload := config
+	if existing, ok := existingConfigs[config.ConfigID]; ok {
+		if existing.Description != vmiManagedAlerting {
+			return fmt.Errorf("OpenSearch notification channel %s was not created by the VMI, and is not taken over", config.ConfigID)
+		}
+		if reflect.DeepEqual(existing, config.Config) {
+			return nil
+		}
+		log.Oncef("Updating OpenSearch notification channel %s, which differs from the VMI", config.ConfigID)
+		method = "PUT"
+		url = fmt.Sprintf("%s/%s", url, config.ConfigID)
+		payload.ConfigID = ""
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := o.doAlertingRequest(method, url, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		responseBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("got status code %d when updating notification channel %s: %s", resp.StatusCode, config.ConfigID, string(responseBody))
+	}
+	return nil
+}
+
+//cleanupNotificationConfigs deletes the channels managed by the VMI which are no longer in the VMI.
+// SMTP accounts are deleted after the email channels which use them.
+func (o *OSClient) cleanupNotificationConfigs(opensearchEndpoint string, existingConfigs map[string]NotificationConfig, channels []vmcontrollerv1.NotificationChannel) error {
+	expected := map[string]bool{}
+	for _, channel := range channels {
+		expected[channel.Name] = true
+		expected[resources.GetSMTPAccountName(channel.Name)] = true
+	}
+	var ids []string
+	for id, config := range existingConfigs {
+		if config.Description == vmiManagedAlerting && !expected[id] {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := existingConfigs[ids[i]].ConfigType == channelTypeSMTP, existingConfigs[ids[j]].ConfigType == channelTypeSMTP
+		if a != b {
+			return b
+		}
+		return ids[i] < ids[j]
+	})
+	for _, id := range ids {
+		if err := o.deleteAlertingResource(opensearchEndpoint, notificationsAPI, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *OSClient) deleteAlertingResource(opensearchEndpoint, api, id string) error {
+	resp, err := o.doAlertingRequest("DELETE", fmt.Sprintf("%s/%s/%s", opensearchEndpoint, api, id), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("got status code %d when deleting %s", resp.StatusCode, id)
+	}
+	return nil
+}
+
+func (o *OSClient) doAlertingRequest(method, url string, payload []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	return o.DoHTTP(req)
+}
+
+//toNotificationConfigs returns the notification configs of the channels, in the order they must be created.
+// Channels without a URL, because their optional URL Secret does not exist, are left out.
+func toNotificationConfigs(channels []vmcontrollerv1.NotificationChannel, channelURLs map[string]string) ([]NotificationConfigItem, error) {
+	var configs []NotificationConfigItem
+	for _, channel := range channels {
+		config := NotificationConfig{
+			Name:        channel.Name,
+			Description: vmiManagedAlerting,
+			ConfigType:  channel.Type,
+			IsEnabled:   true,
+		}
+		switch channel.Type {
+		case "slack", "webhook":
+			url, ok := channelURLs[channel.Name]
+			if !ok {
+				continue
+			}
+			if channel.Type == "slack" {
+				config.Slack = &URLConfig{URL: url}
+			} else {
+				config.Webhook = &URLConfig{URL: url}
+			}
+		case channelTypeEmail:
+			if channel.Email == nil {
+				return nil, fmt.Errorf("email notification channel %s has no email settings", channel.Name)
+			}
+			method := channel.Email.Method
+			if method == "" {
+				method = "none"
+			}
+			smtpAccountID := resources.GetSMTPAccountName(channel.Name)
+			configs = append(configs, NotificationConfigItem{
+				ConfigID: smtpAccountID,
+				Config: NotificationConfig{
+					Name:        smtpAccountID,
+					Description: vmiManagedAlerting,
+					ConfigType:  channelTypeSMTP,
+					IsEnabled:   true,
+					SMTPAccount: &SMTPAccountConfig{
+						Host:        channel.Email.Host,
+						Port:        channel.Email.Port,
+						Method:      method,
+						FromAddress: channel.Email.From,
+					},
+				},
+			})
+			config.Email = &EmailConfig{EmailAccountID: smtpAccountID}
+			for _, recipient := range channel.Email.Recipients {
+				config.Email.RecipientList = append(config.Email.RecipientList, EmailRecipient{Recipient: recipient})
+			}
+		default:
+			return nil, fmt.Errorf("notification channel %s has unsupported type %s", channel.Name, channel.Type)
+		}
+		configs = append(configs, NotificationConfigItem{ConfigID: channel.Name, Config: config})
+	}
+	return configs, nil
+}
+
+//toMonitor returns the query monitor, marked as VMI managed, with a hash of its definition to detect changes
+func toMonitor(monitor vmcontrollerv1.AlertingMonitor) (*Monitor, error) {
+	var query map[string]interface{}
+	if err := json.Unmarshal([]byte(monitor.Query), &query); err != nil {
+		return nil, fmt.Errorf("monitor %s has an invalid query: %v", monitor.Name, err)
+	}
+	schedule, err := toMonitorSchedule(monitor.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("monitor %s has an invalid schedule: %v", monitor.Name, err)
+	}
+	m := &Monitor{
+		Type:        "monitor",
+		MonitorType: "query_level_monitor",
+		Name:        monitor.Name,
+		Enabled:     monitor.Enabled == nil || *monitor.Enabled,
+		Schedule:    *schedule,
+		Inputs: []MonitorInput{
+			{Search: MonitorSearch{Indices: monitor.Indices, Query: query}},
+		},
+		Triggers: []MonitorTrigger{},
+	}
+	for _, trigger := range monitor.Triggers {
+		t := MonitorTrigger{
+			Name:      trigger.Name,
+			Severity:  trigger.Severity,
+			Condition: TriggerCondition{Script: Script{Source: trigger.Condition, Lang: "painless"}},
+			Actions:   []TriggerAction{},
+		}
+		for _, action := range trigger.Actions {
+			a := TriggerAction{
+				Name:            action.Name,
+				DestinationID:   action.Channel,
+				MessageTemplate: Script{Source: action.Message},
+			}
+			if action.Subject != "" {
+				a.SubjectTemplate = &Script{Source: action.Subject}
+			}
+			if action.Throttle != "" {
+				minutes, err := strconv.Atoi(strings.TrimSuffix(action.Throttle, "m"))
+				if err != nil {
+					return nil, fmt.Errorf("monitor %s has an invalid throttle %s", monitor.Name, action.Throttle)
+				}
+				a.ThrottleEnabled = true
+				a.Throttle = &ActionThrottle{Value: minutes, Unit: "MINUTES"}
+			}
+			t.Actions = append(t.Actions, a)
+		}
+		m.Triggers = append(m.Triggers, t)
+	}
+
+	definition, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	h := fnv.New32a()
+	if _, err := h.Write(definition); err != nil {
+		return nil, err
+	}
+	m.UIMetadata = map[string]interface{}{
+		monitorManagedKey: vmiManagedAlerting,
+		monitorHashKey:    fmt.Sprintf("%x", h.Sum32()),
+	}
+	return m, nil
+}
+
+func toMonitorSchedule(schedule vmcontrollerv1.MonitorSchedule) (*MonitorSchedule, error) {
+	if schedule.Cron != "" {
+		timezone := schedule.Timezone
+		if timezone == "" {
+			timezone = defaultCronTimezone
+		}
+		return &MonitorSchedule{Cron: &ScheduleCron{Expression: schedule.Cron, Timezone: timezone}}, nil
+	}
+	if len(schedule.Interval) < 2 {
+		return nil, fmt.Errorf("either an interval or a cron expression is required")
+	}
+	interval, err := strconv.Atoi(schedule.Interval[:len(schedule.Interval)-1])
+	if err != nil || interval < 1 {
+		return nil, fmt.Errorf("invalid interval %s", schedule.Interval)
+	}
+	units := map[byte]string{'m': "MINUTES", 'h': "HOURS", 'd': "DAYS"}
+	unit, ok := units[schedule.Interval[len(schedule.Interval)-1]]
+	if !ok {
+		return nil, fmt.Errorf("invalid interval unit %s", schedule.Interval)
+	}
+	return &MonitorSchedule{Period: &SchedulePeriod{Interval: interval, Unit: unit}}, nil
+}
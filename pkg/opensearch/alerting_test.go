@@ -0,0 +1,216 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	"io"
+	corev1 "k8s.io/api/core/v1"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func createAlertingVMI() *vmcontrollerv1.VerrazzanoMonitoringInstance {
+	vmi := createISMVMI("1d", true)
+	vmi.Spec.Elasticsearch.NotificationChannels = []vmcontrollerv1.NotificationChannel{
+		{Name: "oncall", Type: "slack", URLSecret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "oncall"}, Key: "url"}},
+		{Name: "mail", Type: "email", Email: &vmcontrollerv1.EmailChannel{Host: "smtp.example.com", Port: 587, Method: "start_tls", From: "vmi@example.com", Recipients: []string{"oncall@example.com"}}},
+	}
+	vmi.Spec.Elasticsearch.Monitors = []vmcontrollerv1.AlertingMonitor{
+		{
+			Name:     "error-rate",
+			Indices:  []string{"verrazzano-application-*"},
+			Query:    `{"size": 0, "query": {"match": {"level": "error"}}}`,
+			Schedule: vmcontrollerv1.MonitorSchedule{Interval: "5m"},
+			Triggers: []vmcontrollerv1.MonitorTrigger{
+				{
+					Name:      "spike",
+					Severity:  "1",
+					Condition: "ctx.results[0].hits.total.value > 100",
+					Actions:   []vmcontrollerv1.MonitorAction{{Name: "page", Channel: "oncall", Message: "Error rate spike", Throttle: "10m"}},
+				},
+			},
+		},
+	}
+	return vmi
+}
+
+// TestConfigureAlerting Tests the reconciliation of monitors and notification channels
+// GIVEN a VMI with monitors and notification channels
+// WHEN I call ConfigureAlerting
+// THEN missing or changed channels and monitors are created or updated, and removed VMI managed ones are deleted
+func TestConfigureAlerting(t *testing.T) {
+	vmi := createAlertingVMI()
+	desired, err := toMonitor(vmi.Spec.Elasticsearch.Monitors[0])
+	assert.NoError(t, err)
+	monitorHits := func(hash string) string {
+		return `{"hits": {"hits": [
+			{"_id": "m1", "_source": {"type": "monitor", "name": "error-rate", "ui_metadata": {"vmi_managed": "__vmi-managed__", "vmi_hash": "` + hash + `"}}},
+			{"_id": "m2", "_source": {"type": "monitor", "name": "removed", "ui_metadata": {"vmi_managed": "__vmi-managed__"}}},
+			{"_id": "m3", "_source": {"type": "monitor", "name": "dashboards", "ui_metadata": {"search": {}}}}
+		]}}`
+	}
+	const configs = `{"config_list": [
+		{"config_id": "oncall", "config": {"name": "oncall", "description": "__vmi-managed__", "config_type": "slack", "is_enabled": true, "slack": {"url": "https://hooks.slack.com/old"}}},
+		{"config_id": "old", "config": {"name": "old", "description": "__vmi-managed__", "config_type": "email", "is_enabled": true}},
+		{"config_id": "old-smtp-account", "config": {"name": "old-smtp-account", "description": "__vmi-managed__", "config_type": "smtp_account", "is_enabled": true}},
+		{"config_id": "team", "config": {"name": "team", "config_type": "chime", "is_enabled": true}}
+	]}`
+
+	var tests = []struct {
+		name     string
+		hash     string
+		requests []string
+	}{
+		{
+			"updates a changed monitor",
+			"stale",
+			[]string{
+				"PUT /_plugins/_notifications/configs/oncall",
+				"POST /_plugins/_notifications/configs",
+				"POST /_plugins/_notifications/configs",
+				"PUT /_plugins/_alerting/monitors/m1",
+				"DELETE /_plugins/_alerting/monitors/m2",
+				"DELETE /_plugins/_notifications/configs/old",
+				"DELETE /_plugins/_notifications/configs/old-smtp-account",
+			},
+		},
+		{
+			"leaves an unchanged monitor alone",
+			desired.UIMetadata[monitorHashKey].(string),
+			[]string{
+				"PUT /_plugins/_notifications/configs/oncall",
+				"POST /_plugins/_notifications/configs",
+				"POST /_plugins/_notifications/configs",
+				"DELETE /_plugins/_alerting/monitors/m2",
+				"DELETE /_plugins/_notifications/configs/old",
+				"DELETE /_plugins/_notifications/configs/old-smtp-account",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			o := NewOSClient()
+			o.DoHTTP = func(req *http.Request) (*http.Response, error) {
+				body := ""
+				switch {
+				case req.Method == "GET" && req.URL.Path == "/_plugins/_notifications/configs":
+					body = configs
+				case req.URL.Path == "/_plugins/_alerting/monitors/_search":
+					body = monitorHits(tt.hash)
+				default:
+					requests = append(requests, req.Method+" "+req.URL.Path)
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(body)),
+				}, nil
+			}
+			err := <-o.ConfigureAlerting(vzlog.DefaultLogger(), vmi, map[string]string{"oncall": "https://hooks.slack.com/new"})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.requests, requests)
+		})
+	}
+}
+
+// TestConfigureAlertingNotTakenOver Tests that monitors and channels not created by the VMI are left alone
+// GIVEN a VMI with a monitor or a channel named like one created outside of the VMI
+// WHEN I call ConfigureAlerting
+// THEN an error is returned, and the existing monitor or channel is not updated
+func TestConfigureAlertingNotTakenOver(t *testing.T) {
+	var tests = []struct {
+		name     string
+		monitors string
+		configs  string
+		err      string
+	}{
+		{
+			"a monitor without the VMI hash",
+			`{"hits": {"hits": [{"_id": "m1", "_source": {"type": "monitor", "name": "error-rate", "ui_metadata": {"search": {}}}}]}}`,
+			`{"config_list": []}`,
+			"OpenSearch monitor error-rate was not created by the VMI, and is not taken over",
+		},
+		{
+			"a channel without the VMI description",
+			`{"hits": {"hits": []}}`,
+			`{"config_list": [{"config_id": "oncall", "config": {"name": "oncall", "config_type": "slack", "is_enabled": true, "slack": {"url": "https://hooks.slack.com/team"}}}]}`,
+			"OpenSearch notification channel oncall was not created by the VMI, and is not taken over",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := NewOSClient()
+			o.DoHTTP = func(req *http.Request) (*http.Response, error) {
+				body := ""
+				switch {
+				case req.Method == "GET" && req.URL.Path == "/_plugins/_notifications/configs":
+					body = tt.configs
+				case req.URL.Path == "/_plugins/_alerting/monitors/_search":
+					body = tt.monitors
+				case req.Method == "PUT" || req.Method == "DELETE":
+					t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(body)),
+				}, nil
+			}
+			err := <-o.ConfigureAlerting(vzlog.DefaultLogger(), createAlertingVMI(), map[string]string{"oncall": "https://hooks.slack.com/new"})
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+// TestConfigureAlertingNotManaged Tests that the Alerting plugin is not used by VMIs without monitors
+// GIVEN a VMI without monitors or notification channels, which never had them
+// WHEN I call ConfigureAlerting
+// THEN no request is sent to OpenSearch
+func TestConfigureAlertingNotManaged(t *testing.T) {
+	o := NewOSClient()
+	o.DoHTTP = func(req *http.Request) (*http.Response, error) {
+		t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+		return nil, nil
+	}
+	assert.NoError(t, <-o.ConfigureAlerting(vzlog.DefaultLogger(), createISMVMI("1d", true), nil))
+}
+
+// TestToMonitor Tests the monitor sent to the Alerting plugin
+// GIVEN a VMI monitor
+// WHEN I call toMonitor
+// THEN the monitor has the schedule, query and triggers of the VMI monitor, and invalid monitors are rejected
+func TestToMonitor(t *testing.T) {
+	vmi := createAlertingVMI()
+	monitor, err := toMonitor(vmi.Spec.Elasticsearch.Monitors[0])
+	assert.NoError(t, err)
+	assert.True(t, monitor.Enabled)
+	assert.Equal(t, &SchedulePeriod{Interval: 5, Unit: "MINUTES"}, monitor.Schedule.Period)
+	assert.Equal(t, float64(0), monitor.Inputs[0].Search.Query["size"])
+	action := monitor.Triggers[0].Actions[0]
+	assert.Equal(t, "oncall", action.DestinationID)
+	assert.Equal(t, &ActionThrottle{Value: 10, Unit: "MINUTES"}, action.Throttle)
+	assert.Equal(t, vmiManagedAlerting, monitor.UIMetadata[monitorManagedKey])
+	payload, err := json.Marshal(monitor)
+	assert.NoError(t, err)
+	assert.Contains(t, string(payload), `"monitor_type":"query_level_monitor"`)
+
+	cron := vmi.Spec.Elasticsearch.Monitors[0]
+	cron.Schedule = vmcontrollerv1.MonitorSchedule{Cron: "0 * * * *"}
+	monitor, err = toMonitor(cron)
+	assert.NoError(t, err)
+	assert.Equal(t, &ScheduleCron{Expression: "0 * * * *", Timezone: "UTC"}, monitor.Schedule.Cron)
+
+	invalid := vmi.Spec.Elasticsearch.Monitors[0]
+	invalid.Query = "{"
+	_, err = toMonitor(invalid)
+	assert.Error(t, err)
+	invalid = vmi.Spec.Elasticsearch.Monitors[0]
+	invalid.Schedule = vmcontrollerv1.MonitorSchedule{}
+	_, err = toMonitor(invalid)
+	assert.Error(t, err)
+}
@@ -0,0 +1,172 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type (
+	ClusterSettings struct {
+		Persistent map[string]interface{} `json:"persistent"`
+		Transient  map[string]interface{} `json:"transient,omitempty"`
+	}
+)
+
+const (
+	// AllocationAwarenessSetting is the cluster setting which enables shard allocation awareness
+	AllocationAwarenessSetting = "cluster.routing.allocation.awareness.attributes"
+	// AvailabilityDomainAttribute is the node attribute data nodes are started with, see node.attr.availability_domain
+	AvailabilityDomainAttribute = "availability_domain"
+)
+
+//GetClusterSettings returns the persistent cluster settings the VMI expects to be applied.
+// Allocation awareness on availability_domain is enabled by default when the data nodes are spread across more
+// than one availability domain. Remote clusters are configured through cluster.remote.* settings.
+// Settings from the VMI spec always take precedence over defaults, except for the settings the operator manages.
+func GetClusterSettings(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, availabilityDomains []string) map[string]string {
+	settings := map[string]string{}
+	if len(availabilityDomains) > 1 {
+		settings[AllocationAwarenessSetting] = AvailabilityDomainAttribute
+	}
+	for _, remote := range vmi.Spec.Elasticsearch.RemoteClusters {
+		prefix := fmt.Sprintf("cluster.remote.%s.", remote.Name)
+		settings[prefix+"seeds"] = strings.Join(remote.Seeds, ",")
+		settings[prefix+"skip_unavailable"] = strconv.FormatBool(remote.SkipUnavailable)
+	}
+	for k, v := range vmi.Spec.Elasticsearch.ClusterSettings {
+		if !isManagedClusterSetting(k) {
+			settings[k] = v
+		}
+	}
+	return settings
+}
+
+//isManagedClusterSetting returns true if the operator manages the setting, e.g., the nodes excluded from shard
+// allocation while a node is drained, or the shards which may be allocated while a node restarts
+func isManagedClusterSetting(setting string) bool {
+	return setting == AllocationExcludeNameSetting || setting == AllocationEnableSetting
+}
+
+//ConfigureClusterSettings applies the persistent cluster settings. Settings that were previously applied by the
+// operator, but are no longer expected, are reset to the cluster default.
+// The returned channel should be read for exactly one response, which tells whether the settings were applied.
+func (o *OSClient) ConfigureClusterSettings(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, settings, previous map[string]string) chan error {
+	ch := make(chan error)
+	// configuration is done asynchronously, as this does not need to be blocking
+	go func() {
+		if !vmi.Spec.Elasticsearch.Enabled {
+			ch <- nil
+			return
+		}
+		if err := o.updateClusterSettings(resources.GetOpenSearchHTTPEndpoint(vmi), settings, previous); err != nil {
+			ch <- err
+			return
+		}
+		for k := range vmi.Spec.Elasticsearch.ClusterSettings {
+			if isManagedClusterSetting(k) {
+				ch <- fmt.Errorf("cluster setting %s is managed by the operator, and cannot be set in the VMI", k)
+				return
+			}
+		}
+		ch <- nil
+	}()
+	return ch
+}
+
+func (o *OSClient) updateClusterSettings(opensearchEndpoint string, settings, previous map[string]string) error {
+	existing, err := o.getClusterSettings(opensearchEndpoint)
+	if err != nil {
+		return err
+	}
+	updates := clusterSettingsUpdates(existing, settings, previous)
+	if len(updates) == 0 {
+		return nil
+	}
+	return o.putClusterSettings(opensearchEndpoint, &ClusterSettings{Persistent: updates})
+}
+
+func (o *OSClient) getClusterSettings(opensearchEndpoint string) (*ClusterSettings, error) {
+	url := fmt.Sprintf("%s/_cluster/settings?flat_settings=true", opensearchEndpoint)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got status code %d when getting cluster settings", resp.StatusCode)
+	}
+	clusterSettings := &ClusterSettings{}
+	if err := json.NewDecoder(resp.Body).Decode(clusterSettings); err != nil {
+		return nil, err
+	}
+	return clusterSettings, nil
+}
+
+func (o *OSClient) putClusterSettings(opensearchEndpoint string, clusterSettings *ClusterSettings) error {
+	payload, err := json.Marshal(clusterSettings)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/_cluster/settings", opensearchEndpoint)
+	req, err := http.NewRequest("PUT", url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		responseBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("got status code %d when updating cluster settings: %s", resp.StatusCode, string(responseBody))
+	}
+	return nil
+}
+
+//clusterSettingsUpdates returns the persistent settings which differ from the existing cluster settings.
+// A nil value resets the setting to its default.
+func clusterSettingsUpdates(existing *ClusterSettings, settings, previous map[string]string) map[string]interface{} {
+	updates := map[string]interface{}{}
+	for k, v := range settings {
+		if existingValue, ok := existing.Persistent[k]; !ok || settingValue(existingValue) != v {
+			updates[k] = v
+		}
+	}
+	for k := range previous {
+		if _, ok := settings[k]; ok || isManagedClusterSetting(k) {
+			continue
+		}
+		if _, ok := existing.Persistent[k]; ok {
+			updates[k] = nil
+		}
+	}
+	return updates
+}
+
+//settingValue formats an existing setting like the expected settings, where list settings are comma separated
+func settingValue(value interface{}) string {
+	if list, ok := value.([]interface{}); ok {
+		var values []string
+		for _, v := range list {
+			values = append(values, fmt.Sprint(v))
+		}
+		return strings.Join(values, ",")
+	}
+	return fmt.Sprint(value)
+}
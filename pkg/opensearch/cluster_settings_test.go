@@ -0,0 +1,191 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const testClusterSettings = `{
+	"persistent": {
+		"cluster.routing.allocation.awareness.attributes": "availability_domain",
+		"cluster.max_shards_per_node": "2000",
+		"cluster.remote.managed1.seeds": ["managed1-a:9300", "managed1-b:9300"]
+	},
+	"transient": {}
+}`
+
+func TestGetClusterSettings(t *testing.T) {
+	var tests = []struct {
+		name                string
+		clusterSettings     map[string]string
+		availabilityDomains []string
+		expected            map[string]string
+	}{
+		{
+			"no settings for a single availability domain",
+			nil,
+			[]string{"AD1"},
+			map[string]string{},
+		},
+		{
+			"allocation awareness for multiple availability domains",
+			nil,
+			[]string{"AD1", "AD2"},
+			map[string]string{AllocationAwarenessSetting: AvailabilityDomainAttribute},
+		},
+		{
+			"the node exclusion is managed by the operator",
+			map[string]string{AllocationExcludeNameSetting: "data-0"},
+			nil,
+			map[string]string{},
+		},
+		{
+			"the shard allocation is managed by the operator",
+			map[string]string{AllocationEnableSetting: "all"},
+			nil,
+			map[string]string{},
+		},
+		{
+			"spec settings override defaults",
+			map[string]string{
+				AllocationAwarenessSetting:    "",
+				"cluster.max_shards_per_node": "2000",
+			},
+			[]string{"AD1", "AD2"},
+			map[string]string{
+				AllocationAwarenessSetting:    "",
+				"cluster.max_shards_per_node": "2000",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+				Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+					Elasticsearch: vmcontrollerv1.Elasticsearch{
+						ClusterSettings: tt.clusterSettings,
+					},
+				},
+			}
+			assert.Equal(t, tt.expected, GetClusterSettings(vmi, tt.availabilityDomains))
+		})
+	}
+
+	// remote clusters are configured through cluster.remote.* settings
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{}
+	vmi.Spec.Elasticsearch.RemoteClusters = []vmcontrollerv1.RemoteCluster{
+		{Name: "managed1", Seeds: []string{"managed1-a:9300", "managed1-b:9300"}, SkipUnavailable: true},
+	}
+	assert.Equal(t, map[string]string{
+		"cluster.remote.managed1.seeds":            "managed1-a:9300,managed1-b:9300",
+		"cluster.remote.managed1.skip_unavailable": "true",
+	}, GetClusterSettings(vmi, nil))
+}
+
+func TestConfigureClusterSettings(t *testing.T) {
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Elasticsearch: vmcontrollerv1.Elasticsearch{
+				Enabled: true,
+			},
+		},
+	}
+	var tests = []struct {
+		name     string
+		settings map[string]string
+		previous map[string]string
+		update   map[string]interface{}
+		isError  bool
+	}{
+		{
+			"no update when settings are already applied",
+			map[string]string{AllocationAwarenessSetting: AvailabilityDomainAttribute},
+			nil,
+			nil,
+			false,
+		},
+		{
+			"no update when list settings are already applied",
+			map[string]string{"cluster.remote.managed1.seeds": "managed1-a:9300,managed1-b:9300"},
+			nil,
+			nil,
+			false,
+		},
+		{
+			"changed settings are updated",
+			map[string]string{"cluster.max_shards_per_node": "3000"},
+			nil,
+			map[string]interface{}{"cluster.max_shards_per_node": "3000"},
+			false,
+		},
+		{
+			"previously applied settings are reset",
+			map[string]string{"cluster.max_shards_per_node": "2000"},
+			map[string]string{AllocationAwarenessSetting: AvailabilityDomainAttribute},
+			map[string]interface{}{AllocationAwarenessSetting: nil},
+			false,
+		},
+		{
+			"error when the update fails",
+			map[string]string{"cluster.routing.allocation.enable": "all"},
+			nil,
+			map[string]interface{}{"cluster.routing.allocation.enable": "all"},
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var update map[string]interface{}
+			o := NewOSClient()
+			o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+				if request.Method == "GET" {
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(testClusterSettings)),
+					}, nil
+				}
+				clusterSettings := &ClusterSettings{}
+				assert.NoError(t, json.NewDecoder(request.Body).Decode(clusterSettings))
+				update = clusterSettings.Persistent
+				statusCode := http.StatusOK
+				if tt.isError {
+					statusCode = http.StatusBadRequest
+				}
+				return &http.Response{
+					StatusCode: statusCode,
+					Body:       io.NopCloser(strings.NewReader("")),
+				}, nil
+			}
+			err := <-o.ConfigureClusterSettings(vmi, tt.settings, tt.previous)
+			assert.Equal(t, tt.isError, err != nil)
+			assert.Equal(t, tt.update, update)
+		})
+	}
+
+	// the node exclusion is never reset, and is rejected in the VMI while the other settings are applied
+	var updates []map[string]interface{}
+	o := NewOSClient()
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		if request.Method == "GET" {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(testClusterSettings))}, nil
+		}
+		clusterSettings := &ClusterSettings{}
+		assert.NoError(t, json.NewDecoder(request.Body).Decode(clusterSettings))
+		updates = append(updates, clusterSettings.Persistent)
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}
+	vmi.Spec.Elasticsearch.ClusterSettings = map[string]string{AllocationExcludeNameSetting: "data-0", "cluster.max_shards_per_node": "3000"}
+	err := <-o.ConfigureClusterSettings(vmi, GetClusterSettings(vmi, nil), map[string]string{AllocationExcludeNameSetting: "data-0"})
+	assert.Error(t, err)
+	assert.Equal(t, []map[string]interface{}{{"cluster.max_shards_per_node": "3000"}}, updates)
+}
@@ -0,0 +1,198 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	AllocationExplanation struct {
+		Index                   string                   `json:"index"`
+		Shard                   int                      `json:"shard"`
+		Primary                 bool                     `json:"primary"`
+		CurrentState            string                   `json:"current_state"`
+		UnassignedInfo          *UnassignedInfo          `json:"unassigned_info,omitempty"`
+		CanAllocate             string                   `json:"can_allocate"`
+		AllocateExplanation     string                   `json:"allocate_explanation"`
+		NodeAllocationDecisions []NodeAllocationDecision `json:"node_allocation_decisions"`
+	}
+
+	UnassignedInfo struct {
+		Reason               string `json:"reason"`
+		LastAllocationStatus string `json:"last_allocation_status"`
+		Details              string `json:"details"`
+	}
+
+	NodeAllocationDecision struct {
+		NodeName     string            `json:"node_name"`
+		NodeDecision string            `json:"node_decision"`
+		Deciders     []DeciderDecision `json:"deciders"`
+	}
+
+	DeciderDecision struct {
+		Decider     string `json:"decider"`
+		Decision    string `json:"decision"`
+		Explanation string `json:"explanation"`
+	}
+
+	//HealthDiagnostics is the cluster health, with the reasons unassigned shards cannot be allocated
+	HealthDiagnostics struct {
+		*ClusterHealth
+		BlockingReasons []string
+	}
+)
+
+const (
+	// maxExplainedShards bounds the number of unassigned shards explained on each health check
+	maxExplainedShards = 5
+	// maxReasonLength bounds the length of each blocking reason
+	maxReasonLength = 256
+	shardUnassigned = "UNASSIGNED"
+	decisionNo      = "NO"
+)
+
+//GetClusterHealth returns the full cluster health
+func (o *OSClient) GetClusterHealth(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) (*ClusterHealth, error) {
+	return o.getOpenSearchClusterHealth(vmi)
+}
+
+//GetHealthDiagnostics returns the cluster health, with the reasons the first unassigned shards cannot be allocated
+func (o *OSClient) GetHealthDiagnostics(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) (*HealthDiagnostics, error) {
+	health, err := o.getOpenSearchClusterHealth(vmi)
+	if err != nil {
+		return nil, err
+	}
+	status := &HealthDiagnostics{ClusterHealth: health}
+	if health.UnassignedShards < 1 {
+		return status, nil
+	}
+
+	shards, err := o.getUnassignedShards(vmi)
+	if err != nil {
+		return nil, err
+	}
+	for i, shard := range shards {
+		if i >= maxExplainedShards {
+			// the count is left out, so the reasons only change when the explained shards do
+			status.BlockingReasons = append(status.BlockingReasons, "more unassigned shards were not explained")
+			break
+		}
+		explanation, err := o.ExplainAllocation(vmi, shard)
+		if err != nil {
+			return nil, err
+		}
+		status.BlockingReasons = append(status.BlockingReasons, summarizeAllocation(explanation))
+	}
+	return status, nil
+}
+
+//ExplainAllocation returns the cluster's explanation of why the shard is not allocated
+func (o *OSClient) ExplainAllocation(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, shard CatShard) (*AllocationExplanation, error) {
+	shardNumber, err := strconv.Atoi(shard.Shard)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"index":   shard.Index,
+		"shard":   shardNumber,
+		"primary": shard.PriRep == "p",
+	})
+	if err != nil {
+		return nil, err
+	}
+	url := resources.GetOpenSearchHTTPEndpoint(vmi) + "/_cluster/allocation/explain"
+	req, err := http.NewRequest("POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		responseBody, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("got status code %d when explaining allocation of %s[%s]: %s", resp.StatusCode, shard.Index, shard.Shard, string(responseBody))
+	}
+	explanation := &AllocationExplanation{}
+	if err := json.NewDecoder(resp.Body).Decode(explanation); err != nil {
+		return nil, err
+	}
+	return explanation, nil
+}
+
+//getUnassignedShards returns the unassigned shards, primaries first, in a stable order
+func (o *OSClient) getUnassignedShards(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) ([]CatShard, error) {
+	url := resources.GetOpenSearchHTTPEndpoint(vmi) + "/_cat/shards?format=json&h=index,shard,prirep,state"
+	var shards []CatShard
+	if err := o.getJSON(url, &shards); err != nil {
+		return nil, err
+	}
+	var unassigned []CatShard
+	for _, shard := range shards {
+		if shard.State == shardUnassigned {
+			unassigned = append(unassigned, shard)
+		}
+	}
+	sort.Slice(unassigned, func(i, j int) bool {
+		a, b := unassigned[i], unassigned[j]
+		if a.PriRep != b.PriRep {
+			return a.PriRep == "p"
+		}
+		if a.Index != b.Index {
+			return a.Index < b.Index
+		}
+		return a.Shard < b.Shard
+	})
+	return unassigned, nil
+}
+
+//summarizeAllocation describes why a shard is unassigned, using the first decider which prevents its allocation
+func summarizeAllocation(explanation *AllocationExplanation) string {
+	shardType := "replica"
+	if explanation.Primary {
+		shardType = "primary"
+	}
+	reason := explanation.AllocateExplanation
+	for _, node := range explanation.NodeAllocationDecisions {
+		if blocking := getBlockingDecider(node); blocking != nil {
+			reason = fmt.Sprintf("node %s: %s: %s", node.NodeName, blocking.Decider, blocking.Explanation)
+			break
+		}
+	}
+	if reason == "" && explanation.UnassignedInfo != nil {
+		reason = explanation.UnassignedInfo.Details
+	}
+	summary := fmt.Sprintf("%s[%d] %s", explanation.Index, explanation.Shard, shardType)
+	if explanation.UnassignedInfo != nil && explanation.UnassignedInfo.Reason != "" {
+		summary = fmt.Sprintf("%s (%s)", summary, explanation.UnassignedInfo.Reason)
+	}
+	if reason != "" {
+		summary = fmt.Sprintf("%s: %s", summary, reason)
+	}
+	if len(summary) > maxReasonLength {
+		summary = summary[:maxReasonLength-3] + "..."
+	}
+	return summary
+}
+
+func getBlockingDecider(node NodeAllocationDecision) *DeciderDecision {
+	for i := range node.Deciders {
+		if strings.EqualFold(node.Deciders[i].Decision, decisionNo) {
+			return &node.Deciders[i]
+		}
+	}
+	return nil
+}
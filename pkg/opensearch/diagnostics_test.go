@@ -0,0 +1,146 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const testAllocationExplanation = `{
+	"index": "verrazzano-system",
+	"shard": 0,
+	"primary": false,
+	"current_state": "unassigned",
+	"unassigned_info": {"reason": "NODE_LEFT", "last_allocation_status": "no_attempt"},
+	"can_allocate": "no",
+	"allocate_explanation": "cannot allocate because allocation is not permitted to any of the nodes",
+	"node_allocation_decisions": [
+		{
+			"node_name": "data-0",
+			"node_decision": "no",
+			"deciders": [
+				{"decider": "same_shard", "decision": "NO", "explanation": "a copy of this shard is already allocated to this node"}
+			]
+		}
+	]
+}`
+
+func TestGetHealthDiagnostics(t *testing.T) {
+	var tests = []struct {
+		name       string
+		health     string
+		shards     string
+		reasons    []string
+		explained  int
+		isError    bool
+		statusCode int
+	}{
+		{
+			"green cluster has no blocking reasons",
+			`{"status": "green", "number_of_nodes": 3, "active_shards": 10}`,
+			`[]`,
+			nil,
+			0,
+			false,
+			http.StatusOK,
+		},
+		{
+			"yellow cluster explains unassigned shards",
+			`{"status": "yellow", "number_of_nodes": 1, "active_shards": 5, "unassigned_shards": 1, "number_of_pending_tasks": 2}`,
+			`[{"index": "verrazzano-system", "shard": "0", "prirep": "p", "state": "STARTED"}, {"index": "verrazzano-system", "shard": "0", "prirep": "r", "state": "UNASSIGNED"}]`,
+			[]string{"verrazzano-system[0] replica (NODE_LEFT): node data-0: same_shard: a copy of this shard is already allocated to this node"},
+			1,
+			false,
+			http.StatusOK,
+		},
+		{
+			"explanations are bounded",
+			`{"status": "yellow", "unassigned_shards": 7}`,
+			testUnassignedShards(7),
+			nil,
+			maxExplainedShards,
+			false,
+			http.StatusOK,
+		},
+		{
+			"error when the health request fails",
+			``,
+			``,
+			nil,
+			0,
+			true,
+			http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			explained := 0
+			o := NewOSClient()
+			o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+				body := tt.health
+				switch request.URL.Path {
+				case "/_cat/shards":
+					body = tt.shards
+				case "/_cluster/allocation/explain":
+					explained++
+					payload := map[string]interface{}{}
+					assert.NoError(t, json.NewDecoder(request.Body).Decode(&payload))
+					assert.Equal(t, "verrazzano-system", payload["index"])
+					body = testAllocationExplanation
+				}
+				return &http.Response{
+					StatusCode: tt.statusCode,
+					Body:       io.NopCloser(strings.NewReader(body)),
+				}, nil
+			}
+			status, err := o.GetHealthDiagnostics(testDrainVMI())
+			if tt.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.explained, explained)
+			if tt.reasons != nil {
+				assert.Equal(t, tt.reasons, status.BlockingReasons)
+			}
+			if tt.explained == maxExplainedShards {
+				assert.Len(t, status.BlockingReasons, maxExplainedShards+1)
+				assert.Equal(t, "more unassigned shards were not explained", status.BlockingReasons[maxExplainedShards])
+			}
+		})
+	}
+}
+
+func TestSummarizeAllocation(t *testing.T) {
+	explanation := &AllocationExplanation{
+		Index:               "verrazzano-system",
+		Shard:               1,
+		Primary:             true,
+		UnassignedInfo:      &UnassignedInfo{Reason: "INDEX_CREATED"},
+		AllocateExplanation: strings.Repeat("x", 2*maxReasonLength),
+	}
+	summary := summarizeAllocation(explanation)
+	assert.Len(t, summary, maxReasonLength)
+	assert.True(t, strings.HasPrefix(summary, "verrazzano-system[1] primary (INDEX_CREATED): x"))
+	assert.True(t, strings.HasSuffix(summary, "..."))
+
+	explanation.AllocateExplanation = ""
+	explanation.UnassignedInfo.Details = "failed to create shard"
+	assert.Equal(t, "verrazzano-system[1] primary (INDEX_CREATED): failed to create shard", summarizeAllocation(explanation))
+}
+
+func testUnassignedShards(count int) string {
+	var shards []string
+	for i := 0; i < count; i++ {
+		shards = append(shards, fmt.Sprintf(`{"index": "verrazzano-system", "shard": "%d", "prirep": "r", "state": "UNASSIGNED"}`, i))
+	}
+	return "[" + strings.Join(shards, ",") + "]"
+}
@@ -0,0 +1,37 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"strconv"
+)
+
+type CatAllocation struct {
+	Node        string  `json:"node"`
+	DiskPercent *string `json:"disk.percent"`
+}
+
+//GetNodeDiskUsage returns the disk usage percentage of each data node, keyed by node name
+func (o *OSClient) GetNodeDiskUsage(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) (map[string]int, error) {
+	url := resources.GetOpenSearchHTTPEndpoint(vmi) + "/_cat/allocation?format=json&h=node,disk.percent"
+	var allocations []CatAllocation
+	if err := o.getJSON(url, &allocations); err != nil {
+		return nil, err
+	}
+	usage := map[string]int{}
+	for _, allocation := range allocations {
+		// unassigned shards are reported as a row without disk usage
+		if allocation.DiskPercent == nil {
+			continue
+		}
+		percent, err := strconv.Atoi(*allocation.DiskPercent)
+		if err != nil {
+			return nil, err
+		}
+		usage[allocation.Node] = percent
+	}
+	return usage, nil
+}
@@ -0,0 +1,54 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestGetNodeDiskUsage(t *testing.T) {
+	var tests = []struct {
+		name    string
+		body    string
+		usage   map[string]int
+		isError bool
+	}{
+		{
+			"returns the disk usage of each node",
+			`[{"node": "data-0", "disk.percent": "81"}, {"node": "data-1", "disk.percent": "12"}, {"node": "UNASSIGNED", "disk.percent": null}]`,
+			map[string]int{"data-0": 81, "data-1": 12},
+			false,
+		},
+		{
+			"error when the disk usage is not a number",
+			`[{"node": "data-0", "disk.percent": "x"}]`,
+			nil,
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := NewOSClient()
+			o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+				assert.Equal(t, "/_cat/allocation", request.URL.Path)
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(tt.body)),
+				}, nil
+			}
+			usage, err := o.GetNodeDiskUsage(testDrainVMI())
+			if tt.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.usage, usage)
+		})
+	}
+}
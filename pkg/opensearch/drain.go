@@ -0,0 +1,130 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"encoding/json"
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"net/http"
+	"strings"
+)
+
+type (
+	CatShard struct {
+		Index string `json:"index"`
+		Shard  string `json:"shard"`
+		PriRep string `json:"prirep"`
+		State  string `json:"state"`
+		Node   string `json:"node"`
+	}
+
+	CatNode struct {
+		Name string `json:"name"`
+	}
+)
+
+// AllocationExcludeNameSetting is the cluster setting which moves shards off of the named nodes
+const AllocationExcludeNameSetting = "cluster.routing.allocation.exclude._name"
+
+//ExcludeNode adds the node to the nodes excluded from shard allocation, so that its shards are relocated to the
+// remaining nodes. Nodes excluded by others are kept.
+func (o *OSClient) ExcludeNode(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, nodeName string) error {
+	return o.updateNodeExclusion(resources.GetOpenSearchHTTPEndpoint(vmi), nodeName, true)
+}
+
+//ClearNodeExclusion removes the node from the nodes excluded from shard allocation. The setting is reset to the
+// cluster default once no nodes are excluded.
+func (o *OSClient) ClearNodeExclusion(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, nodeName string) error {
+	return o.updateNodeExclusion(resources.GetOpenSearchHTTPEndpoint(vmi), nodeName, false)
+}
+
+func (o *OSClient) updateNodeExclusion(opensearchEndpoint, nodeName string, exclude bool) error {
+	existing, err := o.getClusterSettings(opensearchEndpoint)
+	if err != nil {
+		return err
+	}
+	var nodes []string
+	excluded := false
+	if value, ok := existing.Persistent[AllocationExcludeNameSetting]; ok {
+		for _, node := range strings.Split(settingValue(value), ",") {
+			node = strings.TrimSpace(node)
+			if node == "" {
+				continue
+			}
+			if node == nodeName {
+				excluded = true
+				if !exclude {
+					continue
+				}
+			}
+			nodes = append(nodes, node)
+		}
+	}
+	if excluded == exclude {
+		return nil
+	}
+	if exclude {
+		nodes = append(nodes, nodeName)
+	}
+	var setting interface{}
+	if len(nodes) > 0 {
+		setting = strings.Join(nodes, ",")
+	}
+	return o.putClusterSettings(opensearchEndpoint, &ClusterSettings{
+		Persistent: map[string]interface{}{
+			AllocationExcludeNameSetting: setting,
+		},
+	})
+}
+
+//GetNodeShardCount returns the number of shards allocated to the node, including shards relocating away from it
+func (o *OSClient) GetNodeShardCount(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, nodeName string) (int, error) {
+	url := resources.GetOpenSearchHTTPEndpoint(vmi) + "/_cat/shards?format=json&h=index,shard,state,node"
+	var shards []CatShard
+	if err := o.getJSON(url, &shards); err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, shard := range shards {
+		// relocating shards are reported as "<source> -> <ip> <id> <target>"
+		fields := strings.Fields(shard.Node)
+		if len(fields) > 0 && fields[0] == nodeName {
+			count++
+		}
+	}
+	return count, nil
+}
+
+//IsNodeInCluster returns true if the node is currently a member of the cluster
+func (o *OSClient) IsNodeInCluster(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, nodeName string) (bool, error) {
+	url := resources.GetOpenSearchHTTPEndpoint(vmi) + "/_cat/nodes?format=json&h=name"
+	var nodes []CatNode
+	if err := o.getJSON(url, &nodes); err != nil {
+		return false, err
+	}
+	for _, node := range nodes {
+		if node.Name == nodeName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (o *OSClient) getJSON(url string, v interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("got status code %d when requesting %s", resp.StatusCode, req.URL.Path)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
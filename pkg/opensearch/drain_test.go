@@ -0,0 +1,147 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const testCatShards = `[
+	{"index": "verrazzano-system", "shard": "0", "state": "STARTED", "node": "data-0"},
+	{"index": "verrazzano-system", "shard": "0", "state": "STARTED", "node": "data-1"},
+	{"index": "verrazzano-application-a", "shard": "0", "state": "RELOCATING", "node": "data-1 -> 10.0.0.1 abcdef data-2"},
+	{"index": "verrazzano-application-b", "shard": "0", "state": "UNASSIGNED", "node": null}
+]`
+
+const testCatNodes = `[{"name": "master-0"}, {"name": "data-0"}]`
+
+func testDrainVMI() *vmcontrollerv1.VerrazzanoMonitoringInstance {
+	return &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Elasticsearch: vmcontrollerv1.Elasticsearch{
+				Enabled: true,
+			},
+		},
+	}
+}
+
+func TestGetNodeShardCount(t *testing.T) {
+	var tests = []struct {
+		name     string
+		nodeName string
+		count    int
+		isError  bool
+	}{
+		{"counts started shards", "data-0", 1, false},
+		{"counts shards relocating away from the node", "data-1", 2, false},
+		{"does not count shards relocating to the node", "data-2", 0, false},
+		{"error when the request fails", "data-0", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := NewOSClient()
+			o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+				statusCode := http.StatusOK
+				if tt.isError {
+					statusCode = http.StatusInternalServerError
+				}
+				return &http.Response{
+					StatusCode: statusCode,
+					Body:       io.NopCloser(strings.NewReader(testCatShards)),
+				}, nil
+			}
+			count, err := o.GetNodeShardCount(testDrainVMI(), tt.nodeName)
+			assert.Equal(t, tt.isError, err != nil)
+			assert.Equal(t, tt.count, count)
+		})
+	}
+}
+
+func TestIsNodeInCluster(t *testing.T) {
+	o := NewOSClient()
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(testCatNodes)),
+		}, nil
+	}
+	inCluster, err := o.IsNodeInCluster(testDrainVMI(), "data-0")
+	assert.NoError(t, err)
+	assert.True(t, inCluster)
+	inCluster, err = o.IsNodeInCluster(testDrainVMI(), "data-1")
+	assert.NoError(t, err)
+	assert.False(t, inCluster)
+}
+
+func TestNodeExclusion(t *testing.T) {
+	var tests = []struct {
+		name     string
+		existing string
+		exclude  bool
+		expected []map[string]interface{}
+	}{
+		{
+			"node is excluded",
+			`{"persistent": {}}`,
+			true,
+			[]map[string]interface{}{{AllocationExcludeNameSetting: "data-0"}},
+		},
+		{
+			"node is added to the nodes excluded by others",
+			`{"persistent": {"cluster.routing.allocation.exclude._name": "user-node"}}`,
+			true,
+			[]map[string]interface{}{{AllocationExcludeNameSetting: "user-node,data-0"}},
+		},
+		{
+			"no update when the node is already excluded",
+			`{"persistent": {"cluster.routing.allocation.exclude._name": "user-node,data-0"}}`,
+			true,
+			nil,
+		},
+		{
+			"nodes excluded by others are kept when the exclusion is cleared",
+			`{"persistent": {"cluster.routing.allocation.exclude._name": "user-node,data-0"}}`,
+			false,
+			[]map[string]interface{}{{AllocationExcludeNameSetting: "user-node"}},
+		},
+		{
+			"setting is reset once no nodes are excluded",
+			`{"persistent": {"cluster.routing.allocation.exclude._name": "data-0"}}`,
+			false,
+			[]map[string]interface{}{{AllocationExcludeNameSetting: nil}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var settings []map[string]interface{}
+			o := NewOSClient()
+			o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+				if request.Method == "GET" {
+					return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(tt.existing))}, nil
+				}
+				clusterSettings := &ClusterSettings{}
+				assert.NoError(t, json.NewDecoder(request.Body).Decode(clusterSettings))
+				settings = append(settings, clusterSettings.Persistent)
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader("")),
+				}, nil
+			}
+			if tt.exclude {
+				assert.NoError(t, o.ExcludeNode(testDrainVMI(), "data-0"))
+			} else {
+				assert.NoError(t, o.ClearNodeExclusion(testDrainVMI(), "data-0"))
+			}
+			assert.Equal(t, tt.expected, settings)
+		})
+	}
+}
@@ -15,7 +15,21 @@ import (
 
 type (
 	ClusterHealth struct {
-		Status string `json:"status"`
+		ClusterName                 string  `json:"cluster_name"`
+		Status                      string  `json:"status"`
+		TimedOut                    bool    `json:"timed_out"`
+		NumberOfNodes               int     `json:"number_of_nodes"`
+		NumberOfDataNodes           int     `json:"number_of_data_nodes"`
+		ActivePrimaryShards         int     `json:"active_primary_shards"`
+		ActiveShards                int     `json:"active_shards"`
+		RelocatingShards            int     `json:"relocating_shards"`
+		InitializingShards          int     `json:"initializing_shards"`
+		UnassignedShards            int     `json:"unassigned_shards"`
+		DelayedUnassignedShards     int     `json:"delayed_unassigned_shards"`
+		NumberOfPendingTasks        int     `json:"number_of_pending_tasks"`
+		NumberOfInFlightFetch       int     `json:"number_of_in_flight_fetch"`
+		TaskMaxWaitingInQueueMillis int     `json:"task_max_waiting_in_queue_millis"`
+		ActiveShardsPercentAsNumber float64 `json:"active_shards_percent_as_number"`
 	}
 
 	NodeSettings struct {
@@ -23,6 +37,7 @@ type (
 	}
 
 	Node struct {
+		Name    string   `json:"name"`
 		Version string   `json:"version"`
 		Roles   []string `json:"roles"`
 	}
@@ -40,7 +55,8 @@ func (o *OSClient) opensearchHealth(vmo *vmcontrollerv1.VerrazzanoMonitoringInst
 		return err
 	}
 	if !(clusterHealth.Status == HealthGreen) {
-		return fmt.Errorf("OpenSearch health is %s", clusterHealth.Status)
+		return fmt.Errorf("OpenSearch health is %s (%d unassigned, %d initializing, %d relocating shards)",
+			clusterHealth.Status, clusterHealth.UnassignedShards, clusterHealth.InitializingShards, clusterHealth.RelocatingShards)
 	}
 
 	// Verify that the nodes are running the expected version
@@ -8,9 +8,13 @@ import (
 	"encoding/json"
 	"fmt"
 	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	nodetool "github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/nodes"
 	"net/http"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type (
@@ -66,9 +70,25 @@ const (
 	vmiManagedPolicy = "__vmi-managed__"
 )
 
+var (
+	indexAgeRegexp = regexp.MustCompile(`^([0-9]+)(d|h|m|s|ms|micros|nanos)$`)
+	indexAgeUnits  = map[string]time.Duration{
+		"d":      24 * time.Hour,
+		"h":      time.Hour,
+		"m":      time.Minute,
+		"s":      time.Second,
+		"ms":     time.Millisecond,
+		"micros": time.Microsecond,
+		"nanos":  time.Nanosecond,
+	}
+)
+
 //createISMPolicy creates an ISM policy if it does not exist, else the policy will be updated.
 // If the policy already exsts and its spec matches the VMO policy spec, no update will be issued
 func (o *OSClient) createISMPolicy(opensearchEndpoint string, policy vmcontrollerv1.IndexManagementPolicy) error {
+	if err := validateTiers(&policy); err != nil {
+		return fmt.Errorf("invalid tiers in ISM policy %s: %v", policy.PolicyName, err)
+	}
 	policyURL := fmt.Sprintf("%s/_plugins/_ism/policies/%s", opensearchEndpoint, policy.PolicyName)
 	existingPolicy, err := o.getPolicyByName(policyURL)
 	if err != nil {
@@ -279,6 +299,49 @@ func toISMPolicy(policy *vmcontrollerv1.IndexManagementPolicy) *ISMPolicy {
 		minIndexAge = *policy.MinIndexAge
 	}
 
+	// Indices are ingested, then moved through the tiers before they are deleted
+	states := []PolicyState{
+		{
+			Name: "ingest",
+			Actions: []map[string]interface{}{
+				rolloverAction,
+			},
+		},
+	}
+	for _, tier := range policy.Tiers {
+		states[len(states)-1].Transitions = []PolicyTransition{
+			{
+				StateName: tier.Tier,
+				Conditions: PolicyConditions{
+					MinIndexAge: tier.MinIndexAge,
+				},
+			},
+		}
+		states = append(states, PolicyState{
+			Name: tier.Tier,
+			Actions: []map[string]interface{}{
+				createAllocationAction(tier.Tier),
+			},
+		})
+	}
+	states[len(states)-1].Transitions = []PolicyTransition{
+		{
+			StateName: "delete",
+			Conditions: PolicyConditions{
+				MinIndexAge: minIndexAge,
+			},
+		},
+	}
+	states = append(states, PolicyState{
+		Name: "delete",
+		Actions: []map[string]interface{}{
+			{
+				"delete": map[string]interface{}{},
+			},
+		},
+		Transitions: []PolicyTransition{},
+	})
+
 	return &ISMPolicy{
 		Policy: InlinePolicy{
 			DefaultState: "ingest",
@@ -291,31 +354,65 @@ func toISMPolicy(policy *vmcontrollerv1.IndexManagementPolicy) *ISMPolicy {
 					},
 				},
 			},
-			States: []PolicyState{
-				{
-					Name: "ingest",
-					Actions: []map[string]interface{}{
-						rolloverAction,
-					},
-					Transitions: []PolicyTransition{
-						{
-							StateName: "delete",
-							Conditions: PolicyConditions{
-								MinIndexAge: minIndexAge,
-							},
-						},
-					},
-				},
-				{
-					Name: "delete",
-					Actions: []map[string]interface{}{
-						{
-							"delete": map[string]interface{}{},
-						},
-					},
-					Transitions: []PolicyTransition{},
-				},
+			States: states,
+		},
+	}
+}
+
+//validateTiers checks that the tiers do not collide with the ingest and delete states of the policy, and that indices
+// reach the tiers in order of their minimum index age, before they are deleted
+func validateTiers(policy *vmcontrollerv1.IndexManagementPolicy) error {
+	minIndexAge := defaultMinIndexAge
+	if policy.MinIndexAge != nil {
+		minIndexAge = *policy.MinIndexAge
+	}
+	deleteAge, err := parseIndexAge(minIndexAge)
+	if err != nil {
+		return err
+	}
+	states := map[string]bool{"ingest": true, "delete": true}
+	var previousAge time.Duration
+	for i, tier := range policy.Tiers {
+		if states[tier.Tier] {
+			return fmt.Errorf("tier %s collides with another state of the policy", tier.Tier)
+		}
+		states[tier.Tier] = true
+		age, err := parseIndexAge(tier.MinIndexAge)
+		if err != nil {
+			return err
+		}
+		if i > 0 && age <= previousAge {
+			return fmt.Errorf("tier %s must have a larger minimum index age than tier %s", tier.Tier, policy.Tiers[i-1].Tier)
+		}
+		if age >= deleteAge {
+			return fmt.Errorf("tier %s must have a smaller minimum index age than the policy's %s", tier.Tier, minIndexAge)
+		}
+		previousAge = age
+	}
+	return nil
+}
+
+//parseIndexAge parses an ISM index age, e.g., 7d
+func parseIndexAge(age string) (time.Duration, error) {
+	match := indexAgeRegexp.FindStringSubmatch(age)
+	if match == nil {
+		return 0, fmt.Errorf("invalid index age %s", age)
+	}
+	n, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid index age %s: %v", age, err)
+	}
+	return time.Duration(n) * indexAgeUnits[match[2]], nil
+}
+
+//createAllocationAction moves an index to the nodes of a tier
+func createAllocationAction(tier string) map[string]interface{} {
+	return map[string]interface{}{
+		"allocation": map[string]interface{}{
+			"require": map[string]interface{}{
+				nodetool.TierAttribute: tier,
 			},
+			"wait_for": false,
 		},
 	}
 }
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch/opensearchtest"
 	"io"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"net/http"
@@ -157,6 +158,28 @@ func TestConfigureIndexManagementPluginHappyPath(t *testing.T) {
 	assert.NoError(t, <-ch)
 }
 
+// TestConfigureISMFakeOpenSearch Tests configuring ISM policies of a fake OpenSearch cluster
+// GIVEN a fake OpenSearch cluster with an index
+// WHEN I call ConfigureISM repeatedly, and finally without policies
+// THEN the policy is created and added to the index, and deleted once it is no longer configured
+func TestConfigureISMFakeOpenSearch(t *testing.T) {
+	fake := opensearchtest.NewFakeOpenSearch()
+	defer fake.Close()
+	fake.AddIndex("verrazzano-system", 1)
+	o := NewOSClient()
+	o.DoHTTP = fake.DoHTTP
+
+	vmi := createISMVMI("1d", true)
+	assert.NoError(t, <-o.ConfigureISM(vmi))
+	assert.Equal(t, []string{"verrazzano-system"}, fake.Policies())
+	assert.Equal(t, map[string]string{"verrazzano-system": "verrazzano-system"}, fake.ManagedIndices())
+	assert.NoError(t, <-o.ConfigureISM(vmi))
+
+	vmi.Spec.Elasticsearch.Policies = nil
+	assert.NoError(t, <-o.ConfigureISM(vmi))
+	assert.Empty(t, fake.Policies())
+}
+
 // TestGetPolicyByName Tests retrieving ISM policies by name
 // GIVEN an OpenSearch instance
 // WHEN I call getPolicyByName
@@ -445,3 +468,66 @@ func TestIsEligibleForDeletion(t *testing.T) {
 		})
 	}
 }
+
+// TestToISMPolicyTiers Tests the allocation of aging indices to node tiers
+// GIVEN a policy with node tiers
+// WHEN I call toISMPolicy
+// THEN indices move through the tiers in order before they are deleted
+func TestToISMPolicyTiers(t *testing.T) {
+	policy := createTestPolicy("30d", "1d", "verrazzano-system", "10gb", 1000)
+	policy.Tiers = []vmcontrollerv1.TierAllocationPolicy{
+		{Tier: "warm", MinIndexAge: "3d"},
+		{Tier: "cold", MinIndexAge: "14d"},
+	}
+	states := toISMPolicy(policy).Policy.States
+	assert.Len(t, states, 4)
+	var transitions []PolicyTransition
+	for _, state := range states {
+		transitions = append(transitions, state.Transitions...)
+	}
+	assert.Equal(t, []PolicyTransition{
+		{StateName: "warm", Conditions: PolicyConditions{MinIndexAge: "3d"}},
+		{StateName: "cold", Conditions: PolicyConditions{MinIndexAge: "14d"}},
+		{StateName: "delete", Conditions: PolicyConditions{MinIndexAge: "30d"}},
+	}, transitions)
+	assert.Equal(t, "warm", states[1].Name)
+	assert.Equal(t, createAllocationAction("warm"), states[1].Actions[0])
+
+	payload, err := serializeIndexManagementPolicy(policy)
+	assert.NoError(t, err)
+	assert.Contains(t, string(payload), `"allocation":{"require":{"tier":"cold"},"wait_for":false}`)
+}
+
+// TestValidateTiers Tests the validation of the tiers of a policy
+// GIVEN a policy with node tiers
+// WHEN I call validateTiers
+// THEN tiers which collide with the policy states, or are not in order of their minimum index age, are rejected
+func TestValidateTiers(t *testing.T) {
+	var tests = []struct {
+		name    string
+		tiers   []vmcontrollerv1.TierAllocationPolicy
+		isError bool
+	}{
+		{"no tiers", nil, false},
+		{"ascending tiers", []vmcontrollerv1.TierAllocationPolicy{{Tier: "warm", MinIndexAge: "36h"}, {Tier: "cold", MinIndexAge: "14d"}}, false},
+		{"ingest tier", []vmcontrollerv1.TierAllocationPolicy{{Tier: "ingest", MinIndexAge: "1d"}}, true},
+		{"delete tier", []vmcontrollerv1.TierAllocationPolicy{{Tier: "delete", MinIndexAge: "1d"}}, true},
+		{"duplicate tier", []vmcontrollerv1.TierAllocationPolicy{{Tier: "warm", MinIndexAge: "1d"}, {Tier: "warm", MinIndexAge: "2d"}}, true},
+		{"descending tiers", []vmcontrollerv1.TierAllocationPolicy{{Tier: "warm", MinIndexAge: "14d"}, {Tier: "cold", MinIndexAge: "2d"}}, true},
+		{"same age", []vmcontrollerv1.TierAllocationPolicy{{Tier: "warm", MinIndexAge: "2d"}, {Tier: "cold", MinIndexAge: "48h"}}, true},
+		{"tier after deletion", []vmcontrollerv1.TierAllocationPolicy{{Tier: "warm", MinIndexAge: "30d"}}, true},
+		{"invalid age", []vmcontrollerv1.TierAllocationPolicy{{Tier: "warm", MinIndexAge: "soon"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := createTestPolicy("30d", "1d", "verrazzano-system", "10gb", 1000)
+			policy.Tiers = tt.tiers
+			err := validateTiers(policy)
+			if tt.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
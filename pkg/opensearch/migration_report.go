@@ -0,0 +1,102 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"encoding/json"
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	"strconv"
+)
+
+type (
+	//IndexMigrationPlan describes how an old index would be migrated to a data stream
+	IndexMigrationPlan struct {
+		Index      string `yaml:"index"`
+		DataStream string `yaml:"dataStream"`
+		// DataStreamExists is true when the documents would be added to an existing data stream
+		DataStreamExists bool  `yaml:"dataStreamExists"`
+		Documents        int64 `yaml:"documents"`
+		SizeBytes        int64 `yaml:"sizeBytes"`
+		// Retention is empty when all documents are reindexed
+		Retention          string `yaml:"retention,omitempty"`
+		Query              string `yaml:"query,omitempty"`
+		ReindexedDocuments int64  `yaml:"reindexedDocuments"`
+		DroppedDocuments   int64  `yaml:"droppedDocuments"`
+	}
+
+	CatIndex struct {
+		Index     string `json:"index"`
+		StoreSize string `json:"store.size"`
+	}
+)
+
+//GetIndexMigrations returns the pending migration of each old index, as the migration rules of the VMI map them to
+// data streams, without changing any index
+func (o *OSClient) GetIndexMigrations(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) ([]vmcontrollerv1.IndexMigrationStatus, error) {
+	indices, err := o.getIndices(log, resources.GetOpenSearchHTTPEndpoint(vmi))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve OpenSearch index list: %v", err)
+	}
+	return getIndexMigrations(vmi, indices)
+}
+
+//GetIndexMigrationPlans returns what the migrations would do, without changing any index.
+// The documents outside of the retention window would not be reindexed, and are reported as dropped.
+func (o *OSClient) GetIndexMigrationPlans(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, migrations []vmcontrollerv1.IndexMigrationStatus) ([]IndexMigrationPlan, error) {
+	if len(migrations) == 0 {
+		return nil, nil
+	}
+	openSearchEndpoint := resources.GetOpenSearchHTTPEndpoint(vmi)
+	sizes, err := o.getIndexSizes(openSearchEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var plans []IndexMigrationPlan
+	for _, migration := range migrations {
+		plan := IndexMigrationPlan{
+			Index:      migration.Index,
+			DataStream: migration.DataStream,
+			SizeBytes:  sizes[migration.Index],
+			Retention:  migration.Retention,
+		}
+		if plan.Documents, err = o.countDocuments(log, openSearchEndpoint, migration.Index, nil); err != nil {
+			return nil, err
+		}
+		plan.ReindexedDocuments = plan.Documents
+		reindexPayload := createReindexPayload(migration.Index, migration.DataStream, migration.Retention, vmi.Spec.Elasticsearch.Migration)
+		if query := reindexPayload.Source.Query; query != nil {
+			queryJSON, err := json.Marshal(query)
+			if err != nil {
+				return nil, err
+			}
+			plan.Query = string(queryJSON)
+			if plan.ReindexedDocuments, err = o.countDocuments(log, openSearchEndpoint, migration.Index, query); err != nil {
+				return nil, err
+			}
+		}
+		plan.DroppedDocuments = plan.Documents - plan.ReindexedDocuments
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+//getIndexSizes returns the store size of each index in bytes
+func (o *OSClient) getIndexSizes(openSearchEndpoint string) (map[string]int64, error) {
+	var catIndices []CatIndex
+	if err := o.getJSON(openSearchEndpoint+"/_cat/indices?format=json&h=index,store.size&bytes=b", &catIndices); err != nil {
+		return nil, err
+	}
+	sizes := map[string]int64{}
+	for _, catIndex := range catIndices {
+		// closed indices have no store size
+		if size, err := strconv.ParseInt(catIndex.StoreSize, 10, 64); err == nil {
+			sizes[catIndex.Index] = size
+		}
+	}
+	return sizes, nil
+}
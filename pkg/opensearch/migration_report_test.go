@@ -0,0 +1,92 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestGetIndexMigrationPlans Tests the migration dry run report
+// GIVEN old indices with documents outside of the retention window
+// WHEN I call GetIndexMigrationPlans
+// THEN the documents, sizes and dropped documents of each index are reported, and no index is changed
+func TestGetIndexMigrationPlans(t *testing.T) {
+	var tests = []struct {
+		name    string
+		indices string
+		plans   []IndexMigrationPlan
+	}{
+		{
+			"no indices to migrate",
+			`{".kibana_1": {"aliases": {}}}`,
+			nil,
+		},
+		{
+			"reports the indices to migrate",
+			`{"verrazzano-namespace-verrazzano-system": {"aliases": {}}, "verrazzano-namespace-bobs-books": {"aliases": {}}}`,
+			[]IndexMigrationPlan{
+				{
+					Index:              "verrazzano-namespace-bobs-books",
+					DataStream:         "verrazzano-application-bobs-books",
+					Documents:          100,
+					SizeBytes:          2048,
+					Retention:          "86400s",
+					Query:              `{"range":{"@timestamp":{"gte":"now-86400s","lt":"now/s"}}}`,
+					ReindexedDocuments: 60,
+					DroppedDocuments:   40,
+				},
+				{
+					Index:              "verrazzano-namespace-verrazzano-system",
+					DataStream:         config.DataStreamName(),
+					Documents:          100,
+					SizeBytes:          1024,
+					Retention:          "86400s",
+					Query:              `{"range":{"@timestamp":{"gte":"now-86400s","lt":"now/s"}}}`,
+					ReindexedDocuments: 60,
+					DroppedDocuments:   40,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := NewOSClient()
+			o.DoHTTP = func(req *http.Request) (*http.Response, error) {
+				body := ""
+				switch {
+				case req.URL.Path == "/_aliases":
+					body = tt.indices
+				case req.URL.Path == "/_cat/indices":
+					assert.Equal(t, "b", req.URL.Query().Get("bytes"))
+					body = `[{"index": "verrazzano-namespace-verrazzano-system", "store.size": "1024"}, {"index": "verrazzano-namespace-bobs-books", "store.size": "2048"}, {"index": "closed", "store.size": null}]`
+				case strings.HasSuffix(req.URL.Path, "/_count"):
+					query, _ := ioutil.ReadAll(req.Body)
+					body = `{"count": 100}`
+					if len(query) > 0 {
+						body = `{"count": 60}`
+					}
+				default:
+					t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(body)),
+				}, nil
+			}
+			vmi := createISMVMI("1d", true)
+			migrations, err := o.GetIndexMigrations(vzlog.DefaultLogger(), vmi)
+			assert.NoError(t, err)
+			plans, err := o.GetIndexMigrationPlans(vzlog.DefaultLogger(), vmi, migrations)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.plans, plans)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//DefaultIndexMigrationRules returns the built-in rules, which migrate the Verrazzano system indices to the system
+// data stream, and the namespace indices of applications to per-namespace application data streams
+func DefaultIndexMigrationRules() []vmcontrollerv1.IndexMigrationRule {
+	var systemNamespaces []string
+	for _, namespace := range config.SystemNamespaces() {
+		systemNamespaces = append(systemNamespaces, regexp.QuoteMeta(namespace))
+	}
+	return []vmcontrollerv1.IndexMigrationRule{
+		{
+			Source:      fmt.Sprintf("verrazzano-namespace-(%s)", strings.Join(systemNamespaces, "|")),
+			Destination: config.DataStreamName(),
+		},
+		{
+			Source:      ".*verrazzano-systemd-journal.*",
+			Destination: config.DataStreamName(),
+		},
+		{
+			Source:      "verrazzano-logstash-.*",
+			Destination: config.DataStreamName(),
+		},
+		{
+			Source:      "verrazzano-namespace-(.+)",
+			Destination: "verrazzano-application-${1}",
+		},
+	}
+}
+
+//getIndexMigrationRules returns the VMI migration rules, followed by the default rules
+func getIndexMigrationRules(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) []vmcontrollerv1.IndexMigrationRule {
+	var rules []vmcontrollerv1.IndexMigrationRule
+	if settings := vmi.Spec.Elasticsearch.Migration; settings != nil {
+		rules = append(rules, settings.Rules...)
+	}
+	return append(rules, DefaultIndexMigrationRules()...)
+}
+
+//getIndexMigrations returns a pending migration for each index matched by a migration rule, in index name order.
+// Hidden and system indices are never migrated, whatever the rules are.
+func getIndexMigrations(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, indices []string) ([]vmcontrollerv1.IndexMigrationStatus, error) {
+	rules := getIndexMigrationRules(vmi)
+	sorted := append([]string{}, indices...)
+	sort.Strings(sorted)
+	var migrations []vmcontrollerv1.IndexMigrationStatus
+	for _, index := range sorted {
+		if isHiddenIndex(index) {
+			continue
+		}
+		rule, dataStream, err := matchIndexMigrationRule(rules, index)
+		if err != nil {
+			return nil, err
+		}
+		// an index is never reindexed into itself
+		if rule == nil || dataStream == "" || dataStream == index {
+			continue
+		}
+		retention, err := getIndexMigrationRetention(vmi, rule, dataStream)
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, vmcontrollerv1.IndexMigrationStatus{
+			Index:      index,
+			DataStream: dataStream,
+			Phase:      vmcontrollerv1.IndexMigrationPending,
+			Retention:  retention,
+		})
+	}
+	return migrations, nil
+}
+
+//isHiddenIndex returns true for hidden and system indices, e.g., .kibana*, .opendistro*, .plugins*, and the
+// .ds-* backing indices of data streams
+func isHiddenIndex(index string) bool {
+	return strings.HasPrefix(index, ".")
+}
+
+//matchIndexMigrationRule returns the first rule which applies to the index, and the index's destination data stream
+func matchIndexMigrationRule(rules []vmcontrollerv1.IndexMigrationRule, index string) (*vmcontrollerv1.IndexMigrationRule, string, error) {
+	for i := range rules {
+		rule := &rules[i]
+		source, err := regexp.Compile("^(?:" + rule.Source + ")$")
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid index migration rule source %s: %v", rule.Source, err)
+		}
+		match := source.FindStringSubmatchIndex(index)
+		if match == nil {
+			continue
+		}
+		excluded, err := isExcludedIndex(rule, index)
+		if err != nil {
+			return nil, "", err
+		}
+		if excluded {
+			continue
+		}
+		return rule, string(source.ExpandString(nil, rule.Destination, index, match)), nil
+	}
+	return nil, "", nil
+}
+
+func isExcludedIndex(rule *vmcontrollerv1.IndexMigrationRule, index string) (bool, error) {
+	for _, exclude := range rule.Exclude {
+		matched, err := regexp.MatchString("^(?:"+exclude+")$", index)
+		if err != nil {
+			return false, fmt.Errorf("invalid index migration rule exclusion %s: %v", exclude, err)
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+//getIndexMigrationRetention returns the rule's retention in seconds, or the retention of the ISM policy matching the data stream
+func getIndexMigrationRetention(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, rule *vmcontrollerv1.IndexMigrationRule, dataStream string) (string, error) {
+	if rule.Retention == nil {
+		return getRetentionAgeInSeconds(vmi, dataStream)
+	}
+	seconds, err := calculateSeconds(*rule.Retention)
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate the retention age in seconds: %v", err)
+	}
+	return fmt.Sprintf("%ds", seconds), nil
+}
@@ -0,0 +1,123 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
+	"testing"
+)
+
+// TestGetIndexMigrations Tests that indices are mapped to data streams by the migration rules
+// GIVEN migration rules in the VMI
+// WHEN I call getIndexMigrations
+// THEN the first matching rule determines the data stream and retention of each index
+func TestGetIndexMigrations(t *testing.T) {
+	sevenDays := "7d"
+	var tests = []struct {
+		name        string
+		rules       []vmcontrollerv1.IndexMigrationRule
+		dataStreams map[string]string
+		retention   map[string]string
+		isError     bool
+	}{
+		{
+			"default rules",
+			nil,
+			map[string]string{
+				"verrazzano-namespace-verrazzano-system": config.DataStreamName(),
+				"verrazzano-logstash-2022.01.01":         config.DataStreamName(),
+				"verrazzano-namespace-todo":              "verrazzano-application-todo",
+			},
+			map[string]string{"verrazzano-namespace-todo": "86400s"},
+			false,
+		},
+		{
+			"custom rules with a destination template are evaluated first",
+			[]vmcontrollerv1.IndexMigrationRule{
+				{Source: `orders-(\w+)-\d{4}\.\d{2}`, Destination: "verrazzano-application-${1}", Retention: &sevenDays},
+				{Source: "verrazzano-namespace-todo", Destination: "verrazzano-application-tasks"},
+			},
+			map[string]string{
+				"orders-eu-2022.01":                      "verrazzano-application-eu",
+				"orders-us-2022.02":                      "verrazzano-application-us",
+				"verrazzano-namespace-todo":              "verrazzano-application-tasks",
+				"verrazzano-namespace-verrazzano-system": config.DataStreamName(),
+				"verrazzano-logstash-2022.01.01":         config.DataStreamName(),
+			},
+			map[string]string{"orders-eu-2022.01": "604800s"},
+			false,
+		},
+		{
+			"excluded indices fall through to the next rule, and an empty destination keeps the index",
+			[]vmcontrollerv1.IndexMigrationRule{
+				{Source: "verrazzano-namespace-.*", Destination: "verrazzano-application-all", Exclude: []string{".*-todo"}},
+				{Source: "verrazzano-namespace-todo"},
+			},
+			map[string]string{
+				"verrazzano-namespace-verrazzano-system": "verrazzano-application-all",
+				"verrazzano-logstash-2022.01.01":         config.DataStreamName(),
+			},
+			nil,
+			false,
+		},
+		{
+			"hidden and system indices are skipped, even when a rule matches them",
+			[]vmcontrollerv1.IndexMigrationRule{{Source: ".*", Destination: "verrazzano-application-all"}},
+			map[string]string{
+				"verrazzano-namespace-verrazzano-system": "verrazzano-application-all",
+				"verrazzano-namespace-todo":              "verrazzano-application-all",
+				"verrazzano-logstash-2022.01.01":         "verrazzano-application-all",
+				"orders-eu-2022.01":                      "verrazzano-application-all",
+				"orders-us-2022.02":                      "verrazzano-application-all",
+				"orders":                                 "verrazzano-application-all",
+			},
+			nil,
+			false,
+		},
+		{
+			"invalid source pattern",
+			[]vmcontrollerv1.IndexMigrationRule{{Source: "orders-(", Destination: "orders"}},
+			nil,
+			nil,
+			true,
+		},
+	}
+
+	indices := []string{
+		"verrazzano-namespace-verrazzano-system",
+		"verrazzano-namespace-todo",
+		"verrazzano-logstash-2022.01.01",
+		"orders-eu-2022.01",
+		"orders-us-2022.02",
+		"orders",
+		".kibana_1",
+		".opendistro-job-scheduler-lock",
+		".plugins-ml-config",
+		".opensearch-notifications-config",
+		".ds-verrazzano-systemd-journal-000001",
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vmi := createISMVMI("1d", true)
+			vmi.Spec.Elasticsearch.Migration = &vmcontrollerv1.IndexMigrationSettings{Rules: tt.rules}
+			migrations, err := getIndexMigrations(vmi, indices)
+			if tt.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			dataStreams := map[string]string{}
+			for _, migration := range migrations {
+				dataStreams[migration.Index] = migration.DataStream
+				assert.Equal(t, vmcontrollerv1.IndexMigrationPending, migration.Phase)
+				if retention, ok := tt.retention[migration.Index]; ok {
+					assert.Equal(t, retention, migration.Retention)
+				}
+			}
+			assert.Equal(t, tt.dataStreams, dataStreams)
+		})
+	}
+}
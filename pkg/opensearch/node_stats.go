@@ -0,0 +1,103 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"regexp"
+	"sort"
+)
+
+type (
+	NodeStatsList struct {
+		Nodes map[string]NodeStats `json:"nodes"`
+	}
+
+	NodeStats struct {
+		Name    string       `json:"name"`
+		JVM     JVMStats     `json:"jvm"`
+		FS      FSStats      `json:"fs"`
+		Indices IndicesStats `json:"indices"`
+	}
+
+	JVMStats struct {
+		Mem struct {
+			HeapUsedInBytes int64 `json:"heap_used_in_bytes"`
+			HeapMaxInBytes  int64 `json:"heap_max_in_bytes"`
+		} `json:"mem"`
+		GC struct {
+			Collectors map[string]GCStats `json:"collectors"`
+		} `json:"gc"`
+	}
+
+	GCStats struct {
+		CollectionCount        int64 `json:"collection_count"`
+		CollectionTimeInMillis int64 `json:"collection_time_in_millis"`
+	}
+
+	FSStats struct {
+		Total struct {
+			TotalInBytes     int64 `json:"total_in_bytes"`
+			AvailableInBytes int64 `json:"available_in_bytes"`
+		} `json:"total"`
+	}
+
+	IndicesStats struct {
+		Indexing struct {
+			IndexTotal        int64 `json:"index_total"`
+			IndexTimeInMillis int64 `json:"index_time_in_millis"`
+		} `json:"indexing"`
+		Search struct {
+			QueryTotal        int64 `json:"query_total"`
+			QueryTimeInMillis int64 `json:"query_time_in_millis"`
+		} `json:"search"`
+	}
+
+	//ClusterStats is a snapshot of the OpenSearch statistics exported as operator metrics
+	ClusterStats struct {
+		Health *ClusterHealth
+		// sorted by node name
+		Nodes []NodeStats
+		// store size of each data stream, including its replicas
+		DataStreamSizes map[string]int64
+	}
+)
+
+// backing indices of data streams are named .ds-<data stream>-<generation>
+var backingIndexPattern = regexp.MustCompile(`^\.ds-(.+)-[0-9]{6}$`)
+
+//GetClusterStats returns the health, node statistics and data stream sizes of the OpenSearch cluster
+func (o *OSClient) GetClusterStats(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) (*ClusterStats, error) {
+	health, err := o.getOpenSearchClusterHealth(vmi)
+	if err != nil {
+		return nil, err
+	}
+	openSearchEndpoint := resources.GetOpenSearchHTTPEndpoint(vmi)
+	nodeStats := &NodeStatsList{}
+	if err := o.getJSON(openSearchEndpoint+"/_nodes/stats/jvm,fs,indices", nodeStats); err != nil {
+		return nil, err
+	}
+	indexSizes, err := o.getIndexSizes(openSearchEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &ClusterStats{
+		Health:          health,
+		DataStreamSizes: map[string]int64{},
+	}
+	for _, node := range nodeStats.Nodes {
+		stats.Nodes = append(stats.Nodes, node)
+	}
+	sort.Slice(stats.Nodes, func(i, j int) bool {
+		return stats.Nodes[i].Name < stats.Nodes[j].Name
+	})
+	for index, size := range indexSizes {
+		if match := backingIndexPattern.FindStringSubmatch(index); match != nil {
+			stats.DataStreamSizes[match[1]] += size
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,59 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestGetClusterStats Tests collecting the statistics exported as operator metrics
+// GIVEN an OpenSearch cluster with nodes and data streams
+// WHEN I call GetClusterStats
+// THEN the health, sorted node statistics, and data stream sizes are returned
+func TestGetClusterStats(t *testing.T) {
+	responses := map[string]string{
+		"/_cluster/health": `{"status": "green", "active_shards": 10, "unassigned_shards": 2}`,
+		"/_nodes/stats/jvm,fs,indices": `{"nodes": {
+			"b": {"name": "vmi-system-es-master-1", "jvm": {"mem": {"heap_used_in_bytes": 100}, "gc": {"collectors": {"young": {"collection_count": 5}}}}},
+			"a": {"name": "vmi-system-es-master-0", "fs": {"total": {"total_in_bytes": 1000, "available_in_bytes": 400}}, "indices": {"indexing": {"index_total": 42}}}
+		}}`,
+		"/_cat/indices": `[
+			{"index": ".ds-verrazzano-system-000001", "store.size": "100"},
+			{"index": ".ds-verrazzano-system-000002", "store.size": "50"},
+			{"index": ".ds-verrazzano-application-todo-000001", "store.size": "20"},
+			{"index": "verrazzano-namespace-todo", "store.size": "30"},
+			{"index": ".ds-closed-000001", "store.size": null}
+		]`,
+	}
+	o := NewOSClient()
+	o.DoHTTP = func(req *http.Request) (*http.Response, error) {
+		body, ok := responses[req.URL.Path]
+		statusCode := http.StatusOK
+		if !ok {
+			statusCode = http.StatusNotFound
+		}
+		return &http.Response{
+			StatusCode: statusCode,
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil
+	}
+	stats, err := o.GetClusterStats(createISMVMI("1d", true))
+	assert.NoError(t, err)
+	assert.Equal(t, 10, stats.Health.ActiveShards)
+	assert.Len(t, stats.Nodes, 2)
+	assert.Equal(t, "vmi-system-es-master-0", stats.Nodes[0].Name)
+	assert.Equal(t, int64(400), stats.Nodes[0].FS.Total.AvailableInBytes)
+	assert.Equal(t, int64(42), stats.Nodes[0].Indices.Indexing.IndexTotal)
+	assert.Equal(t, int64(5), stats.Nodes[1].JVM.GC.Collectors["young"].CollectionCount)
+	assert.Equal(t, map[string]int64{"verrazzano-system": 150, "verrazzano-application-todo": 20}, stats.DataStreamSizes)
+
+	// statistics are not collected while OpenSearch is unavailable
+	delete(responses, "/_cluster/health")
+	_, err = o.GetClusterStats(createISMVMI("1d", true))
+	assert.Error(t, err)
+}
@@ -7,16 +7,53 @@ import (
 	"fmt"
 	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
 	"net/http"
 )
 
 type (
+	//OpenSearch is the API of the OpenSearch cluster of a VMI, as used by the controller
+	OpenSearch interface {
+		IsDataResizable(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error
+		IsUpdated(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error
+		IsGreen(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error
+		GetClusterHealth(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) (*ClusterHealth, error)
+		GetHealthDiagnostics(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) (*HealthDiagnostics, error)
+		GetClusterStats(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) (*ClusterStats, error)
+		GetNodes(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) ([]Node, error)
+		GetNodeDiskUsage(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) (map[string]int, error)
+
+		ConfigureISM(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) chan error
+		ConfigureClusterSettings(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, settings, previous map[string]string) chan error
+		ConfigureSecurity(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, credentials map[string]InternalUserCredentials) chan error
+		ConfigureTenants(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) chan error
+		ConfigureAlerting(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, channelURLs map[string]string) chan error
+
+		ExcludeNode(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, nodeName string) error
+		ClearNodeExclusion(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, nodeName string) error
+		GetNodeShardCount(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, nodeName string) (int, error)
+		IsNodeInCluster(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, nodeName string) (bool, error)
+		AddVotingConfigExclusion(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, nodeName string) error
+		ClearVotingConfigExclusions(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) error
+		GetElectedMaster(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) (string, error)
+		PrepareNodeRestart(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) error
+		FinishNodeRestart(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) error
+
+		GetIndexMigrations(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) ([]vmcontrollerv1.IndexMigrationStatus, error)
+		GetIndexMigrationPlans(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, migrations []vmcontrollerv1.IndexMigrationStatus) ([]IndexMigrationPlan, error)
+		MigrateIndicesToDataStreams(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, openSearchEndpoint string) (bool, error)
+		DataStreamExists(openSearchEndpoint, dataStream string) (bool, error)
+	}
+
+	//OSClient implements OpenSearch over HTTP. Requests are sent by DoHTTP, which tests may replace.
 	OSClient struct {
 		httpClient *http.Client
 		DoHTTP     func(request *http.Request) (*http.Response, error)
 	}
 )
 
+var _ OpenSearch = &OSClient{}
+
 func NewOSClient() *OSClient {
 	o := &OSClient{
 		httpClient: http.DefaultClient,
@@ -12,10 +12,10 @@ import (
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
 	"io/ioutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"net/http"
 	"regexp"
 	"strconv"
-	"strings"
 )
 
 var (
@@ -35,11 +35,15 @@ type (
 		Conflicts string `json:"conflicts"`
 		Source    `json:"source"`
 		Dest      `json:"dest"`
+		// RequestsPerSecond and Slices are passed as request parameters
+		RequestsPerSecond *int32 `json:"-"`
+		Slices            string `json:"-"`
 	}
 
 	Source struct {
 		Index string `json:"index"`
 		Query *Query `json:"query,omitempty"`
+		Size  *int32 `json:"size,omitempty"`
 	}
 
 	Query struct {
@@ -59,40 +63,135 @@ type (
 		Index  string `json:"index"`
 		OpType string `json:"op_type"`
 	}
-)
 
-// Reindex old style indices to data streams and delete it
-func (o *OSClient) MigrateIndicesToDataStreams(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, openSearchEndpoint string) error {
-	log.Debugf("Checking for OpenSearch indices to migrate to data streams.")
-	// Get the indices
-	indices, err := o.getIndices(log, openSearchEndpoint)
-	if err != nil {
-		return fmt.Errorf("failed to retrieve OpenSearch index list: %v", err)
+	ReindexTaskResponse struct {
+		Task string `json:"task"`
+	}
+
+	Task struct {
+		Completed bool `json:"completed"`
+		Task      struct {
+			Status ReindexStatus `json:"status"`
+		} `json:"task"`
+		Response ReindexStatus `json:"response"`
+		Error    *TaskError    `json:"error,omitempty"`
+	}
+
+	ReindexStatus struct {
+		Total            int64         `json:"total"`
+		Created          int64         `json:"created"`
+		VersionConflicts int64         `json:"version_conflicts"`
+		Failures         []interface{} `json:"failures,omitempty"`
+	}
+
+	TaskError struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
 	}
-	systemIndices := getSystemIndices(log, indices)
-	appIndices := getApplicationIndices(log, indices)
 
-	if len(systemIndices) > 0 || len(appIndices) > 0 {
+	CountResponse struct {
+		Count int64 `json:"count"`
+	}
+)
+
+// maxReindexAttempts is the number of times an index is reindexed before its migration fails
+const maxReindexAttempts = 3
+
+//MigrateIndicesToDataStreams reindexes old style indices to data streams, and deletes them.
+// Each index is reindexed by an OpenSearch task, and the migration progress is kept in the VMI status, so an
+// interrupted migration is resumed. A source index is only deleted once all of its documents were reindexed.
+// Returns true once all indices were migrated, or once the migrations which failed after maxReindexAttempts are all
+// that is left. Failed migrations are kept in the VMI status with their source index, and are not retried.
+func (o *OSClient) MigrateIndicesToDataStreams(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, openSearchEndpoint string) (bool, error) {
+	if isIndexMigrationParked(vmi) {
+		return true, nil
+	}
+	if len(vmi.Status.Elasticsearch.IndexMigrations) == 0 {
+		log.Debugf("Checking for OpenSearch indices to migrate to data streams.")
+		indices, err := o.getIndices(log, openSearchEndpoint)
+		if err != nil {
+			return false, fmt.Errorf("failed to retrieve OpenSearch index list: %v", err)
+		}
+		migrations, err := getIndexMigrations(vmi, indices)
+		if err != nil {
+			return false, err
+		}
+		if len(migrations) == 0 {
+			log.Debug("Found no indices to migrate to data streams")
+			return true, nil
+		}
 		log.Info("Migrating Verrazzano indices to data streams")
+		vmi.Status.Elasticsearch.IndexMigrations = migrations
 	}
 
-	// Reindex and delete old system indices
-	err = o.reindexAndDeleteIndices(log, vmi, openSearchEndpoint, systemIndices, true)
-	if err != nil {
-		return fmt.Errorf("failed to migrate the Verrazzano system indices to data streams: %v", err)
+	migrations := vmi.Status.Elasticsearch.IndexMigrations
+	running := 0
+	for i := range migrations {
+		migration := &migrations[i]
+		if migration.Phase == vmcontrollerv1.IndexMigrationReindexing {
+			if err := o.checkReindex(log, openSearchEndpoint, migration); err != nil {
+				return false, err
+			}
+			if migration.Phase == vmcontrollerv1.IndexMigrationReindexing {
+				running++
+			}
+		}
+	}
+	if running > 0 {
+		if err := o.rethrottleReindexTasks(log, vmi, openSearchEndpoint); err != nil {
+			return false, err
+		}
 	}
 
-	// Reindex and delete old application indices
-	err = o.reindexAndDeleteIndices(log, vmi, openSearchEndpoint, appIndices, false)
-	if err != nil {
-		return fmt.Errorf("failed to migrate the Verrazzano application indices to data streams: %v", err)
+	// at most maxConcurrentIndices indices are reindexed at the same time
+	var failed []string
+	inProgress := false
+	for i := range migrations {
+		migration := &migrations[i]
+		switch migration.Phase {
+		case vmcontrollerv1.IndexMigrationPending:
+			inProgress = true
+			if running < getMaxConcurrentIndices(vmi) {
+				if err := o.startReindex(log, vmi, openSearchEndpoint, migration); err != nil {
+					return false, err
+				}
+				running++
+			}
+		case vmcontrollerv1.IndexMigrationReindexing:
+			inProgress = true
+		case vmcontrollerv1.IndexMigrationFailed:
+			failed = append(failed, migration.Index)
+		}
 	}
-	if len(systemIndices) > 0 || len(appIndices) > 0 {
-		log.Info("Migration of Verrazzano indices to data streams completed successfully")
-	} else {
-		log.Debug("Found no indices to migrate to data streams")
+	if inProgress {
+		return false, nil
 	}
-	return nil
+	if len(failed) > 0 {
+		// only the failed migrations are kept, so the status shows which source indices are left
+		log.Errorf("Failed to migrate the Verrazzano indices %v to data streams, the source indices were kept", failed)
+		var parked []vmcontrollerv1.IndexMigrationStatus
+		for _, migration := range migrations {
+			if migration.Phase == vmcontrollerv1.IndexMigrationFailed {
+				parked = append(parked, migration)
+			}
+		}
+		vmi.Status.Elasticsearch.IndexMigrations = parked
+		return true, nil
+	}
+	log.Info("Migration of Verrazzano indices to data streams completed successfully")
+	vmi.Status.Elasticsearch.IndexMigrations = nil
+	return true, nil
+}
+
+//isIndexMigrationParked returns true if the only migrations left are the ones which failed
+func isIndexMigrationParked(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) bool {
+	migrations := vmi.Status.Elasticsearch.IndexMigrations
+	for _, migration := range migrations {
+		if migration.Phase != vmcontrollerv1.IndexMigrationFailed {
+			return false
+		}
+	}
+	return len(migrations) > 0
 }
 
 func (o *OSClient) DataStreamExists(openSearchEndpoint, dataStream string) (bool, error) {
@@ -114,47 +213,6 @@ func (o *OSClient) DataStreamExists(openSearchEndpoint, dataStream string) (bool
 	return true, nil
 }
 
-func getSystemIndices(log vzlog.VerrazzanoLogger, indices []string) []string {
-	var systemIndices []string
-	for _, index := range indices {
-		if strings.HasPrefix(index, "verrazzano-namespace-") {
-			for _, systemNamespace := range config.SystemNamespaces() {
-				if index == "verrazzano-namespace-"+systemNamespace {
-					systemIndices = append(systemIndices, index)
-				}
-			}
-		}
-		if strings.Contains(index, "verrazzano-systemd-journal") {
-			systemIndices = append(systemIndices, index)
-		}
-		if strings.HasPrefix(index, "verrazzano-logstash-") {
-			systemIndices = append(systemIndices, index)
-		}
-	}
-	log.Debugf("Found Verrazzano system indices %v", systemIndices)
-	return systemIndices
-}
-
-func getApplicationIndices(log vzlog.VerrazzanoLogger, indices []string) []string {
-	var appIndices []string
-	for _, index := range indices {
-		systemIndex := false
-		if strings.HasPrefix(index, "verrazzano-namespace-") {
-			for _, systemNamespace := range config.SystemNamespaces() {
-				if index == "verrazzano-namespace-"+systemNamespace {
-					systemIndex = true
-					break
-				}
-			}
-			if !systemIndex {
-				appIndices = append(appIndices, index)
-			}
-		}
-	}
-	log.Debugf("Found Verrazzano application indices %v", appIndices)
-	return appIndices
-}
-
 func (o *OSClient) getIndices(log vzlog.VerrazzanoLogger, openSearchEndpoint string) ([]string, error) {
 	indicesURL := fmt.Sprintf("%s/_aliases", openSearchEndpoint)
 	log.Debugf("Executing get indices API %s", indicesURL)
@@ -185,62 +243,260 @@ func (o *OSClient) getIndices(log vzlog.VerrazzanoLogger, openSearchEndpoint str
 	return indexNames, nil
 }
 
-func (o *OSClient) reindexAndDeleteIndices(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance,
-	openSearchEndpoint string, indices []string, isSystemIndex bool) error {
-	for _, index := range indices {
-		var dataStreamName string
-		if isSystemIndex {
-			dataStreamName = config.DataStreamName()
-		} else {
-			dataStreamName = strings.Replace(index, "verrazzano-namespace", "verrazzano-application", 1)
-		}
-		noOfSecs, err := getRetentionAgeInSeconds(vmi, dataStreamName)
-		if err != nil {
-			return err
+//startReindex counts the documents to reindex, and starts a reindex task for the index
+func (o *OSClient) startReindex(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, openSearchEndpoint string, migration *vmcontrollerv1.IndexMigrationStatus) error {
+	reindexPayload := createReindexPayload(migration.Index, migration.DataStream, migration.Retention, vmi.Spec.Elasticsearch.Migration)
+	reindexPayload.RequestsPerSecond = o.getReindexRequestsPerSecond(log, vmi)
+	count, err := o.countDocuments(log, openSearchEndpoint, migration.Index, reindexPayload.Source.Query)
+	if err != nil {
+		return err
+	}
+	log.Infof("Reindexing %d documents from index %v to data stream %s", count, migration.Index, migration.DataStream)
+	taskID, err := o.reindexToDataStream(log, openSearchEndpoint, reindexPayload)
+	if err != nil {
+		return err
+	}
+	now := metav1.Now()
+	migration.Phase = vmcontrollerv1.IndexMigrationReindexing
+	migration.TaskID = taskID
+	migration.SourceDocs = count
+	migration.ReindexedDocs = 0
+	migration.Attempts++
+	migration.RequestsPerSecond = reindexPayload.RequestsPerSecond
+	migration.StartTime = &now
+	return nil
+}
+
+//rethrottleReindexTasks throttles the running reindex tasks to the requests per second the cluster health allows
+func (o *OSClient) rethrottleReindexTasks(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, openSearchEndpoint string) error {
+	requestsPerSecond := o.getReindexRequestsPerSecond(log, vmi)
+	for i := range vmi.Status.Elasticsearch.IndexMigrations {
+		migration := &vmi.Status.Elasticsearch.IndexMigrations[i]
+		if migration.Phase != vmcontrollerv1.IndexMigrationReindexing || isSameRequestsPerSecond(migration.RequestsPerSecond, requestsPerSecond) {
+			continue
 		}
-		log.Infof("Reindexing data from index %v to data stream %s", index, dataStreamName)
-		err = o.reindexToDataStream(log, openSearchEndpoint, index, dataStreamName, noOfSecs)
-		if err != nil {
+		if err := o.rethrottleReindex(log, openSearchEndpoint, migration.TaskID, requestsPerSecond); err != nil {
 			return err
 		}
-		log.Infof("Cleaning up index %v", index)
-		err = o.deleteIndex(log, openSearchEndpoint, index)
-		if err != nil {
-			return err
+		migration.RequestsPerSecond = requestsPerSecond
+	}
+	return nil
+}
+
+//getReindexRequestsPerSecond returns the degraded requests per second while the cluster is not green, if configured.
+// Returns nil if reindexing is unthrottled.
+func (o *OSClient) getReindexRequestsPerSecond(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) *int32 {
+	settings := vmi.Spec.Elasticsearch.Migration
+	if settings == nil {
+		return nil
+	}
+	if settings.DegradedRequestsPerSecond != nil {
+		health, err := o.getOpenSearchClusterHealth(vmi)
+		if err != nil || health.Status != HealthGreen {
+			log.Progressf("Throttling reindex to %d requests per second, since the cluster is not green", *settings.DegradedRequestsPerSecond)
+			return settings.DegradedRequestsPerSecond
 		}
-		log.Infof("Successfully cleaned up index %v", index)
 	}
+	return settings.RequestsPerSecond
+}
+
+func isSameRequestsPerSecond(a, b *int32) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func getMaxConcurrentIndices(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) int {
+	settings := vmi.Spec.Elasticsearch.Migration
+	if settings == nil || settings.MaxConcurrentIndices < 1 {
+		return 1
+	}
+	return int(settings.MaxConcurrentIndices)
+}
+
+//rethrottleReindex changes the requests per second of a running reindex task
+func (o *OSClient) rethrottleReindex(log vzlog.VerrazzanoLogger, openSearchEndpoint, taskID string, requestsPerSecond *int32) error {
+	rethrottleURL := fmt.Sprintf("%s/_reindex/%s/_rethrottle?requests_per_second=%s", openSearchEndpoint, taskID, formatRequestsPerSecond(requestsPerSecond))
+	log.Debugf("Executing rethrottle API %s", rethrottleURL)
+	req, err := http.NewRequest("POST", rethrottleURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// the task may have completed since it was checked
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		responseBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("got status code %d when rethrottling reindex task %s: %s", resp.StatusCode, taskID, string(responseBody))
+	}
+	log.Infof("Throttled reindex task %s to %s requests per second", taskID, formatRequestsPerSecond(requestsPerSecond))
 	return nil
 }
 
-func (o *OSClient) reindexToDataStream(log vzlog.VerrazzanoLogger, openSearchEndpoint, sourceName, destName, retentionSeconds string) error {
-	reindexPayload := createReindexPayload(sourceName, destName, retentionSeconds)
-	payload, err := json.Marshal(reindexPayload)
+//formatRequestsPerSecond returns the requests per second parameter, where -1 is unthrottled
+func formatRequestsPerSecond(requestsPerSecond *int32) string {
+	if requestsPerSecond == nil {
+		return "-1"
+	}
+	return strconv.Itoa(int(*requestsPerSecond))
+}
+
+//checkReindex updates the progress of the reindex task. Once the task has completed and all documents were reindexed,
+// the source index is deleted. Otherwise, the index is reindexed again, up to maxReindexAttempts times.
+func (o *OSClient) checkReindex(log vzlog.VerrazzanoLogger, openSearchEndpoint string, migration *vmcontrollerv1.IndexMigrationStatus) error {
+	task, err := o.getTask(log, openSearchEndpoint, migration.TaskID)
 	if err != nil {
 		return err
 	}
-	reindexURL := fmt.Sprintf("%s/_reindex", openSearchEndpoint)
+	if task == nil {
+		retryReindex(log, migration, fmt.Sprintf("reindex task %s no longer exists", migration.TaskID))
+		return nil
+	}
+	if !task.Completed {
+		migration.ReindexedDocs = task.Task.Status.Created + task.Task.Status.VersionConflicts
+		log.Progressf("Reindexing from %s to %s, %d of %d documents reindexed", migration.Index, migration.DataStream, migration.ReindexedDocs, migration.SourceDocs)
+		return nil
+	}
+	if task.Error != nil {
+		retryReindex(log, migration, fmt.Sprintf("reindex task %s failed: %s", migration.TaskID, task.Error.Reason))
+		return nil
+	}
+	if len(task.Response.Failures) > 0 {
+		failures, _ := json.Marshal(task.Response.Failures[0])
+		retryReindex(log, migration, fmt.Sprintf("reindex task %s had %d failures, the first being %s", migration.TaskID, len(task.Response.Failures), string(failures)))
+		return nil
+	}
+	// documents which already exist in the data stream, from an earlier attempt, are version conflicts
+	migration.ReindexedDocs = task.Response.Created + task.Response.VersionConflicts
+	if migration.ReindexedDocs != migration.SourceDocs {
+		retryReindex(log, migration, fmt.Sprintf("reindexed %d of %d documents", migration.ReindexedDocs, migration.SourceDocs))
+		return nil
+	}
+
+	log.Infof("Reindex from %s to %s completed successfully, cleaning up index %v", migration.Index, migration.DataStream, migration.Index)
+	if err := o.deleteIndex(log, openSearchEndpoint, migration.Index); err != nil {
+		return err
+	}
+	log.Infof("Successfully cleaned up index %v", migration.Index)
+	migration.Phase = vmcontrollerv1.IndexMigrationCompleted
+	migration.TaskID = ""
+	migration.Message = ""
+	return nil
+}
+
+func retryReindex(log vzlog.VerrazzanoLogger, migration *vmcontrollerv1.IndexMigrationStatus, reason string) {
+	migration.TaskID = ""
+	if migration.Attempts >= maxReindexAttempts {
+		log.Errorf("Failed to reindex from %s to %s after %d attempts: %s", migration.Index, migration.DataStream, migration.Attempts, reason)
+		migration.Phase = vmcontrollerv1.IndexMigrationFailed
+		migration.Message = fmt.Sprintf("%s, the source index was kept", reason)
+		return
+	}
+	log.Infof("Retrying reindex from %s to %s: %s", migration.Index, migration.DataStream, reason)
+	migration.Phase = vmcontrollerv1.IndexMigrationPending
+	migration.Message = reason
+}
+
+//reindexToDataStream starts a reindex task, and returns its task ID
+func (o *OSClient) reindexToDataStream(log vzlog.VerrazzanoLogger, openSearchEndpoint string, reindexPayload *ReindexPayload) (string, error) {
+	payload, err := json.Marshal(reindexPayload)
+	if err != nil {
+		return "", err
+	}
+	reindexURL := fmt.Sprintf("%s/_reindex?wait_for_completion=false&requests_per_second=%s", openSearchEndpoint, formatRequestsPerSecond(reindexPayload.RequestsPerSecond))
+	if reindexPayload.Slices != "" {
+		reindexURL = fmt.Sprintf("%s&slices=%s", reindexURL, reindexPayload.Slices)
+	}
 	log.Debugf("Executing Reindex API %s", reindexURL)
 
 	req, err := http.NewRequest("POST", reindexURL, bytes.NewReader(payload))
 	if err != nil {
-		return err
+		return "", err
 	}
 	req.Header.Add("Content-Type", "application/json")
 	resp, err := o.DoHTTP(req)
 	if err != nil {
-		log.Errorf("Reindex from %s to %s failed", sourceName, destName)
-		return err
+		log.Errorf("Reindex from %s to %s failed", reindexPayload.Source.Index, reindexPayload.Dest.Index)
+		return "", err
 	}
 	defer resp.Body.Close()
 	responseBody, _ := ioutil.ReadAll(resp.Body)
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("got status code %d when reindexing from %s to %s failed: %s", resp.StatusCode, sourceName,
-			destName, string(responseBody))
+		return "", fmt.Errorf("got status code %d when reindexing from %s to %s failed: %s", resp.StatusCode, reindexPayload.Source.Index,
+			reindexPayload.Dest.Index, string(responseBody))
+	}
+	reindexTask := &ReindexTaskResponse{}
+	if err := json.Unmarshal(responseBody, reindexTask); err != nil {
+		return "", err
 	}
+	if reindexTask.Task == "" {
+		return "", fmt.Errorf("no task was started when reindexing from %s to %s: %s", reindexPayload.Source.Index, reindexPayload.Dest.Index, string(responseBody))
+	}
+	return reindexTask.Task, nil
+}
 
-	log.Infof("Reindex from %s to %s completed successfully: %s", sourceName, destName, string(responseBody))
-	return nil
+//getTask returns the task, or nil if the task does not exist
+func (o *OSClient) getTask(log vzlog.VerrazzanoLogger, openSearchEndpoint, taskID string) (*Task, error) {
+	taskURL := fmt.Sprintf("%s/_tasks/%s", openSearchEndpoint, taskID)
+	log.Debugf("Executing get task API %s", taskURL)
+	req, err := http.NewRequest("GET", taskURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got status code %d when getting task %s", resp.StatusCode, taskID)
+	}
+	task := &Task{}
+	if err := json.NewDecoder(resp.Body).Decode(task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+//countDocuments returns the number of documents in the index which match the query
+func (o *OSClient) countDocuments(log vzlog.VerrazzanoLogger, openSearchEndpoint, index string, query *Query) (int64, error) {
+	countURL := fmt.Sprintf("%s/%s/_count", openSearchEndpoint, index)
+	log.Debugf("Executing count API %s", countURL)
+	var body []byte
+	if query != nil {
+		var err error
+		if body, err = json.Marshal(map[string]interface{}{"query": query}); err != nil {
+			return 0, err
+		}
+	}
+	req, err := http.NewRequest("POST", countURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("got status code %d when counting the documents of index %s", resp.StatusCode, index)
+	}
+	count := &CountResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(count); err != nil {
+		return 0, err
+	}
+	return count.Count, nil
 }
 
 func (o *OSClient) deleteIndex(log vzlog.VerrazzanoLogger, openSearchEndpoint string, indexName string) error {
@@ -258,13 +514,17 @@ func (o *OSClient) deleteIndex(log vzlog.VerrazzanoLogger, openSearchEndpoint st
 	defer resp.Body.Close()
 	responseBody, _ := ioutil.ReadAll(resp.Body)
 	log.Debugf("Delete API response %s", string(responseBody))
+	// the index may have been deleted before the migration progress was saved
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("got status code %d when deleting the indice %s in OpenSearch: %s", resp.StatusCode, indexName, string(responseBody))
 	}
 	return nil
 }
 
-func createReindexPayload(source, dest, retentionSeconds string) *ReindexPayload {
+func createReindexPayload(source, dest, retentionSeconds string, settings *vmcontrollerv1.IndexMigrationSettings) *ReindexPayload {
 	reindexPayload := &ReindexPayload{
 		Conflicts: "proceed",
 		Source: Source{
@@ -275,6 +535,11 @@ func createReindexPayload(source, dest, retentionSeconds string) *ReindexPayload
 			OpType: "create",
 		},
 	}
+	if settings != nil {
+		reindexPayload.Source.Size = settings.BatchSize
+		reindexPayload.RequestsPerSecond = settings.RequestsPerSecond
+		reindexPayload.Slices = settings.Slices
+	}
 	if retentionSeconds != "" {
 		reindexPayload.Source.Query = &Query{
 			Range: Range{
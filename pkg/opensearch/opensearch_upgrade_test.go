@@ -4,7 +4,8 @@
 package opensearch
 
 import (
-	"errors"
+	"encoding/json"
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
@@ -63,8 +64,8 @@ const openSearchEP = "http://localhost:9200/"
 
 // TestGetIndices tests that indices can be fetched from the OpenSearch server
 // GIVEN an OpenSearch server with indices
-// WHEN I call getIndices, getSystemIndices, and getApplicationIndices
-// THEN I get back the expected indices, system indices, and application indices
+// WHEN I call getIndices and getIndexMigrations with the default rules
+// THEN I get back the expected indices, and the system and application indices are migrated to their data streams
 func TestGetIndices(t *testing.T) {
 	o := NewOSClient()
 	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
@@ -80,15 +81,19 @@ func TestGetIndices(t *testing.T) {
 	assert.Contains(t, indices, "verrazzano-namespace-istio-system")
 	assert.Equal(t, 13, len(indices))
 
-	systemIndices := getSystemIndices(log, indices)
-	assert.Contains(t, systemIndices, "verrazzano-namespace-keycloak")
-	assert.Contains(t, systemIndices, "verrazzano-namespace-istio-system")
-	assert.Equal(t, 10, len(systemIndices))
-
-	applicationIndices := getApplicationIndices(log, indices)
-	assert.Contains(t, applicationIndices, "verrazzano-namespace-testapp")
-	assert.Contains(t, applicationIndices, "verrazzano-namespace-dummyapp")
-	assert.Equal(t, 2, len(applicationIndices))
+	migrations, err := getIndexMigrations(createISMVMI("1d", true), indices)
+	assert.NoError(t, err)
+	dataStreams := map[string]string{}
+	for _, migration := range migrations {
+		dataStreams[migration.Index] = migration.DataStream
+	}
+	assert.Equal(t, 12, len(migrations))
+	assert.Equal(t, config.DataStreamName(), dataStreams["verrazzano-namespace-keycloak"])
+	assert.Equal(t, config.DataStreamName(), dataStreams["verrazzano-namespace-istio-system"])
+	assert.Equal(t, config.DataStreamName(), dataStreams["verrazzano-systemd-journal"])
+	assert.Equal(t, "verrazzano-application-testapp", dataStreams["verrazzano-namespace-testapp"])
+	assert.Equal(t, "verrazzano-application-dummyapp", dataStreams["verrazzano-namespace-dummyapp"])
+	assert.NotContains(t, dataStreams, ".kibana_1")
 }
 
 // TestDataStreamExists Tests the expected data streams can be retrieved on an OpenSearch cluster
@@ -144,118 +149,330 @@ func TestCalculateSeconds(t *testing.T) {
 	a.Equal(uint64(20), seconds)
 }
 
-// TestReindexAndDeleteIndices Tests that indices are reindexed and deleted as expected
-// GIVEN a cluster with indices to reindex
-// WHEN I call reindexAndDeleteIndices
-// THEN those indices are reindexed and deleted
-func TestReindexAndDeleteIndices(t *testing.T) {
-	systemIndices := []string{"verrazzano-namespace-verrazzano-system"}
-	dummyOK := func(req *http.Request) (*http.Response, error) {
-		return &http.Response{
-			StatusCode: http.StatusOK,
-			Body:       io.NopCloser(strings.NewReader("")),
-		}, nil
+// TestMigrateIndicesToDataStreams Tests that indices are reindexed by tasks and deleted as expected
+// GIVEN a cluster with indices to reindex, and the persisted migration progress
+// WHEN I call MigrateIndicesToDataStreams
+// THEN the migration progresses, and source indices are only deleted once all their documents were reindexed
+func TestMigrateIndicesToDataStreams(t *testing.T) {
+	const (
+		systemIndex     = "verrazzano-namespace-verrazzano-system"
+		appIndex        = "verrazzano-namespace-bobs-books"
+		appDataStream   = "verrazzano-application-bobs-books"
+		taskRunning     = `{"completed": false, "task": {"status": {"total": 10, "created": 4, "version_conflicts": 1}}}`
+		taskCompleted   = `{"completed": true, "response": {"total": 10, "created": 8, "version_conflicts": 2, "failures": []}}`
+		taskIncomplete  = `{"completed": true, "response": {"total": 10, "created": 7, "version_conflicts": 0, "failures": []}}`
+		taskFailures    = `{"completed": true, "response": {"total": 10, "created": 9, "failures": [{"cause": {"type": "mapper_parsing_exception"}}]}}`
+		taskError       = `{"completed": true, "error": {"type": "search_phase_execution_exception", "reason": "all shards failed"}}`
+		indicesResponse = `{"verrazzano-namespace-verrazzano-system": {"aliases": {}}, "verrazzano-namespace-bobs-books": {"aliases": {}}}`
+	)
+	reindexing := func(index, dataStream string, attempts int) vmcontrollerv1.IndexMigrationStatus {
+		return vmcontrollerv1.IndexMigrationStatus{
+			Index:      index,
+			DataStream: dataStream,
+			Phase:      vmcontrollerv1.IndexMigrationReindexing,
+			TaskID:     "node:1",
+			SourceDocs: 10,
+			Attempts:   attempts,
+		}
+	}
+	pending := func(index, dataStream string) vmcontrollerv1.IndexMigrationStatus {
+		return vmcontrollerv1.IndexMigrationStatus{Index: index, DataStream: dataStream, Phase: vmcontrollerv1.IndexMigrationPending}
+	}
+	failed := func(index, dataStream string) vmcontrollerv1.IndexMigrationStatus {
+		return vmcontrollerv1.IndexMigrationStatus{Index: index, DataStream: dataStream, Phase: vmcontrollerv1.IndexMigrationFailed, Attempts: maxReindexAttempts}
 	}
+
 	var tests = []struct {
-		name          string
-		indices       []string
-		httpFunc      func(req *http.Request) (*http.Response, error)
-		isSystemIndex bool
-		isError       bool
+		name       string
+		migrations []vmcontrollerv1.IndexMigrationStatus
+		indices    string
+		task       string
+		complete   bool
+		isError    bool
+		deleted    []string
+		phases     []vmcontrollerv1.IndexMigrationPhase
 	}{
 		{
-			"should reindex system indices",
-			systemIndices,
-			dummyOK,
+			"no indices to migrate",
+			nil,
+			`{".kibana_1": {"aliases": {}}}`,
+			"",
 			true,
 			false,
+			nil,
+			nil,
 		},
 		{
-			"should reindex application indices",
-			[]string{"verrazzano-namespace-bobs-books", "verrazzano-namespace-todo-app"},
-			dummyOK,
+			"starts reindexing the first index",
+			nil,
+			indicesResponse,
+			"",
 			false,
 			false,
+			nil,
+			[]vmcontrollerv1.IndexMigrationPhase{vmcontrollerv1.IndexMigrationReindexing, vmcontrollerv1.IndexMigrationPending},
 		},
 		{
-			"should fail when reindex fails",
-			systemIndices,
-			func(req *http.Request) (*http.Response, error) {
-				return nil, errors.New("BOOM")
-			},
-			true,
-			true,
+			"waits for the reindex task",
+			[]vmcontrollerv1.IndexMigrationStatus{reindexing(systemIndex, config.DataStreamName(), 1), pending(appIndex, appDataStream)},
+			"",
+			taskRunning,
+			false,
+			false,
+			nil,
+			[]vmcontrollerv1.IndexMigrationPhase{vmcontrollerv1.IndexMigrationReindexing, vmcontrollerv1.IndexMigrationPending},
 		},
 		{
-			"should fail when delete not found",
-			systemIndices,
-			func(req *http.Request) (*http.Response, error) {
-				if req.Method == "POST" {
-					return dummyOK(req)
-				}
-				return &http.Response{
-					StatusCode: http.StatusNotFound,
-					Body:       io.NopCloser(strings.NewReader("")),
-				}, nil
-			},
-			true,
-			true,
+			"deletes the source index and starts the next reindex",
+			[]vmcontrollerv1.IndexMigrationStatus{reindexing(systemIndex, config.DataStreamName(), 1), pending(appIndex, appDataStream)},
+			"",
+			taskCompleted,
+			false,
+			false,
+			[]string{systemIndex},
+			[]vmcontrollerv1.IndexMigrationPhase{vmcontrollerv1.IndexMigrationCompleted, vmcontrollerv1.IndexMigrationReindexing},
 		},
 		{
-			"should fail when delete fails",
-			systemIndices,
-			func(req *http.Request) (*http.Response, error) {
-				if req.Method == "POST" {
-					return dummyOK(req)
-				}
-				return nil, errors.New("BOOM")
-			},
+			"retries when documents are missing",
+			[]vmcontrollerv1.IndexMigrationStatus{reindexing(systemIndex, config.DataStreamName(), 1)},
+			"",
+			taskIncomplete,
+			false,
+			false,
+			nil,
+			[]vmcontrollerv1.IndexMigrationPhase{vmcontrollerv1.IndexMigrationReindexing},
+		},
+		{
+			"retries when the task had failures",
+			[]vmcontrollerv1.IndexMigrationStatus{reindexing(systemIndex, config.DataStreamName(), 1)},
+			"",
+			taskFailures,
+			false,
+			false,
+			nil,
+			[]vmcontrollerv1.IndexMigrationPhase{vmcontrollerv1.IndexMigrationReindexing},
+		},
+		{
+			"retries when the task no longer exists",
+			[]vmcontrollerv1.IndexMigrationStatus{reindexing(systemIndex, config.DataStreamName(), 1)},
+			"",
+			"",
+			false,
+			false,
+			nil,
+			[]vmcontrollerv1.IndexMigrationPhase{vmcontrollerv1.IndexMigrationReindexing},
+		},
+		{
+			"fails after the last attempt, and keeps the source index",
+			[]vmcontrollerv1.IndexMigrationStatus{reindexing(systemIndex, config.DataStreamName(), maxReindexAttempts)},
+			"",
+			taskError,
 			true,
+			false,
+			nil,
+			[]vmcontrollerv1.IndexMigrationPhase{vmcontrollerv1.IndexMigrationFailed},
+		},
+		{
+			"keeps only the failed migrations once the others complete",
+			[]vmcontrollerv1.IndexMigrationStatus{failed(systemIndex, config.DataStreamName()), reindexing(appIndex, appDataStream, 1)},
+			"",
+			taskCompleted,
 			true,
+			false,
+			[]string{appIndex},
+			[]vmcontrollerv1.IndexMigrationPhase{vmcontrollerv1.IndexMigrationFailed},
 		},
 		{
-			"should fail when reindex 5xx",
-			systemIndices,
-			func(req *http.Request) (*http.Response, error) {
-				return &http.Response{
-					StatusCode: http.StatusInternalServerError,
-					Body:       io.NopCloser(strings.NewReader("")),
-				}, nil
-			},
+			"does nothing once only failed migrations are left",
+			[]vmcontrollerv1.IndexMigrationStatus{failed(systemIndex, config.DataStreamName())},
+			indicesResponse,
+			"",
 			true,
+			false,
+			nil,
+			[]vmcontrollerv1.IndexMigrationPhase{vmcontrollerv1.IndexMigrationFailed},
+		},
+		{
+			"completes once all indices are migrated",
+			[]vmcontrollerv1.IndexMigrationStatus{reindexing(appIndex, appDataStream, 2)},
+			"",
+			taskCompleted,
 			true,
+			false,
+			[]string{appIndex},
+			nil,
 		},
 	}
-	vmi := createISMVMI("1d", true)
-	vmi.Spec.Elasticsearch.Policies = []vmcontrollerv1.IndexManagementPolicy{
-		*createTestPolicy("1d", "1d", "verrazzano-*", "1d", 1),
-	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			var deleted []string
 			o := NewOSClient()
-			o.DoHTTP = tt.httpFunc
-			err := o.reindexAndDeleteIndices(vzlog.DefaultLogger(), vmi, openSearchEP, tt.indices, tt.isSystemIndex)
+			o.DoHTTP = func(req *http.Request) (*http.Response, error) {
+				statusCode := http.StatusOK
+				body := ""
+				switch {
+				case req.URL.Path == "/_aliases":
+					body = tt.indices
+				case strings.HasSuffix(req.URL.Path, "/_count"):
+					body = `{"count": 10}`
+				case req.URL.Path == "/_reindex":
+					assert.Equal(t, "false", req.URL.Query().Get("wait_for_completion"))
+					body = `{"task": "node:2"}`
+				case strings.HasPrefix(req.URL.Path, "/_tasks/"):
+					body = tt.task
+					if tt.task == "" {
+						statusCode = http.StatusNotFound
+					}
+				case req.Method == "DELETE":
+					deleted = append(deleted, strings.TrimPrefix(req.URL.Path, "/"))
+				}
+				return &http.Response{
+					StatusCode: statusCode,
+					Body:       io.NopCloser(strings.NewReader(body)),
+				}, nil
+			}
+			vmi := createISMVMI("1d", true)
+			vmi.Status.Elasticsearch.IndexMigrations = tt.migrations
+			complete, err := o.MigrateIndicesToDataStreams(vzlog.DefaultLogger(), vmi, "http://localhost:9200")
 			if tt.isError {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
 			}
+			assert.Equal(t, tt.complete, complete)
+			assert.Equal(t, tt.deleted, deleted)
+			var phases []vmcontrollerv1.IndexMigrationPhase
+			for _, migration := range vmi.Status.Elasticsearch.IndexMigrations {
+				phases = append(phases, migration.Phase)
+				if migration.Phase == vmcontrollerv1.IndexMigrationReindexing {
+					assert.NotEmpty(t, migration.TaskID)
+					assert.Equal(t, int64(10), migration.SourceDocs)
+				}
+			}
+			assert.Equal(t, tt.phases, phases)
 		})
 	}
 }
 
-// TestReindexToDataStream Tests reindexing an index to a data stream
-// GIVENa cluster with an index to reindex
+// TestCheckReindex Tests the migration progress recorded for a reindex task
+// GIVEN a reindex task
+// WHEN I call checkReindex
+// THEN the reindexed documents are recorded, and failed attempts are retried
+func TestCheckReindex(t *testing.T) {
+	o := NewOSClient()
+	task := `{"completed": false, "task": {"status": {"total": 10, "created": 4, "version_conflicts": 1}}}`
+	o.DoHTTP = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(task)),
+		}, nil
+	}
+	migration := &vmcontrollerv1.IndexMigrationStatus{Index: "a", Phase: vmcontrollerv1.IndexMigrationReindexing, TaskID: "node:1", SourceDocs: 10, Attempts: 1}
+	assert.NoError(t, o.checkReindex(vzlog.DefaultLogger(), openSearchEP, migration))
+	assert.Equal(t, int64(5), migration.ReindexedDocs)
+	assert.Equal(t, vmcontrollerv1.IndexMigrationReindexing, migration.Phase)
+
+	task = `{"completed": true, "response": {"total": 10, "created": 7}}`
+	assert.NoError(t, o.checkReindex(vzlog.DefaultLogger(), openSearchEP, migration))
+	assert.Equal(t, vmcontrollerv1.IndexMigrationPending, migration.Phase)
+	assert.Equal(t, "reindexed 7 of 10 documents", migration.Message)
+	assert.Empty(t, migration.TaskID)
+}
+
+// TestReindexToDataStream Tests starting a reindex task of an index to a data stream
+// GIVEN a cluster with an index to reindex
 // WHEN I call reindexToDataStream
-// THEN the index is reindex to a data stream
+// THEN a reindex task is started, and its ID is returned
 func TestReindexToDataStream(t *testing.T) {
 	o := NewOSClient()
 	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		assert.Equal(t, "wait_for_completion=false&requests_per_second=-1", request.URL.RawQuery)
 		return &http.Response{
 			StatusCode: http.StatusOK,
-			Body:       io.NopCloser(strings.NewReader("")),
+			Body:       io.NopCloser(strings.NewReader(`{"task": "node:1"}`)),
 		}, nil
 	}
-	err := o.reindexToDataStream(vzlog.DefaultLogger(), openSearchEP, "src", "dest", "1s")
+	taskID, err := o.reindexToDataStream(vzlog.DefaultLogger(), "http://localhost:9200", createReindexPayload("src", "dest", "1s", nil))
 	assert.NoError(t, err)
+	assert.Equal(t, "node:1", taskID)
+}
+
+// TestThrottledReindex Tests that the migration settings throttle the reindex
+// GIVEN migration settings for concurrency, slices, batch size and requests per second
+// WHEN I call MigrateIndicesToDataStreams
+// THEN reindex tasks are started with the settings, and running tasks are rethrottled when the cluster is not green
+func TestThrottledReindex(t *testing.T) {
+	rps := int32(500)
+	degradedRPS := int32(50)
+	batchSize := int32(200)
+	var tests = []struct {
+		name        string
+		health      string
+		started     int
+		rethrottled string
+	}{
+		{"starts tasks up to the maximum concurrent indices", `{"status": "green"}`, 2, ""},
+		{"throttles tasks while the cluster is not green", `{"status": "yellow"}`, 2, "50"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var started []string
+			rethrottled := ""
+			o := NewOSClient()
+			o.DoHTTP = func(req *http.Request) (*http.Response, error) {
+				body := ""
+				switch {
+				case req.URL.Path == "/_cluster/health":
+					body = tt.health
+				case strings.HasSuffix(req.URL.Path, "/_count"):
+					body = `{"count": 10}`
+				case req.URL.Path == "/_reindex":
+					payload := map[string]interface{}{}
+					assert.NoError(t, json.NewDecoder(req.Body).Decode(&payload))
+					assert.Equal(t, float64(batchSize), payload["source"].(map[string]interface{})["size"])
+					assert.Equal(t, "auto", req.URL.Query().Get("slices"))
+					started = append(started, req.URL.Query().Get("requests_per_second"))
+					body = fmt.Sprintf(`{"task": "node:%d"}`, len(started)+1)
+				case strings.HasPrefix(req.URL.Path, "/_tasks/"):
+					body = `{"completed": false, "task": {"status": {"total": 10, "created": 1}}}`
+				case strings.HasSuffix(req.URL.Path, "/_rethrottle"):
+					assert.Equal(t, "/_reindex/node:1/_rethrottle", req.URL.Path)
+					rethrottled = req.URL.Query().Get("requests_per_second")
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(body)),
+				}, nil
+			}
+			vmi := createISMVMI("1d", true)
+			vmi.Spec.Elasticsearch.Migration = &vmcontrollerv1.IndexMigrationSettings{
+				RequestsPerSecond:         &rps,
+				DegradedRequestsPerSecond: &degradedRPS,
+				Slices:                    "auto",
+				MaxConcurrentIndices:      3,
+				BatchSize:                 &batchSize,
+			}
+			vmi.Status.Elasticsearch.IndexMigrations = []vmcontrollerv1.IndexMigrationStatus{
+				{Index: "a", DataStream: "da", Phase: vmcontrollerv1.IndexMigrationReindexing, TaskID: "node:1", SourceDocs: 10, Attempts: 1, RequestsPerSecond: &rps},
+				{Index: "b", DataStream: "db", Phase: vmcontrollerv1.IndexMigrationPending},
+				{Index: "c", DataStream: "dc", Phase: vmcontrollerv1.IndexMigrationPending},
+				{Index: "d", DataStream: "dd", Phase: vmcontrollerv1.IndexMigrationPending},
+			}
+			complete, err := o.MigrateIndicesToDataStreams(vzlog.DefaultLogger(), vmi, "http://localhost:9200")
+			assert.NoError(t, err)
+			assert.False(t, complete)
+			assert.Len(t, started, tt.started)
+			assert.Equal(t, tt.rethrottled, rethrottled)
+			expectedRPS := rps
+			if tt.rethrottled != "" {
+				expectedRPS = degradedRPS
+			}
+			for _, requestsPerSecond := range started {
+				assert.Equal(t, fmt.Sprint(expectedRPS), requestsPerSecond)
+			}
+			migrations := vmi.Status.Elasticsearch.IndexMigrations
+			assert.Equal(t, expectedRPS, *migrations[0].RequestsPerSecond)
+			assert.Equal(t, vmcontrollerv1.IndexMigrationPending, migrations[3].Phase)
+		})
+	}
 }
@@ -0,0 +1,779 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+// Package opensearchtest provides a fake OpenSearch server, which keeps the cluster state in memory so that the operator
+// can be tested against the OpenSearch API without a cluster.
+package opensearchtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// the fake cluster name
+	clusterName = "fake-opensearch"
+	// the ISM setting which holds the policy of a managed index
+	policyIDSetting = "index.plugins.index_state_management.policy_id"
+	// backing indices of data streams are named .ds-<data stream>-<generation>
+	backingIndexFormat = ".ds-%s-%06d"
+)
+
+type (
+	//FakeOpenSearch is an in-process OpenSearch server with in-memory state. It implements the subset of the
+	// OpenSearch API used by the operator:
+	// - cluster health and nodes
+	// - ISM policies, adding policies to indices and explaining managed indices
+	// - data streams, aliases, document counts and index deletion
+	// - reindex, where each reindex task completes immediately
+	// APIs which are not implemented return 501 Not Implemented.
+	FakeOpenSearch struct {
+		server *httptest.Server
+
+		lock        sync.Mutex
+		health      string
+		nodes       map[string]FakeNode
+		indices     map[string]*fakeIndex
+		dataStreams map[string]*fakeDataStream
+		// index patterns of the data stream index templates
+		templates []string
+		policies  map[string]*fakePolicy
+		tasks     map[string]*fakeTask
+		seqNo     int
+		taskCount int
+		requests  []string
+	}
+
+	//FakeNode is a node of the fake cluster
+	FakeNode struct {
+		Name    string   `json:"name"`
+		Version string   `json:"version"`
+		Roles   []string `json:"roles"`
+	}
+
+	fakeIndex struct {
+		// document IDs, so that reindexing a document twice is a version conflict
+		docs       map[string]bool
+		dataStream string
+		policyID   string
+	}
+
+	fakeDataStream struct {
+		indices []string
+	}
+
+	fakePolicy struct {
+		seqNo       int
+		primaryTerm int
+		version     int
+		policy      json.RawMessage
+	}
+
+	fakeTask struct {
+		Completed bool `json:"completed"`
+		Task      struct {
+			Status reindexStatus `json:"status"`
+		} `json:"task"`
+		Response reindexStatus `json:"response"`
+	}
+
+	reindexStatus struct {
+		Total            int64         `json:"total"`
+		Created          int64         `json:"created"`
+		VersionConflicts int64         `json:"version_conflicts"`
+		Failures         []interface{} `json:"failures"`
+	}
+
+	reindexRequest struct {
+		Source struct {
+			Index string `json:"index"`
+		} `json:"source"`
+		Dest struct {
+			Index  string `json:"index"`
+			OpType string `json:"op_type"`
+		} `json:"dest"`
+	}
+
+	addPolicyRequest struct {
+		PolicyID string `json:"policy_id"`
+	}
+
+	failedIndex struct {
+		IndexName string `json:"index_name"`
+		Reason    string `json:"reason"`
+	}
+
+	jsonObject map[string]interface{}
+)
+
+//NewFakeOpenSearch starts a fake OpenSearch server with green health and no nodes, indices or policies.
+// The server must be closed once the test is done.
+func NewFakeOpenSearch() *FakeOpenSearch {
+	f := &FakeOpenSearch{
+		health:      "green",
+		nodes:       map[string]FakeNode{},
+		indices:     map[string]*fakeIndex{},
+		dataStreams: map[string]*fakeDataStream{},
+		policies:    map[string]*fakePolicy{},
+		tasks:       map[string]*fakeTask{},
+	}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	return f
+}
+
+//Close shuts down the server
+func (f *FakeOpenSearch) Close() {
+	f.server.Close()
+}
+
+//URL returns the base URL of the server
+func (f *FakeOpenSearch) URL() string {
+	return f.server.URL
+}
+
+//DoHTTP sends the request to the fake server, whatever the host of the request URL is. It is meant to replace the
+// DoHTTP function of an OpenSearch client, so that requests to the OpenSearch endpoint of any VMI reach the fake.
+func (f *FakeOpenSearch) DoHTTP(request *http.Request) (*http.Response, error) {
+	fakeRequest := request.Clone(request.Context())
+	fakeRequest.URL.Scheme = "http"
+	fakeRequest.URL.Host = f.server.Listener.Addr().String()
+	fakeRequest.Host = ""
+	return f.server.Client().Do(fakeRequest)
+}
+
+//SetHealth sets the health status of the cluster, one of green, yellow or red
+func (f *FakeOpenSearch) SetHealth(status string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.health = status
+}
+
+//AddNode adds a node to the cluster, or replaces the node of the same name
+func (f *FakeOpenSearch) AddNode(name, version string, roles ...string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.nodes[name] = FakeNode{Name: name, Version: version, Roles: roles}
+}
+
+//RemoveNode removes a node from the cluster
+func (f *FakeOpenSearch) RemoveNode(name string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	delete(f.nodes, name)
+}
+
+//AddIndex adds an index with the given number of documents
+func (f *FakeOpenSearch) AddIndex(name string, docs int) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	index := f.createIndex(name, "")
+	for i := 0; i < docs; i++ {
+		index.docs[fmt.Sprintf("%s-%d", name, i)] = true
+	}
+}
+
+//AddDataStreamTemplate adds an index template with a data stream for the index pattern. A data stream is created
+// when documents are written to a missing index matching the pattern.
+func (f *FakeOpenSearch) AddDataStreamTemplate(pattern string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.templates = append(f.templates, pattern)
+}
+
+//AddDataStream adds a data stream with a single, empty backing index
+func (f *FakeOpenSearch) AddDataStream(name string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.createDataStream(name)
+}
+
+//Indices returns the number of documents of each index, including the backing indices of data streams
+func (f *FakeOpenSearch) Indices() map[string]int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	indices := map[string]int{}
+	for name, index := range f.indices {
+		indices[name] = len(index.docs)
+	}
+	return indices
+}
+
+//DataStreams returns the number of documents of each data stream
+func (f *FakeOpenSearch) DataStreams() map[string]int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	dataStreams := map[string]int{}
+	for name := range f.dataStreams {
+		dataStreams[name] = f.countDocs(name)
+	}
+	return dataStreams
+}
+
+//Policies returns the names of the ISM policies, in name order
+func (f *FakeOpenSearch) Policies() []string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.policyNames()
+}
+
+//ManagedIndices returns the ISM policy of each managed index
+func (f *FakeOpenSearch) ManagedIndices() map[string]string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	managed := map[string]string{}
+	for name, index := range f.indices {
+		if index.policyID != "" {
+			managed[name] = index.policyID
+		}
+	}
+	return managed
+}
+
+//Requests returns each request received by the server as method and URL path, e.g., GET /_cluster/health
+func (f *FakeOpenSearch) Requests() []string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return append([]string{}, f.requests...)
+}
+
+func (f *FakeOpenSearch) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case segments[0] == "_cluster" && len(segments) == 2 && segments[1] == "health":
+		f.getClusterHealth(w, r)
+	case segments[0] == "_nodes" && len(segments) <= 2:
+		f.getNodes(w, r)
+	case segments[0] == "_plugins" && len(segments) >= 3 && segments[1] == "_ism":
+		f.serveISM(w, r, segments[2], strings.Join(segments[3:], "/"))
+	case segments[0] == "_data_stream" && len(segments) <= 2:
+		f.serveDataStream(w, r, strings.Join(segments[1:], "/"))
+	case segments[0] == "_aliases" && len(segments) == 1 && r.Method == "GET":
+		f.getAliases(w)
+	case segments[0] == "_reindex" && len(segments) == 1 && r.Method == "POST":
+		f.reindex(w, r)
+	case segments[0] == "_reindex" && len(segments) == 3 && segments[2] == "_rethrottle" && r.Method == "POST":
+		f.rethrottle(w, segments[1])
+	case segments[0] == "_tasks" && len(segments) == 2 && r.Method == "GET":
+		f.getTask(w, segments[1])
+	case len(segments) == 2 && segments[1] == "_count" && (r.Method == "GET" || r.Method == "POST"):
+		f.count(w, segments[0])
+	case len(segments) == 1 && segments[0] != "" && !strings.HasPrefix(segments[0], "_") && r.Method == "DELETE":
+		f.deleteIndex(w, segments[0])
+	default:
+		writeError(w, http.StatusNotImplemented, "not_implemented", fmt.Sprintf("%s %s is not implemented by the fake OpenSearch server", r.Method, r.URL.Path))
+	}
+}
+
+func (f *FakeOpenSearch) getClusterHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeMethodNotAllowed(w, r)
+		return
+	}
+	dataNodes := 0
+	for _, node := range f.nodes {
+		for _, role := range node.Roles {
+			if role == "data" {
+				dataNodes++
+				break
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, jsonObject{
+		"cluster_name":                    clusterName,
+		"status":                          f.health,
+		"number_of_nodes":                 len(f.nodes),
+		"number_of_data_nodes":            dataNodes,
+		"active_primary_shards":           len(f.indices),
+		"active_shards":                   len(f.indices),
+		"active_shards_percent_as_number": 100.0,
+	})
+}
+
+func (f *FakeOpenSearch) getNodes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeMethodNotAllowed(w, r)
+		return
+	}
+	nodes := jsonObject{}
+	for name, node := range f.nodes {
+		nodes[name] = node
+	}
+	writeJSON(w, http.StatusOK, jsonObject{
+		"_nodes":       jsonObject{"total": len(f.nodes), "successful": len(f.nodes), "failed": 0},
+		"cluster_name": clusterName,
+		"nodes":        nodes,
+	})
+}
+
+func (f *FakeOpenSearch) serveISM(w http.ResponseWriter, r *http.Request, api, name string) {
+	switch {
+	case api == "policies" && name == "" && r.Method == "GET":
+		f.listPolicies(w)
+	case api == "policies" && name != "" && r.Method == "GET":
+		f.getPolicy(w, name)
+	case api == "policies" && name != "" && r.Method == "PUT":
+		f.putPolicy(w, r, name)
+	case api == "policies" && name != "" && r.Method == "DELETE":
+		f.deletePolicy(w, name)
+	case api == "add" && name != "" && r.Method == "POST":
+		f.addPolicy(w, r, name)
+	case api == "explain" && r.Method == "GET":
+		f.explain(w, name)
+	default:
+		writeMethodNotAllowed(w, r)
+	}
+}
+
+func (f *FakeOpenSearch) listPolicies(w http.ResponseWriter) {
+	policies := []jsonObject{}
+	for _, name := range f.policyNames() {
+		policies = append(policies, f.policyResponse(name))
+	}
+	writeJSON(w, http.StatusOK, jsonObject{
+		"policies":       policies,
+		"total_policies": len(policies),
+	})
+}
+
+func (f *FakeOpenSearch) getPolicy(w http.ResponseWriter, name string) {
+	if _, ok := f.policies[name]; !ok {
+		writeError(w, http.StatusNotFound, "status_exception", "Policy not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, f.policyResponse(name))
+}
+
+//putPolicy creates a policy, or updates it if the sequence number and primary term of the update are current
+func (f *FakeOpenSearch) putPolicy(w http.ResponseWriter, r *http.Request, name string) {
+	body := struct {
+		Policy json.RawMessage `json:"policy"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Policy) == 0 {
+		writeError(w, http.StatusBadRequest, "parse_exception", "the request body must hold a policy")
+		return
+	}
+	query := r.URL.Query()
+	status := http.StatusCreated
+	existing, ok := f.policies[name]
+	if ok {
+		if query.Get("if_seq_no") != fmt.Sprint(existing.seqNo) || query.Get("if_primary_term") != fmt.Sprint(existing.primaryTerm) {
+			writeError(w, http.StatusConflict, "version_conflict_engine_exception",
+				fmt.Sprintf("[%s]: version conflict, required seqNo [%s], primary term [%s]", name, query.Get("if_seq_no"), query.Get("if_primary_term")))
+			return
+		}
+		status = http.StatusOK
+	} else {
+		existing = &fakePolicy{primaryTerm: 1}
+		f.policies[name] = existing
+	}
+	f.seqNo++
+	existing.seqNo = f.seqNo
+	existing.version++
+	existing.policy = body.Policy
+	writeJSON(w, status, jsonObject{
+		"_id":           name,
+		"_version":      existing.version,
+		"_seq_no":       existing.seqNo,
+		"_primary_term": existing.primaryTerm,
+		"policy":        jsonObject{"policy": existing.policy},
+	})
+}
+
+func (f *FakeOpenSearch) deletePolicy(w http.ResponseWriter, name string) {
+	if _, ok := f.policies[name]; !ok {
+		writeJSON(w, http.StatusNotFound, jsonObject{"_id": name, "result": "not_found"})
+		return
+	}
+	delete(f.policies, name)
+	writeJSON(w, http.StatusOK, jsonObject{"_id": name, "result": "deleted"})
+}
+
+//addPolicy manages the indices matching the pattern by the policy. Indices which are already managed fail, as in
+// OpenSearch, where the policy of a managed index is changed by the change policy API.
+func (f *FakeOpenSearch) addPolicy(w http.ResponseWriter, r *http.Request, pattern string) {
+	body := &addPolicyRequest{}
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil || body.PolicyID == "" {
+		writeError(w, http.StatusBadRequest, "illegal_argument_exception", "Missing policy_id")
+		return
+	}
+	if _, ok := f.policies[body.PolicyID]; !ok {
+		writeError(w, http.StatusNotFound, "status_exception", fmt.Sprintf("Could not find policy=%s", body.PolicyID))
+		return
+	}
+	updated := 0
+	failed := []failedIndex{}
+	for _, name := range f.resolveIndices(pattern) {
+		index := f.indices[name]
+		if index.policyID != "" {
+			failed = append(failed, failedIndex{IndexName: name, Reason: "This index already has a policy, use the update policy API to update index policies"})
+			continue
+		}
+		index.policyID = body.PolicyID
+		updated++
+	}
+	writeJSON(w, http.StatusOK, jsonObject{
+		"updated_indices": updated,
+		"failures":        len(failed) > 0,
+		"failed_indices":  failed,
+	})
+}
+
+//explain returns the policy of each index matching the pattern, or of all managed indices if there is no pattern
+func (f *FakeOpenSearch) explain(w http.ResponseWriter, pattern string) {
+	var names []string
+	if pattern == "" {
+		for name, index := range f.indices {
+			if index.policyID != "" {
+				names = append(names, name)
+			}
+		}
+	} else {
+		names = f.resolveIndices(pattern)
+		if len(names) == 0 && !strings.Contains(pattern, "*") {
+			writeIndexNotFound(w, pattern)
+			return
+		}
+	}
+	response := jsonObject{}
+	managed := 0
+	for _, name := range names {
+		index := f.indices[name]
+		if index.policyID == "" {
+			response[name] = jsonObject{policyIDSetting: nil}
+			continue
+		}
+		managed++
+		response[name] = jsonObject{
+			policyIDSetting: index.policyID,
+			"index":         name,
+			"policy_id":     index.policyID,
+			"enabled":       true,
+		}
+	}
+	response["total_managed_indices"] = managed
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (f *FakeOpenSearch) serveDataStream(w http.ResponseWriter, r *http.Request, name string) {
+	switch r.Method {
+	case "GET":
+		f.getDataStreams(w, name)
+	case "PUT":
+		if name == "" {
+			writeMethodNotAllowed(w, r)
+			return
+		}
+		if _, ok := f.dataStreams[name]; ok {
+			writeError(w, http.StatusBadRequest, "resource_already_exists_exception", fmt.Sprintf("data_stream [%s] already exists", name))
+			return
+		}
+		f.createDataStream(name)
+		writeJSON(w, http.StatusOK, jsonObject{"acknowledged": true})
+	case "DELETE":
+		if _, ok := f.dataStreams[name]; !ok {
+			writeError(w, http.StatusNotFound, "resource_not_found_exception", fmt.Sprintf("no such data stream [%s]", name))
+			return
+		}
+		for _, index := range f.dataStreams[name].indices {
+			delete(f.indices, index)
+		}
+		delete(f.dataStreams, name)
+		writeJSON(w, http.StatusOK, jsonObject{"acknowledged": true})
+	default:
+		writeMethodNotAllowed(w, r)
+	}
+}
+
+//getDataStreams returns the data streams matching the comma separated names, which may hold wildcards
+func (f *FakeOpenSearch) getDataStreams(w http.ResponseWriter, names string) {
+	if names == "" {
+		names = "*"
+	}
+	dataStreams := []jsonObject{}
+	for _, pattern := range strings.Split(names, ",") {
+		matched := false
+		for _, name := range f.dataStreamNames() {
+			if !matchPattern(pattern, name) {
+				continue
+			}
+			matched = true
+			var indices []jsonObject
+			for _, index := range f.dataStreams[name].indices {
+				indices = append(indices, jsonObject{"index_name": index})
+			}
+			dataStreams = append(dataStreams, jsonObject{
+				"name":            name,
+				"timestamp_field": jsonObject{"name": "@timestamp"},
+				"indices":         indices,
+				"generation":      len(indices),
+				"status":          strings.ToUpper(f.health),
+			})
+		}
+		if !matched && !strings.Contains(pattern, "*") {
+			writeError(w, http.StatusNotFound, "resource_not_found_exception", fmt.Sprintf("no such data stream [%s]", pattern))
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, jsonObject{"data_streams": dataStreams})
+}
+
+func (f *FakeOpenSearch) getAliases(w http.ResponseWriter) {
+	aliases := jsonObject{}
+	for name := range f.indices {
+		aliases[name] = jsonObject{"aliases": jsonObject{}}
+	}
+	writeJSON(w, http.StatusOK, aliases)
+}
+
+func (f *FakeOpenSearch) count(w http.ResponseWriter, name string) {
+	if _, ok := f.indices[name]; !ok {
+		if _, ok := f.dataStreams[name]; !ok {
+			writeIndexNotFound(w, name)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, jsonObject{"count": f.countDocs(name)})
+}
+
+//reindex copies all documents of the source to the destination, ignoring any query of the request. Documents which
+// already exist in the destination are version conflicts. Unless wait_for_completion is false, the response holds
+// the result of the reindex, otherwise a task which has already completed.
+func (f *FakeOpenSearch) reindex(w http.ResponseWriter, r *http.Request) {
+	body := &reindexRequest{}
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		writeError(w, http.StatusBadRequest, "parse_exception", err.Error())
+		return
+	}
+	source, dest := body.Source.Index, body.Dest.Index
+	if f.indices[source] == nil && f.dataStreams[source] == nil {
+		writeIndexNotFound(w, source)
+		return
+	}
+	if f.dataStreams[dest] == nil && f.indices[dest] == nil && f.matchesTemplate(dest) {
+		f.createDataStream(dest)
+	}
+	if f.dataStreams[dest] != nil && body.Dest.OpType != "create" {
+		writeError(w, http.StatusBadRequest, "illegal_argument_exception", "only write ops with an op_type of create are allowed in data streams")
+		return
+	}
+
+	target := f.getWriteIndex(dest)
+	status := reindexStatus{Failures: []interface{}{}}
+	for _, name := range f.getBackingIndices(source) {
+		for id := range f.indices[name].docs {
+			status.Total++
+			if target.docs[id] {
+				status.VersionConflicts++
+				continue
+			}
+			target.docs[id] = true
+			status.Created++
+		}
+	}
+
+	if r.URL.Query().Get("wait_for_completion") != "false" {
+		writeJSON(w, http.StatusOK, status)
+		return
+	}
+	f.taskCount++
+	taskID := fmt.Sprintf("%s:%d", clusterName, f.taskCount)
+	task := &fakeTask{Completed: true, Response: status}
+	task.Task.Status = status
+	f.tasks[taskID] = task
+	writeJSON(w, http.StatusOK, jsonObject{"task": taskID})
+}
+
+//rethrottle fails for all known tasks, as tasks complete immediately
+func (f *FakeOpenSearch) rethrottle(w http.ResponseWriter, taskID string) {
+	if _, ok := f.tasks[taskID]; ok {
+		writeError(w, http.StatusNotFound, "resource_not_found_exception", fmt.Sprintf("task [%s] has already completed", taskID))
+		return
+	}
+	writeError(w, http.StatusNotFound, "resource_not_found_exception", fmt.Sprintf("task [%s] isn't running and hasn't stored its results", taskID))
+}
+
+func (f *FakeOpenSearch) getTask(w http.ResponseWriter, taskID string) {
+	task, ok := f.tasks[taskID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "resource_not_found_exception", fmt.Sprintf("task [%s] isn't running and hasn't stored its results", taskID))
+		return
+	}
+	writeJSON(w, http.StatusOK, task)
+}
+
+//deleteIndex deletes the index. The write index of a data stream cannot be deleted.
+func (f *FakeOpenSearch) deleteIndex(w http.ResponseWriter, name string) {
+	index, ok := f.indices[name]
+	if !ok {
+		writeIndexNotFound(w, name)
+		return
+	}
+	if index.dataStream != "" {
+		dataStream := f.dataStreams[index.dataStream]
+		if dataStream.indices[len(dataStream.indices)-1] == name {
+			writeError(w, http.StatusBadRequest, "illegal_argument_exception",
+				fmt.Sprintf("index [%s] is the write index for data stream [%s] and cannot be deleted", name, index.dataStream))
+			return
+		}
+		var remaining []string
+		for _, backingIndex := range dataStream.indices {
+			if backingIndex != name {
+				remaining = append(remaining, backingIndex)
+			}
+		}
+		dataStream.indices = remaining
+	}
+	delete(f.indices, name)
+	writeJSON(w, http.StatusOK, jsonObject{"acknowledged": true})
+}
+
+func (f *FakeOpenSearch) createIndex(name, dataStream string) *fakeIndex {
+	index := &fakeIndex{docs: map[string]bool{}, dataStream: dataStream}
+	f.indices[name] = index
+	return index
+}
+
+func (f *FakeOpenSearch) createDataStream(name string) {
+	backingIndex := fmt.Sprintf(backingIndexFormat, name, 1)
+	f.createIndex(backingIndex, name)
+	f.dataStreams[name] = &fakeDataStream{indices: []string{backingIndex}}
+}
+
+func (f *FakeOpenSearch) matchesTemplate(name string) bool {
+	for _, pattern := range f.templates {
+		if matchPattern(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+//getWriteIndex returns the index documents written to the index or data stream are stored in, creating the index
+// if it does not exist
+func (f *FakeOpenSearch) getWriteIndex(name string) *fakeIndex {
+	if dataStream, ok := f.dataStreams[name]; ok {
+		return f.indices[dataStream.indices[len(dataStream.indices)-1]]
+	}
+	if index, ok := f.indices[name]; ok {
+		return index
+	}
+	return f.createIndex(name, "")
+}
+
+//getBackingIndices returns the backing indices of a data stream, or the index itself
+func (f *FakeOpenSearch) getBackingIndices(name string) []string {
+	if dataStream, ok := f.dataStreams[name]; ok {
+		return dataStream.indices
+	}
+	if _, ok := f.indices[name]; ok {
+		return []string{name}
+	}
+	return nil
+}
+
+func (f *FakeOpenSearch) countDocs(name string) int {
+	count := 0
+	for _, index := range f.getBackingIndices(name) {
+		count += len(f.indices[index].docs)
+	}
+	return count
+}
+
+//resolveIndices returns the indices matching the comma separated patterns, in name order. Data streams resolve to
+// their backing indices.
+func (f *FakeOpenSearch) resolveIndices(patterns string) []string {
+	resolved := map[string]bool{}
+	for _, pattern := range strings.Split(patterns, ",") {
+		for name := range f.indices {
+			if matchPattern(pattern, name) {
+				resolved[name] = true
+			}
+		}
+		for name, dataStream := range f.dataStreams {
+			if matchPattern(pattern, name) {
+				for _, index := range dataStream.indices {
+					resolved[index] = true
+				}
+			}
+		}
+	}
+	return sortedKeys(resolved)
+}
+
+func (f *FakeOpenSearch) policyNames() []string {
+	names := map[string]bool{}
+	for name := range f.policies {
+		names[name] = true
+	}
+	return sortedKeys(names)
+}
+
+func (f *FakeOpenSearch) dataStreamNames() []string {
+	names := map[string]bool{}
+	for name := range f.dataStreams {
+		names[name] = true
+	}
+	return sortedKeys(names)
+}
+
+func (f *FakeOpenSearch) policyResponse(name string) jsonObject {
+	policy := f.policies[name]
+	return jsonObject{
+		"_id":           name,
+		"_version":      policy.version,
+		"_seq_no":       policy.seqNo,
+		"_primary_term": policy.primaryTerm,
+		"policy":        policy.policy,
+	}
+}
+
+//matchPattern matches a name to a pattern with * wildcards. As in OpenSearch, wildcards do not match hidden names,
+// which start with a dot, unless the pattern does.
+func matchPattern(pattern, name string) bool {
+	if strings.HasPrefix(name, ".") && !strings.HasPrefix(pattern, ".") {
+		return false
+	}
+	matched, err := path.Match(pattern, name)
+	return err == nil && matched
+}
+
+func sortedKeys(m map[string]bool) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, errorType, reason string) {
+	writeJSON(w, status, jsonObject{
+		"error":  jsonObject{"type": errorType, "reason": reason},
+		"status": status,
+	})
+}
+
+func writeIndexNotFound(w http.ResponseWriter, name string) {
+	writeError(w, http.StatusNotFound, "index_not_found_exception", fmt.Sprintf("no such index [%s]", name))
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusMethodNotAllowed, "method_not_allowed",
+		fmt.Sprintf("Incorrect HTTP method for uri [%s] and method [%s]", r.URL.Path, r.Method))
+}
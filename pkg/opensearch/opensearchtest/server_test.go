@@ -0,0 +1,147 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearchtest
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const vmiEndpoint = "http://vmi-system-es-master-http.verrazzano-system.svc.cluster.local:9200"
+
+//do sends a request to the OpenSearch endpoint of a VMI, and returns the status and decoded response
+func do(t *testing.T, f *FakeOpenSearch, method, path, body string) (int, map[string]interface{}) {
+	req, err := http.NewRequest(method, vmiEndpoint+path, strings.NewReader(body))
+	assert.NoError(t, err)
+	resp, err := f.DoHTTP(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	response := map[string]interface{}{}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+	return resp.StatusCode, response
+}
+
+// TestFakeOpenSearchCluster Tests the cluster APIs of the fake OpenSearch server
+// GIVEN a fake OpenSearch server with nodes
+// WHEN the cluster health and nodes are requested from the OpenSearch endpoint of a VMI
+// THEN the fake server responds with its state, and APIs which are not implemented fail
+func TestFakeOpenSearchCluster(t *testing.T) {
+	f := NewFakeOpenSearch()
+	defer f.Close()
+	f.AddNode("master-0", "2.3.0", "master")
+	f.AddNode("data-0", "2.3.0", "data", "ingest")
+	f.SetHealth("yellow")
+
+	status, health := do(t, f, "GET", "/_cluster/health", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "yellow", health["status"])
+	assert.EqualValues(t, 2, health["number_of_nodes"])
+	assert.EqualValues(t, 1, health["number_of_data_nodes"])
+
+	status, nodes := do(t, f, "GET", "/_nodes/settings", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, nodes["nodes"], 2)
+
+	f.RemoveNode("data-0")
+	_, nodes = do(t, f, "GET", "/_nodes", "")
+	assert.Len(t, nodes["nodes"], 1)
+
+	status, _ = do(t, f, "PUT", "/_cluster/settings", "{}")
+	assert.Equal(t, http.StatusNotImplemented, status)
+	assert.Equal(t, []string{"GET /_cluster/health", "GET /_nodes/settings", "GET /_nodes", "PUT /_cluster/settings"}, f.Requests())
+}
+
+// TestFakeOpenSearchISM Tests the ISM APIs of the fake OpenSearch server
+// GIVEN a fake OpenSearch server with indices
+// WHEN policies are created, updated, added to indices and deleted
+// THEN policies are only updated at their current sequence number, and each index is managed by a single policy
+func TestFakeOpenSearchISM(t *testing.T) {
+	f := NewFakeOpenSearch()
+	defer f.Close()
+	f.AddIndex("verrazzano-system", 1)
+	f.AddIndex("other", 1)
+	const policy = `{"policy": {"description": "test", "default_state": "ingest", "states": []}}`
+
+	status, created := do(t, f, "PUT", "/_plugins/_ism/policies/test", policy)
+	assert.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, "test", created["_id"])
+	status, _ = do(t, f, "PUT", "/_plugins/_ism/policies/test", policy)
+	assert.Equal(t, http.StatusConflict, status)
+	status, existing := do(t, f, "GET", "/_plugins/_ism/policies/test", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "test", existing["policy"].(map[string]interface{})["description"])
+	status, _ = do(t, f, "PUT", "/_plugins/_ism/policies/test?if_seq_no=1&if_primary_term=1", policy)
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = do(t, f, "GET", "/_plugins/_ism/policies/missing", "")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, added := do(t, f, "POST", "/_plugins/_ism/add/verrazzano-*", `{"policy_id": "test"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.EqualValues(t, 1, added["updated_indices"])
+	_, added = do(t, f, "POST", "/_plugins/_ism/add/*", `{"policy_id": "test"}`)
+	assert.EqualValues(t, 1, added["updated_indices"])
+	assert.Equal(t, true, added["failures"])
+	assert.Equal(t, map[string]string{"verrazzano-system": "test", "other": "test"}, f.ManagedIndices())
+
+	status, explained := do(t, f, "GET", "/_plugins/_ism/explain/verrazzano-system", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "test", explained["verrazzano-system"].(map[string]interface{})["policy_id"])
+	assert.EqualValues(t, 1, explained["total_managed_indices"])
+
+	_, list := do(t, f, "GET", "/_plugins/_ism/policies", "")
+	assert.EqualValues(t, 1, list["total_policies"])
+	status, _ = do(t, f, "DELETE", "/_plugins/_ism/policies/test", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Empty(t, f.Policies())
+}
+
+// TestFakeOpenSearchReindex Tests reindexing an index to a data stream on the fake OpenSearch server
+// GIVEN a fake OpenSearch server with an index and a data stream template
+// WHEN the index is reindexed twice to a data stream, and deleted
+// THEN the data stream is created with the documents of the index, and the second reindex only has version conflicts
+func TestFakeOpenSearchReindex(t *testing.T) {
+	f := NewFakeOpenSearch()
+	defer f.Close()
+	f.AddIndex("verrazzano-namespace-bobs-books", 5)
+	f.AddDataStreamTemplate("verrazzano-application*")
+	const reindex = `{"source": {"index": "verrazzano-namespace-bobs-books"}, "dest": {"index": "verrazzano-application-bobs-books", "op_type": "create"}}`
+
+	status, _ := do(t, f, "GET", "/_data_stream/verrazzano-application-bobs-books", "")
+	assert.Equal(t, http.StatusNotFound, status)
+	status, started := do(t, f, "POST", "/_reindex?wait_for_completion=false", reindex)
+	assert.Equal(t, http.StatusOK, status)
+	status, task := do(t, f, "GET", "/_tasks/"+started["task"].(string), "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, true, task["completed"])
+	assert.EqualValues(t, 5, task["response"].(map[string]interface{})["created"])
+	status, _ = do(t, f, "POST", "/_reindex/"+started["task"].(string)+"/_rethrottle?requests_per_second=10", "")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, dataStreams := do(t, f, "GET", "/_data_stream/verrazzano-application-bobs-books", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, dataStreams["data_streams"], 1)
+	_, count := do(t, f, "POST", "/verrazzano-application-bobs-books/_count", "")
+	assert.EqualValues(t, 5, count["count"])
+
+	_, result := do(t, f, "POST", "/_reindex", reindex)
+	assert.EqualValues(t, 0, result["created"])
+	assert.EqualValues(t, 5, result["version_conflicts"])
+	status, _ = do(t, f, "POST", "/_reindex", strings.Replace(reindex, `"create"`, `"index"`, 1))
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	_, aliases := do(t, f, "GET", "/_aliases", "")
+	assert.Contains(t, aliases, "verrazzano-namespace-bobs-books")
+	assert.Contains(t, aliases, ".ds-verrazzano-application-bobs-books-000001")
+	status, _ = do(t, f, "DELETE", "/verrazzano-namespace-bobs-books", "")
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = do(t, f, "DELETE", "/verrazzano-namespace-bobs-books", "")
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = do(t, f, "DELETE", "/.ds-verrazzano-application-bobs-books-000001", "")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, map[string]int{".ds-verrazzano-application-bobs-books-000001": 5}, f.Indices())
+	assert.Equal(t, map[string]int{"verrazzano-application-bobs-books": 5}, f.DataStreams())
+}
@@ -0,0 +1,59 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"io/ioutil"
+	"net/http"
+)
+
+const (
+	// AllocationEnableSetting is the cluster setting which controls which shards may be allocated
+	AllocationEnableSetting = "cluster.routing.allocation.enable"
+	// AllocationPrimaries only allows primary shards to be allocated
+	AllocationPrimaries = "primaries"
+)
+
+//PrepareNodeRestart restricts shard allocation to primaries and flushes all indices, so a restarted node
+// recovers its shards from local data instead of the cluster copying them to other nodes.
+func (o *OSClient) PrepareNodeRestart(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	opensearchEndpoint := resources.GetOpenSearchHTTPEndpoint(vmi)
+	if err := o.putClusterSettings(opensearchEndpoint, &ClusterSettings{
+		Persistent: map[string]interface{}{
+			AllocationEnableSetting: AllocationPrimaries,
+		},
+	}); err != nil {
+		return err
+	}
+	return o.flush(opensearchEndpoint)
+}
+
+//FinishNodeRestart re-enables shard allocation after a restarted node has rejoined the cluster
+func (o *OSClient) FinishNodeRestart(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	return o.putClusterSettings(resources.GetOpenSearchHTTPEndpoint(vmi), &ClusterSettings{
+		Persistent: map[string]interface{}{
+			AllocationEnableSetting: nil,
+		},
+	})
+}
+
+func (o *OSClient) flush(opensearchEndpoint string) error {
+	req, err := http.NewRequest("POST", opensearchEndpoint+"/_flush", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		responseBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("got status code %d when flushing indices: %s", resp.StatusCode, string(responseBody))
+	}
+	return nil
+}
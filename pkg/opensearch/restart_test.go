@@ -0,0 +1,95 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNodeRestart(t *testing.T) {
+	var requests []string
+	var settings []map[string]interface{}
+	flushStatus := http.StatusOK
+	o := NewOSClient()
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		requests = append(requests, request.Method+" "+request.URL.Path)
+		statusCode := http.StatusOK
+		if request.URL.Path == "/_flush" {
+			statusCode = flushStatus
+		} else {
+			clusterSettings := &ClusterSettings{}
+			assert.NoError(t, json.NewDecoder(request.Body).Decode(clusterSettings))
+			settings = append(settings, clusterSettings.Persistent)
+		}
+		return &http.Response{
+			StatusCode: statusCode,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	}
+
+	assert.NoError(t, o.PrepareNodeRestart(testDrainVMI()))
+	assert.NoError(t, o.FinishNodeRestart(testDrainVMI()))
+	assert.Equal(t, []string{"PUT /_cluster/settings", "POST /_flush", "PUT /_cluster/settings"}, requests)
+	assert.Equal(t, []map[string]interface{}{
+		{AllocationEnableSetting: AllocationPrimaries},
+		{AllocationEnableSetting: nil},
+	}, settings)
+
+	flushStatus = http.StatusInternalServerError
+	assert.Error(t, o.PrepareNodeRestart(testDrainVMI()))
+}
+
+// TestNodeRestartWithClusterSettings Tests restarting a node while the cluster settings are configured
+// GIVEN a VMI whose cluster settings include the shard allocation, which was applied before
+// WHEN the cluster settings are configured between preparing and finishing the restart of a node
+// THEN only primaries are allocated until the restart is finished, and the spec setting is rejected
+func TestNodeRestartWithClusterSettings(t *testing.T) {
+	persistent := map[string]interface{}{AllocationEnableSetting: "all"}
+	o := NewOSClient()
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		body := ""
+		switch {
+		case request.Method == "GET":
+			payload, _ := json.Marshal(&ClusterSettings{Persistent: persistent})
+			body = string(payload)
+		case request.URL.Path == "/_cluster/settings":
+			clusterSettings := &ClusterSettings{}
+			assert.NoError(t, json.NewDecoder(request.Body).Decode(clusterSettings))
+			for k, v := range clusterSettings.Persistent {
+				if v == nil {
+					delete(persistent, k)
+				} else {
+					persistent[k] = v
+				}
+			}
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil
+	}
+	vmi := testDrainVMI()
+	vmi.Spec.Elasticsearch.ClusterSettings = map[string]string{
+		AllocationEnableSetting:       "all",
+		"cluster.max_shards_per_node": "2000",
+	}
+	previous := map[string]string{AllocationEnableSetting: "all"}
+
+	assert.NoError(t, o.PrepareNodeRestart(vmi))
+	assert.Error(t, <-o.ConfigureClusterSettings(vmi, GetClusterSettings(vmi, nil), previous))
+	assert.Equal(t, map[string]interface{}{
+		AllocationEnableSetting:       AllocationPrimaries,
+		"cluster.max_shards_per_node": "2000",
+	}, persistent)
+
+	// the spec setting is not applied once the restart is finished either
+	assert.NoError(t, o.FinishNodeRestart(vmi))
+	assert.Error(t, <-o.ConfigureClusterSettings(vmi, GetClusterSettings(vmi, nil), previous))
+	assert.Equal(t, map[string]interface{}{"cluster.max_shards_per_node": "2000"}, persistent)
+}
@@ -0,0 +1,328 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	"io/ioutil"
+	"net/http"
+	"sort"
+)
+
+type (
+	Role struct {
+		Description        string             `json:"description,omitempty"`
+		ClusterPermissions []string           `json:"cluster_permissions"`
+		IndexPermissions   []IndexPermission  `json:"index_permissions"`
+		TenantPermissions  []TenantPermission `json:"tenant_permissions,omitempty"`
+		Reserved           bool               `json:"reserved,omitempty"`
+		Static             bool               `json:"static,omitempty"`
+	}
+
+	IndexPermission struct {
+		IndexPatterns  []string `json:"index_patterns"`
+		AllowedActions []string `json:"allowed_actions"`
+	}
+
+	TenantPermission struct {
+		TenantPatterns []string `json:"tenant_patterns"`
+		AllowedActions []string `json:"allowed_actions"`
+	}
+
+	RoleMapping struct {
+		Description  string   `json:"description,omitempty"`
+		BackendRoles []string `json:"backend_roles"`
+		Users        []string `json:"users"`
+		Reserved     bool     `json:"reserved,omitempty"`
+	}
+
+	InternalUser struct {
+		Description  string            `json:"description,omitempty"`
+		Password     string            `json:"password,omitempty"`
+		BackendRoles []string          `json:"backend_roles"`
+		Attributes   map[string]string `json:"attributes"`
+		Reserved     bool              `json:"reserved,omitempty"`
+		Static       bool              `json:"static,omitempty"`
+	}
+
+	//InternalUserCredentials holds an internal user's password. The password is only updated when its version changes,
+	// as OpenSearch does not return passwords.
+	InternalUserCredentials struct {
+		Password        string
+		PasswordVersion string
+	}
+)
+
+const (
+	securityAPI          = "_plugins/_security/api"
+	securityRoles        = "roles"
+	securityRoleMappings = "rolesmapping"
+	securityUsers        = "internalusers"
+	securityTenants      = "tenants"
+	// Descriptor to identify security resources as being managed by the VMI
+	vmiManagedSecurity = "__vmi-managed__"
+	// passwordVersionAttribute is the internal user attribute holding the version of the user's password
+	passwordVersionAttribute = "vmi_password_version"
+)
+
+//ConfigureSecurity creates or updates the roles, role mappings and internal users of the OpenSearch security plugin,
+// correcting any changes made outside of the VMI. Resources managed by the VMI, but no longer in the VMI, are deleted.
+// Existing resources which were not created by the VMI are never updated nor deleted.
+// The returned channel should be read for exactly one response, which tells whether the security configuration succeeded.
+func (o *OSClient) ConfigureSecurity(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, credentials map[string]InternalUserCredentials) chan error {
+	ch := make(chan error)
+	security := vmi.Spec.Elasticsearch.Security.DeepCopy()
+	enabled := vmi.Spec.Elasticsearch.Enabled
+	opensearchEndpoint := resources.GetOpenSearchHTTPEndpoint(vmi)
+	// configuration is done asynchronously, as this does not need to be blocking
+	go func() {
+		if !enabled || security == nil {
+			ch <- nil
+			return
+		}
+		if err := o.reconcileRoles(log, opensearchEndpoint, security.Roles); err != nil {
+			ch <- err
+			return
+		}
+		if err := o.reconcileRoleMappings(log, opensearchEndpoint, security.RoleMappings); err != nil {
+			ch <- err
+			return
+		}
+		ch <- o.reconcileInternalUsers(log, opensearchEndpoint, security.InternalUsers, credentials)
+	}()
+	return ch
+}
+
+func (o *OSClient) reconcileRoles(log vzlog.VerrazzanoLogger, opensearchEndpoint string, roles []vmcontrollerv1.SecurityRole) error {
+	existing := map[string]Role{}
+	if err := o.getJSON(fmt.Sprintf("%s/%s/%s", opensearchEndpoint, securityAPI, securityRoles), &existing); err != nil {
+		return err
+	}
+	expected := map[string]bool{}
+	for _, role := range roles {
+		expected[role.Name] = true
+		desired := toRole(role)
+		current, ok := existing[role.Name]
+		if ok && isSameRole(current, desired) {
+			continue
+		}
+		if current.Reserved || current.Static {
+			return fmt.Errorf("OpenSearch role %s is reserved, and cannot be updated", role.Name)
+		}
+		if ok && current.Description != vmiManagedSecurity {
+			return fmt.Errorf("OpenSearch role %s was not created by the VMI, and is not taken over", role.Name)
+		}
+		if ok {
+			log.Oncef("Correcting OpenSearch role %s, which differs from the VMI", role.Name)
+		}
+		if err := o.putSecurityResource(opensearchEndpoint, securityRoles, role.Name, desired); err != nil {
+			return err
+		}
+	}
+	var pruned []string
+	for name, role := range existing {
+		if role.Description == vmiManagedSecurity && !expected[name] {
+			pruned = append(pruned, name)
+		}
+	}
+	return o.deleteSecurityResources(opensearchEndpoint, securityRoles, pruned)
+}
+
+func (o *OSClient) reconcileRoleMappings(log vzlog.VerrazzanoLogger, opensearchEndpoint string, roleMappings []vmcontrollerv1.SecurityRoleMapping) error {
+	existing := map[string]RoleMapping{}
+	if err := o.getJSON(fmt.Sprintf("%s/%s/%s", opensearchEndpoint, securityAPI, securityRoleMappings), &existing); err != nil {
+		return err
+	}
+	expected := map[string]bool{}
+	for _, roleMapping := range roleMappings {
+		expected[roleMapping.Role] = true
+		desired := RoleMapping{
+			Description:  vmiManagedSecurity,
+			BackendRoles: nonNilStrings(roleMapping.BackendRoles),
+			Users:        nonNilStrings(roleMapping.Users),
+		}
+		current, ok := existing[roleMapping.Role]
+		if ok && current.Description == desired.Description && isSameStrings(current.BackendRoles, desired.BackendRoles) && isSameStrings(current.Users, desired.Users) {
+			continue
+		}
+		if current.Reserved {
+			return fmt.Errorf("OpenSearch role mapping %s is reserved, and cannot be updated", roleMapping.Role)
+		}
+		if ok && current.Description != vmiManagedSecurity {
+			return fmt.Errorf("OpenSearch role mapping %s was not created by the VMI, and is not taken over", roleMapping.Role)
+		}
+		if ok {
+			log.Oncef("Correcting OpenSearch role mapping %s, which differs from the VMI", roleMapping.Role)
+		}
+		if err := o.putSecurityResource(opensearchEndpoint, securityRoleMappings, roleMapping.Role, desired); err != nil {
+			return err
+		}
+	}
+	var pruned []string
+	for name, roleMapping := range existing {
+		if roleMapping.Description == vmiManagedSecurity && !expected[name] {
+			pruned = append(pruned, name)
+		}
+	}
+	return o.deleteSecurityResources(opensearchEndpoint, securityRoleMappings, pruned)
+}
+
+func (o *OSClient) reconcileInternalUsers(log vzlog.VerrazzanoLogger, opensearchEndpoint string, users []vmcontrollerv1.SecurityInternalUser, credentials map[string]InternalUserCredentials) error {
+	existing := map[string]InternalUser{}
+	if err := o.getJSON(fmt.Sprintf("%s/%s/%s", opensearchEndpoint, securityAPI, securityUsers), &existing); err != nil {
+		return err
+	}
+	expected := map[string]bool{}
+	for _, user := range users {
+		expected[user.Username] = true
+		credential, ok := credentials[user.Username]
+		if !ok {
+			// the user's optional password Secret does not exist
+			continue
+		}
+		desired := InternalUser{
+			Description:  vmiManagedSecurity,
+			BackendRoles: nonNilStrings(user.BackendRoles),
+			Attributes:   map[string]string{passwordVersionAttribute: credential.PasswordVersion},
+		}
+		current, ok := existing[user.Username]
+		if ok && current.Description == desired.Description && isSameStrings(current.BackendRoles, desired.BackendRoles) &&
+			current.Attributes[passwordVersionAttribute] == credential.PasswordVersion {
+			continue
+		}
+		if current.Reserved || current.Static {
+			return fmt.Errorf("OpenSearch internal user %s is reserved, and cannot be updated", user.Username)
+		}
+		if ok && current.Description != vmiManagedSecurity {
+			return fmt.Errorf("OpenSearch internal user %s was not created by the VMI, and is not taken over", user.Username)
+		}
+		if ok {
+			log.Oncef("Updating OpenSearch internal user %s, which differs from the VMI", user.Username)
+		}
+		desired.Password = credential.Password
+		if err := o.putSecurityResource(opensearchEndpoint, securityUsers, user.Username, desired); err != nil {
+			return err
+		}
+	}
+	var pruned []string
+	for name, user := range existing {
+		if user.Description == vmiManagedSecurity && !expected[name] {
+			pruned = append(pruned, name)
+		}
+	}
+	return o.deleteSecurityResources(opensearchEndpoint, securityUsers, pruned)
+}
+
+func (o *OSClient) putSecurityResource(opensearchEndpoint, kind, name string, resource interface{}) error {
+	payload, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/%s/%s/%s", opensearchEndpoint, securityAPI, kind, name)
+	req, err := http.NewRequest("PUT", url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		responseBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("got status code %d when updating %s %s: %s", resp.StatusCode, kind, name, string(responseBody))
+	}
+	return nil
+}
+
+//deleteSecurityResources deletes the named resources of a kind, in a stable order
+func (o *OSClient) deleteSecurityResources(opensearchEndpoint, kind string, names []string) error {
+	sort.Strings(names)
+	for _, name := range names {
+		if err := o.deleteSecurityResource(opensearchEndpoint, kind, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *OSClient) deleteSecurityResource(opensearchEndpoint, kind, name string) error {
+	url := fmt.Sprintf("%s/%s/%s/%s", opensearchEndpoint, securityAPI, kind, name)
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("got status code %d when deleting %s %s", resp.StatusCode, kind, name)
+	}
+	return nil
+}
+
+func toRole(role vmcontrollerv1.SecurityRole) Role {
+	r := Role{
+		Description:        vmiManagedSecurity,
+		ClusterPermissions: nonNilStrings(role.ClusterPermissions),
+		IndexPermissions:   []IndexPermission{},
+	}
+	for _, permission := range role.IndexPermissions {
+		r.IndexPermissions = append(r.IndexPermissions, IndexPermission{
+			IndexPatterns:  nonNilStrings(permission.IndexPatterns),
+			AllowedActions: nonNilStrings(permission.AllowedActions),
+		})
+	}
+	return r
+}
+
+//nonNilStrings returns an empty list for a nil list, as the security plugin rejects null lists
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+//isSameRole returns true if the roles have the same description and permissions, so they need no update
+func isSameRole(a, b Role) bool {
+	if a.Description != b.Description || !isSameStrings(a.ClusterPermissions, b.ClusterPermissions) ||
+		len(a.IndexPermissions) != len(b.IndexPermissions) || len(a.TenantPermissions) != len(b.TenantPermissions) {
+		return false
+	}
+	for i := range a.TenantPermissions {
+		if !isSameStrings(a.TenantPermissions[i].TenantPatterns, b.TenantPermissions[i].TenantPatterns) ||
+			!isSameStrings(a.TenantPermissions[i].AllowedActions, b.TenantPermissions[i].AllowedActions) {
+			return false
+		}
+	}
+	for i := range a.IndexPermissions {
+		if !isSameStrings(a.IndexPermissions[i].IndexPatterns, b.IndexPermissions[i].IndexPatterns) ||
+			!isSameStrings(a.IndexPermissions[i].AllowedActions, b.IndexPermissions[i].AllowedActions) {
+			return false
+		}
+	}
+	return true
+}
+
+//isSameStrings compares string lists, where a nil list is the same as an empty list
+func isSameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,158 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestConfigureSecurity Tests the reconciliation of OpenSearch security resources
+// GIVEN a VMI with roles, role mappings and internal users
+// WHEN I call ConfigureSecurity
+// THEN changed or missing resources are updated, unchanged resources are left alone, and removed VMI managed resources are deleted,
+// while resources not created by the VMI are neither updated nor deleted
+func TestConfigureSecurity(t *testing.T) {
+	const (
+		roles = `{
+			"reader": {"description": "__vmi-managed__", "cluster_permissions": ["cluster_monitor"], "index_permissions": [{"index_patterns": ["*"], "allowed_actions": ["read"]}]},
+			"removed": {"description": "__vmi-managed__", "cluster_permissions": [], "index_permissions": []},
+			"all_access": {"reserved": true, "cluster_permissions": ["*"], "index_permissions": []},
+			"kibana_user": {"cluster_permissions": ["cluster_composite_ops"], "index_permissions": []}
+		}`
+		roleMappings = `{
+			"reader": {"description": "__vmi-managed__", "backend_roles": ["vz_log_reader"], "users": []},
+			"all_access": {"reserved": false, "backend_roles": ["admin"], "users": []}
+		}`
+		users = `{
+			"reader": {"description": "__vmi-managed__", "backend_roles": [], "attributes": {"vmi_password_version": "1"}},
+			"admin": {"reserved": true, "backend_roles": ["admin"], "attributes": {}},
+			"kibanaro": {"backend_roles": ["kibanauser"], "attributes": {}}
+		}`
+	)
+	vmi := createISMVMI("1d", true)
+	vmi.Spec.Elasticsearch.Security = &vmcontrollerv1.OpenSearchSecurity{
+		Roles: []vmcontrollerv1.SecurityRole{
+			{
+				Name:               "reader",
+				ClusterPermissions: []string{"cluster_monitor"},
+				IndexPermissions: []vmcontrollerv1.SecurityIndexPermission{
+					{IndexPatterns: []string{"verrazzano-*"}, AllowedActions: []string{"read"}},
+				},
+			},
+		},
+		RoleMappings: []vmcontrollerv1.SecurityRoleMapping{
+			{Role: "reader", BackendRoles: []string{"vz_log_reader"}},
+		},
+		InternalUsers: []vmcontrollerv1.SecurityInternalUser{
+			{Username: "reader"},
+			{Username: "writer", BackendRoles: []string{"writers"}},
+			{Username: "optional"},
+		},
+	}
+	credentials := map[string]InternalUserCredentials{
+		"reader": {Password: "unchanged", PasswordVersion: "1"},
+		"writer": {Password: "changeme", PasswordVersion: "2"},
+	}
+
+	var updated, deleted []string
+	var writer InternalUser
+	o := NewOSClient()
+	o.DoHTTP = func(req *http.Request) (*http.Response, error) {
+		body := ""
+		switch {
+		case req.Method == "GET" && req.URL.Path == "/_plugins/_security/api/roles":
+			body = roles
+		case req.Method == "GET" && req.URL.Path == "/_plugins/_security/api/rolesmapping":
+			body = roleMappings
+		case req.Method == "GET" && req.URL.Path == "/_plugins/_security/api/internalusers":
+			body = users
+		case req.Method == "PUT":
+			updated = append(updated, strings.TrimPrefix(req.URL.Path, "/_plugins/_security/api/"))
+			if strings.HasSuffix(req.URL.Path, "/writer") {
+				payload, _ := ioutil.ReadAll(req.Body)
+				assert.NoError(t, json.Unmarshal(payload, &writer))
+			}
+		case req.Method == "DELETE":
+			deleted = append(deleted, strings.TrimPrefix(req.URL.Path, "/_plugins/_security/api/"))
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil
+	}
+	assert.NoError(t, <-o.ConfigureSecurity(vzlog.DefaultLogger(), vmi, credentials))
+	assert.Equal(t, []string{"roles/reader", "internalusers/writer"}, updated)
+	assert.Equal(t, []string{"roles/removed"}, deleted)
+	assert.Equal(t, "changeme", writer.Password)
+	assert.Equal(t, "2", writer.Attributes[passwordVersionAttribute])
+	assert.Equal(t, vmiManagedSecurity, writer.Description)
+
+	// reserved resources are not changed
+	vmi.Spec.Elasticsearch.Security.Roles = []vmcontrollerv1.SecurityRole{{Name: "all_access"}}
+	assert.Error(t, <-o.ConfigureSecurity(vzlog.DefaultLogger(), vmi, credentials))
+
+	// resources not created by the VMI are not taken over
+	updated = nil
+	vmi.Spec.Elasticsearch.Security.Roles = []vmcontrollerv1.SecurityRole{{Name: "kibana_user"}}
+	assert.EqualError(t, <-o.ConfigureSecurity(vzlog.DefaultLogger(), vmi, credentials), "OpenSearch role kibana_user was not created by the VMI, and is not taken over")
+	vmi.Spec.Elasticsearch.Security.Roles = nil
+	vmi.Spec.Elasticsearch.Security.RoleMappings = []vmcontrollerv1.SecurityRoleMapping{{Role: "all_access", BackendRoles: []string{"vz_admin"}}}
+	assert.EqualError(t, <-o.ConfigureSecurity(vzlog.DefaultLogger(), vmi, credentials), "OpenSearch role mapping all_access was not created by the VMI, and is not taken over")
+	vmi.Spec.Elasticsearch.Security.RoleMappings = nil
+	vmi.Spec.Elasticsearch.Security.InternalUsers = []vmcontrollerv1.SecurityInternalUser{{Username: "kibanaro"}}
+	credentials["kibanaro"] = InternalUserCredentials{Password: "changeme", PasswordVersion: "1"}
+	assert.EqualError(t, <-o.ConfigureSecurity(vzlog.DefaultLogger(), vmi, credentials), "OpenSearch internal user kibanaro was not created by the VMI, and is not taken over")
+	assert.Empty(t, updated)
+
+	// security is not configured without a security spec
+	vmi.Spec.Elasticsearch.Security = nil
+	assert.NoError(t, <-o.ConfigureSecurity(vzlog.DefaultLogger(), vmi, credentials))
+}
+
+// TestSecurityPayloadsHaveNoNullLists Tests that empty lists are sent to the security plugin
+// GIVEN a VMI with a role, a role mapping and an internal user whose lists are not set
+// WHEN I call ConfigureSecurity
+// THEN the resources are created with empty lists rather than null
+func TestSecurityPayloadsHaveNoNullLists(t *testing.T) {
+	vmi := createISMVMI("1d", true)
+	vmi.Spec.Elasticsearch.Security = &vmcontrollerv1.OpenSearchSecurity{
+		Roles: []vmcontrollerv1.SecurityRole{
+			{Name: "empty"},
+			{Name: "reader", IndexPermissions: []vmcontrollerv1.SecurityIndexPermission{{AllowedActions: []string{"read"}}}},
+		},
+		RoleMappings:  []vmcontrollerv1.SecurityRoleMapping{{Role: "empty", Users: []string{"reader"}}},
+		InternalUsers: []vmcontrollerv1.SecurityInternalUser{{Username: "reader"}},
+	}
+	credentials := map[string]InternalUserCredentials{"reader": {Password: "changeme", PasswordVersion: "1"}}
+
+	payloads := map[string]map[string]interface{}{}
+	o := NewOSClient()
+	o.DoHTTP = func(req *http.Request) (*http.Response, error) {
+		body := "{}"
+		if req.Method == "PUT" {
+			payload := map[string]interface{}{}
+			assert.NoError(t, json.NewDecoder(req.Body).Decode(&payload))
+			payloads[strings.TrimPrefix(req.URL.Path, "/_plugins/_security/api/")] = payload
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil
+	}
+	assert.NoError(t, <-o.ConfigureSecurity(vzlog.DefaultLogger(), vmi, credentials))
+	assert.Len(t, payloads, 4)
+	assert.Equal(t, []interface{}{}, payloads["roles/empty"]["cluster_permissions"])
+	assert.Equal(t, []interface{}{}, payloads["roles/empty"]["index_permissions"])
+	assert.Equal(t, []interface{}{}, payloads["roles/reader"]["index_permissions"].([]interface{})[0].(map[string]interface{})["index_patterns"])
+	assert.Equal(t, []interface{}{}, payloads["rolesmapping/empty"]["backend_roles"])
+	assert.Equal(t, []interface{}{}, payloads["internalusers/reader"]["backend_roles"])
+}
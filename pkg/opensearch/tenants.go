@@ -0,0 +1,199 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	"sort"
+)
+
+type Tenant struct {
+	Description string `json:"description,omitempty"`
+	Reserved    bool   `json:"reserved,omitempty"`
+	Static      bool   `json:"static,omitempty"`
+}
+
+const (
+	// Descriptor to identify tenants, and the roles and role mappings granting access to them, as being managed by the VMI.
+	// It differs from vmiManagedSecurity, so tenant roles are not deleted as roles removed from the security spec.
+	vmiManagedTenant = "__vmi-managed-tenant__"
+	// Prefix of the names of the roles granting access to a tenant
+	tenantRolePrefix = "vmi_tenant_"
+	tenantReadWrite  = "kibana_all_write"
+	tenantReadOnly   = "kibana_all_read"
+)
+
+//ConfigureTenants creates or updates the OpenSearch Dashboards tenants of the VMI, and the roles and role mappings which
+// grant the members of the Keycloak groups of each tenant access to it. Tenants managed by the VMI, but no longer in the VMI,
+// are deleted, together with their roles and role mappings. Existing ones which were not created by the VMI are not taken over.
+// The returned channel should be read for exactly one response, which tells whether the tenants were configured.
+// The security plugin is only used while the VMI has tenants, or had them before.
+func (o *OSClient) ConfigureTenants(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) chan error {
+	ch := make(chan error)
+	tenants := append([]vmcontrollerv1.DashboardsTenant{}, vmi.Spec.Kibana.Tenants...)
+	managed := len(tenants) > 0 || len(vmi.Status.Kibana.Tenants) > 0
+	enabled := vmi.Spec.Elasticsearch.Enabled
+	opensearchEndpoint := resources.GetOpenSearchHTTPEndpoint(vmi)
+	// configuration is done asynchronously, as this does not need to be blocking
+	go func() {
+		if !enabled || !managed {
+			ch <- nil
+			return
+		}
+		if err := o.reconcileTenants(log, opensearchEndpoint, tenants); err != nil {
+			ch <- err
+			return
+		}
+		roles, roleMappings := getTenantRoles(tenants)
+		if err := o.reconcileTenantRoles(log, opensearchEndpoint, roles); err != nil {
+			ch <- err
+			return
+		}
+		ch <- o.reconcileTenantRoleMappings(log, opensearchEndpoint, roleMappings)
+	}()
+	return ch
+}
+
+func (o *OSClient) reconcileTenants(log vzlog.VerrazzanoLogger, opensearchEndpoint string, tenants []vmcontrollerv1.DashboardsTenant) error {
+	existing := map[string]Tenant{}
+	if err := o.getJSON(fmt.Sprintf("%s/%s/%s", opensearchEndpoint, securityAPI, securityTenants), &existing); err != nil {
+		return err
+	}
+	expected := map[string]bool{}
+	for _, tenant := range tenants {
+		expected[tenant.Name] = true
+		current, ok := existing[tenant.Name]
+		if ok && current.Description == vmiManagedTenant {
+			continue
+		}
+		if current.Reserved || current.Static {
+			return fmt.Errorf("OpenSearch Dashboards tenant %s is reserved, and cannot be updated", tenant.Name)
+		}
+		// the saved objects of a tenant belong to whoever created it, so it is never taken over
+		if ok {
+			return fmt.Errorf("OpenSearch Dashboards tenant %s was not created by the VMI, and is not taken over", tenant.Name)
+		}
+		log.Oncef("Creating OpenSearch Dashboards tenant %s", tenant.Name)
+		if err := o.putSecurityResource(opensearchEndpoint, securityTenants, tenant.Name, Tenant{Description: vmiManagedTenant}); err != nil {
+			return err
+		}
+	}
+	var pruned []string
+	for name, tenant := range existing {
+		if tenant.Description == vmiManagedTenant && !expected[name] {
+			pruned = append(pruned, name)
+		}
+	}
+	return o.deleteSecurityResources(opensearchEndpoint, securityTenants, pruned)
+}
+
+func (o *OSClient) reconcileTenantRoles(log vzlog.VerrazzanoLogger, opensearchEndpoint string, roles map[string]Role) error {
+	existing := map[string]Role{}
+	if err := o.getJSON(fmt.Sprintf("%s/%s/%s", opensearchEndpoint, securityAPI, securityRoles), &existing); err != nil {
+		return err
+	}
+	var names []string
+	for name := range roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		desired := roles[name]
+		current, ok := existing[name]
+		if ok && isSameRole(current, desired) {
+			continue
+		}
+		if current.Reserved || current.Static {
+			return fmt.Errorf("OpenSearch role %s is reserved, and cannot be updated", name)
+		}
+		if ok && current.Description != vmiManagedTenant {
+			return fmt.Errorf("OpenSearch role %s was not created by the VMI, and is not taken over", name)
+		}
+		if ok {
+			log.Oncef("Correcting OpenSearch role %s, which differs from the VMI tenants", name)
+		}
+		if err := o.putSecurityResource(opensearchEndpoint, securityRoles, name, desired); err != nil {
+			return err
+		}
+	}
+	var pruned []string
+	for name, role := range existing {
+		if _, ok := roles[name]; !ok && role.Description == vmiManagedTenant {
+			pruned = append(pruned, name)
+		}
+	}
+	return o.deleteSecurityResources(opensearchEndpoint, securityRoles, pruned)
+}
+
+func (o *OSClient) reconcileTenantRoleMappings(log vzlog.VerrazzanoLogger, opensearchEndpoint string, roleMappings map[string]RoleMapping) error {
+	existing := map[string]RoleMapping{}
+	if err := o.getJSON(fmt.Sprintf("%s/%s/%s", opensearchEndpoint, securityAPI, securityRoleMappings), &existing); err != nil {
+		return err
+	}
+	var names []string
+	for name := range roleMappings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		desired := roleMappings[name]
+		current, ok := existing[name]
+		if ok && current.Description == desired.Description && isSameStrings(current.BackendRoles, desired.BackendRoles) && isSameStrings(current.Users, desired.Users) {
+			continue
+		}
+		if current.Reserved {
+			return fmt.Errorf("OpenSearch role mapping %s is reserved, and cannot be updated", name)
+		}
+		if ok && current.Description != vmiManagedTenant {
+			return fmt.Errorf("OpenSearch role mapping %s was not created by the VMI, and is not taken over", name)
+		}
+		if ok {
+			log.Oncef("Correcting OpenSearch role mapping %s, which differs from the VMI tenants", name)
+		}
+		if err := o.putSecurityResource(opensearchEndpoint, securityRoleMappings, name, desired); err != nil {
+			return err
+		}
+	}
+	var pruned []string
+	for name, roleMapping := range existing {
+		if _, ok := roleMappings[name]; !ok && roleMapping.Description == vmiManagedTenant {
+			pruned = append(pruned, name)
+		}
+	}
+	return o.deleteSecurityResources(opensearchEndpoint, securityRoleMappings, pruned)
+}
+
+//getTenantRoles returns the roles granting access to the tenants, and the role mappings of the Keycloak groups to them.
+// Keycloak groups are the backend roles of OpenSearch users. A role is only created for groups which are given.
+func getTenantRoles(tenants []vmcontrollerv1.DashboardsTenant) (map[string]Role, map[string]RoleMapping) {
+	roles := map[string]Role{}
+	roleMappings := map[string]RoleMapping{}
+	add := func(tenant, suffix, action string, groups []string) {
+		if len(groups) == 0 {
+			return
+		}
+		name := tenantRolePrefix + tenant + suffix
+		roles[name] = Role{
+			Description:        vmiManagedTenant,
+			ClusterPermissions: []string{},
+			IndexPermissions:   []IndexPermission{},
+			TenantPermissions: []TenantPermission{
+				{TenantPatterns: []string{tenant}, AllowedActions: []string{action}},
+			},
+		}
+		roleMappings[name] = RoleMapping{
+			Description:  vmiManagedTenant,
+			BackendRoles: groups,
+			Users:        []string{},
+		}
+	}
+	for _, tenant := range tenants {
+		add(tenant.Name, "_rw", tenantReadWrite, tenant.Groups)
+		add(tenant.Name, "_ro", tenantReadOnly, tenant.ReadOnlyGroups)
+	}
+	return roles, roleMappings
+}
@@ -0,0 +1,157 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestConfigureTenants Tests the reconciliation of OpenSearch Dashboards tenants
+// GIVEN a VMI with tenants mapped to Keycloak groups
+// WHEN I call ConfigureTenants
+// THEN missing tenants, roles and role mappings are created, with empty rather than null lists,
+// AND removed tenants are deleted with their roles and role mappings
+func TestConfigureTenants(t *testing.T) {
+	const (
+		tenants = `{
+			"global_tenant": {"reserved": true, "description": "Global tenant"},
+			"bobs-books": {"description": "__vmi-managed-tenant__"},
+			"removed": {"description": "__vmi-managed-tenant__"}
+		}`
+		roles = `{
+			"vmi_tenant_bobs-books_rw": {"description": "__vmi-managed-tenant__", "cluster_permissions": [], "index_permissions": [],
+				"tenant_permissions": [{"tenant_patterns": ["bobs-books"], "allowed_actions": ["kibana_all_write"]}]},
+			"vmi_tenant_removed_rw": {"description": "__vmi-managed-tenant__", "cluster_permissions": [], "index_permissions": [],
+				"tenant_permissions": [{"tenant_patterns": ["removed"], "allowed_actions": ["kibana_all_write"]}]},
+			"reader": {"description": "__vmi-managed__", "cluster_permissions": [], "index_permissions": []}
+		}`
+		roleMappings = `{
+			"vmi_tenant_bobs-books_rw": {"description": "__vmi-managed-tenant__", "backend_roles": ["bobs-books-admins"], "users": []},
+			"vmi_tenant_removed_rw": {"description": "__vmi-managed-tenant__", "backend_roles": ["removed"], "users": []}
+		}`
+	)
+	vmi := createISMVMI("1d", true)
+	vmi.Spec.Kibana.Tenants = []vmcontrollerv1.DashboardsTenant{
+		{Name: "bobs-books", Groups: []string{"bobs-books-admins"}, ReadOnlyGroups: []string{"bobs-books-viewers"}},
+		{Name: "todo-list", Groups: []string{"todo-list-admins"}},
+	}
+
+	var updated, deleted []string
+	var readOnly Role
+	payloads := map[string]map[string]interface{}{}
+	o := NewOSClient()
+	o.DoHTTP = func(req *http.Request) (*http.Response, error) {
+		body := ""
+		switch {
+		case req.Method == "GET" && req.URL.Path == "/_plugins/_security/api/tenants":
+			body = tenants
+		case req.Method == "GET" && req.URL.Path == "/_plugins/_security/api/roles":
+			body = roles
+		case req.Method == "GET" && req.URL.Path == "/_plugins/_security/api/rolesmapping":
+			body = roleMappings
+		case req.Method == "PUT":
+			updated = append(updated, strings.TrimPrefix(req.URL.Path, "/_plugins/_security/api/"))
+			payload, _ := ioutil.ReadAll(req.Body)
+			if req.URL.Path == "/_plugins/_security/api/roles/vmi_tenant_bobs-books_ro" {
+				assert.NoError(t, json.Unmarshal(payload, &readOnly))
+			}
+			fields := map[string]interface{}{}
+			assert.NoError(t, json.Unmarshal(payload, &fields))
+			payloads[strings.TrimPrefix(req.URL.Path, "/_plugins/_security/api/")] = fields
+		case req.Method == "DELETE":
+			deleted = append(deleted, strings.TrimPrefix(req.URL.Path, "/_plugins/_security/api/"))
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil
+	}
+	assert.NoError(t, <-o.ConfigureTenants(vzlog.DefaultLogger(), vmi))
+	assert.Equal(t, []string{
+		"tenants/todo-list",
+		"roles/vmi_tenant_bobs-books_ro",
+		"roles/vmi_tenant_todo-list_rw",
+		"rolesmapping/vmi_tenant_bobs-books_ro",
+		"rolesmapping/vmi_tenant_todo-list_rw",
+	}, updated)
+	assert.Equal(t, []string{"tenants/removed", "roles/vmi_tenant_removed_rw", "rolesmapping/vmi_tenant_removed_rw"}, deleted)
+	assert.Equal(t, []TenantPermission{{TenantPatterns: []string{"bobs-books"}, AllowedActions: []string{tenantReadOnly}}}, readOnly.TenantPermissions)
+	assert.Equal(t, []interface{}{}, payloads["roles/vmi_tenant_bobs-books_ro"]["cluster_permissions"])
+	assert.Equal(t, []interface{}{}, payloads["roles/vmi_tenant_bobs-books_ro"]["index_permissions"])
+	assert.Equal(t, []interface{}{}, payloads["rolesmapping/vmi_tenant_bobs-books_ro"]["users"])
+
+	// reserved tenants are not changed
+	vmi.Spec.Kibana.Tenants = []vmcontrollerv1.DashboardsTenant{{Name: "global_tenant"}}
+	assert.Error(t, <-o.ConfigureTenants(vzlog.DefaultLogger(), vmi))
+
+	// the security plugin is not used without tenants
+	o.DoHTTP = func(req *http.Request) (*http.Response, error) {
+		t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+		return nil, nil
+	}
+	vmi.Spec.Kibana.Tenants = nil
+	assert.NoError(t, <-o.ConfigureTenants(vzlog.DefaultLogger(), vmi))
+}
+
+// TestConfigureTenantsNotTakenOver Tests that tenants are only managed if the VMI created them
+// GIVEN a VMI with tenants, whose tenant, role or role mapping already exists, but was not created by the VMI
+// WHEN I call ConfigureTenants
+// THEN an error is returned, and nothing is updated
+func TestConfigureTenantsNotTakenOver(t *testing.T) {
+	const (
+		tenants = `{
+			"team": {"description": "Team tenant"},
+			"shared": {"description": "__vmi-managed-tenant__"}
+		}`
+		roles = `{
+			"vmi_tenant_shared_rw": {"description": "__vmi-managed-tenant__", "cluster_permissions": [], "index_permissions": [],
+				"tenant_permissions": [{"tenant_patterns": ["shared"], "allowed_actions": ["kibana_all_write"]}]},
+			"vmi_tenant_shared_ro": {"description": "Shared tenant readers", "cluster_permissions": [], "index_permissions": [],
+				"tenant_permissions": [{"tenant_patterns": ["shared"], "allowed_actions": ["kibana_all_read"]}]}
+		}`
+		roleMappings = `{
+			"vmi_tenant_shared_rw": {"description": "Shared tenant writers", "backend_roles": ["shared-admins"], "users": []}
+		}`
+	)
+	vmi := createISMVMI("1d", true)
+
+	var updated []string
+	o := NewOSClient()
+	o.DoHTTP = func(req *http.Request) (*http.Response, error) {
+		body := ""
+		switch {
+		case req.Method == "GET" && req.URL.Path == "/_plugins/_security/api/tenants":
+			body = tenants
+		case req.Method == "GET" && req.URL.Path == "/_plugins/_security/api/roles":
+			body = roles
+		case req.Method == "GET" && req.URL.Path == "/_plugins/_security/api/rolesmapping":
+			body = roleMappings
+		case req.Method == "PUT" || req.Method == "DELETE":
+			updated = append(updated, req.Method+" "+strings.TrimPrefix(req.URL.Path, "/_plugins/_security/api/"))
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil
+	}
+
+	vmi.Spec.Kibana.Tenants = []vmcontrollerv1.DashboardsTenant{{Name: "team", Groups: []string{"team-admins"}}}
+	assert.EqualError(t, <-o.ConfigureTenants(vzlog.DefaultLogger(), vmi),
+		"OpenSearch Dashboards tenant team was not created by the VMI, and is not taken over")
+	vmi.Spec.Kibana.Tenants = []vmcontrollerv1.DashboardsTenant{{Name: "shared", ReadOnlyGroups: []string{"shared-viewers"}}}
+	assert.EqualError(t, <-o.ConfigureTenants(vzlog.DefaultLogger(), vmi),
+		"OpenSearch role vmi_tenant_shared_ro was not created by the VMI, and is not taken over")
+	vmi.Spec.Kibana.Tenants = []vmcontrollerv1.DashboardsTenant{{Name: "shared", Groups: []string{"shared-admins"}}}
+	assert.EqualError(t, <-o.ConfigureTenants(vzlog.DefaultLogger(), vmi),
+		"OpenSearch role mapping vmi_tenant_shared_rw was not created by the VMI, and is not taken over")
+	assert.Empty(t, updated)
+}
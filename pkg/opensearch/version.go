@@ -0,0 +1,110 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed OpenSearch version
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+const (
+	// lastElasticsearchMajor is the Elasticsearch major version OpenSearch 1.x was forked from
+	lastElasticsearchMajor = 7
+	// lastElasticsearchMinor is the Elasticsearch minor version OpenSearch 1.x was forked from
+	lastElasticsearchMinor = 10
+)
+
+// minimumUpgradeVersions is the minimum version a cluster must run before upgrading to a major version
+var minimumUpgradeVersions = map[int]Version{
+	2: {Major: 1, Minor: 3},
+	3: {Major: 2, Minor: 19},
+}
+
+//GetNodes returns the nodes in the cluster, with the version and roles of each node
+func (o *OSClient) GetNodes(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) ([]Node, error) {
+	return o.getOpenSearchNodes(vmi)
+}
+
+//IsMasterNode returns true if the node is master eligible
+func IsMasterNode(node Node) bool {
+	return resources.SliceContains(node.Roles, "master") || resources.SliceContains(node.Roles, "cluster_manager")
+}
+
+//ParseVersion parses a version string of the form major.minor.patch, ignoring any qualifier such as -SNAPSHOT
+func ParseVersion(version string) (Version, error) {
+	parts := strings.SplitN(strings.SplitN(version, "-", 2)[0], ".", 3)
+	if len(parts) < 2 {
+		return Version{}, fmt.Errorf("invalid OpenSearch version %q", version)
+	}
+	var numbers [3]int
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid OpenSearch version %q", version)
+		}
+		numbers[i] = number
+	}
+	return Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+//Compare returns -1, 0 or 1 when v is lower than, equal to or greater than other
+func (v Version) Compare(other Version) int {
+	for _, diff := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if diff < 0 {
+			return -1
+		}
+		if diff > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+func (v Version) isElasticsearch() bool {
+	return v.Major == lastElasticsearchMajor && v.Minor == lastElasticsearchMinor
+}
+
+//ValidateUpgrade returns an error if OpenSearch does not support a rolling upgrade between the versions
+// - downgrades are not supported
+// - Elasticsearch 7.10 may be upgraded to OpenSearch 1.x
+// - a major version may only be upgraded to the next major version, from its minimum supported minor version
+func ValidateUpgrade(fromVersion, toVersion string) error {
+	from, err := ParseVersion(fromVersion)
+	if err != nil {
+		return err
+	}
+	to, err := ParseVersion(toVersion)
+	if err != nil {
+		return err
+	}
+	if from.isElasticsearch() {
+		if to.isElasticsearch() || to.Major == 1 {
+			return nil
+		}
+		return fmt.Errorf("upgrading from Elasticsearch %s to OpenSearch %s is not supported, upgrade to OpenSearch 1.x first", fromVersion, toVersion)
+	}
+	if from.Compare(to) > 0 {
+		return fmt.Errorf("downgrading OpenSearch from %s to %s is not supported", fromVersion, toVersion)
+	}
+	if to.Major == from.Major {
+		return nil
+	}
+	if to.Major > from.Major+1 {
+		return fmt.Errorf("upgrading OpenSearch from %s to %s is not supported, upgrade to %d.x first", fromVersion, toVersion, from.Major+1)
+	}
+	if minimum, ok := minimumUpgradeVersions[to.Major]; ok && from.Compare(minimum) < 0 {
+		return fmt.Errorf("upgrading OpenSearch from %s to %s is not supported, upgrade to %d.%d first", fromVersion, toVersion, minimum.Major, minimum.Minor)
+	}
+	return nil
+}
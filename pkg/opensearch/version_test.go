@@ -0,0 +1,48 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	v, err := ParseVersion("1.2.4")
+	assert.NoError(t, err)
+	assert.Equal(t, Version{Major: 1, Minor: 2, Patch: 4}, v)
+	v, err = ParseVersion("2.3.0-SNAPSHOT")
+	assert.NoError(t, err)
+	assert.Equal(t, Version{Major: 2, Minor: 3}, v)
+	_, err = ParseVersion("latest")
+	assert.Error(t, err)
+	assert.Equal(t, -1, Version{Major: 1, Minor: 2, Patch: 4}.Compare(Version{Major: 1, Minor: 3}))
+	assert.Equal(t, 0, Version{Major: 1, Minor: 3}.Compare(Version{Major: 1, Minor: 3}))
+	assert.Equal(t, 1, Version{Major: 2}.Compare(Version{Major: 1, Minor: 3, Patch: 6}))
+}
+
+func TestValidateUpgrade(t *testing.T) {
+	var tests = []struct {
+		from    string
+		to      string
+		isError bool
+	}{
+		{"1.2.4", "1.2.4", false},
+		{"1.2.4", "1.3.6", false},
+		{"7.10.2", "1.2.4", false},
+		{"1.3.6", "2.3.0", false},
+		{"2.19.1", "3.0.0", false},
+		{"1.3.6", "1.2.4", true},
+		{"1.2.4", "2.3.0", true},
+		{"1.3.6", "3.0.0", true},
+		{"7.10.2", "2.3.0", true},
+		{"1.2.4", "latest", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			assert.Equal(t, tt.isError, ValidateUpgrade(tt.from, tt.to) != nil)
+		})
+	}
+}
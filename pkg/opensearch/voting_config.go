@@ -0,0 +1,59 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+type CatMaster struct {
+	Node string `json:"node"`
+}
+
+//AddVotingConfigExclusion removes a master eligible node from the voting configuration.
+// If the node is the elected master, it abdicates and a new master is elected.
+func (o *OSClient) AddVotingConfigExclusion(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, nodeName string) error {
+	exclusionsURL := fmt.Sprintf("%s/_cluster/voting_config_exclusions?node_names=%s", resources.GetOpenSearchHTTPEndpoint(vmi), url.QueryEscape(nodeName))
+	return o.doVotingConfigExclusions("POST", exclusionsURL)
+}
+
+//ClearVotingConfigExclusions removes all voting configuration exclusions, without waiting for the excluded nodes to leave the cluster
+func (o *OSClient) ClearVotingConfigExclusions(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	exclusionsURL := resources.GetOpenSearchHTTPEndpoint(vmi) + "/_cluster/voting_config_exclusions?wait_for_removal=false"
+	return o.doVotingConfigExclusions("DELETE", exclusionsURL)
+}
+
+//GetElectedMaster returns the name of the elected master node, or an empty string if there is no elected master
+func (o *OSClient) GetElectedMaster(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) (string, error) {
+	var masters []CatMaster
+	if err := o.getJSON(resources.GetOpenSearchHTTPEndpoint(vmi)+"/_cat/master?format=json&h=node", &masters); err != nil {
+		return "", err
+	}
+	if len(masters) < 1 {
+		return "", nil
+	}
+	return masters[0].Node, nil
+}
+
+func (o *OSClient) doVotingConfigExclusions(method, exclusionsURL string) error {
+	req, err := http.NewRequest(method, exclusionsURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		responseBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("got status code %d when updating voting config exclusions: %s", resp.StatusCode, string(responseBody))
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestVotingConfigExclusions(t *testing.T) {
+	var requests []string
+	o := NewOSClient()
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		requests = append(requests, request.Method+" "+request.URL.RequestURI())
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	}
+	assert.NoError(t, o.AddVotingConfigExclusion(testDrainVMI(), "master-2"))
+	assert.NoError(t, o.ClearVotingConfigExclusions(testDrainVMI()))
+	assert.Equal(t, []string{
+		"POST /_cluster/voting_config_exclusions?node_names=master-2",
+		"DELETE /_cluster/voting_config_exclusions?wait_for_removal=false",
+	}, requests)
+
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	}
+	assert.Error(t, o.AddVotingConfigExclusion(testDrainVMI(), "master-2"))
+}
+
+func TestGetElectedMaster(t *testing.T) {
+	var tests = []struct {
+		name   string
+		body   string
+		master string
+	}{
+		{"returns the elected master", `[{"node": "master-0"}]`, "master-0"},
+		{"no master elected", `[]`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := NewOSClient()
+			o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(tt.body)),
+				}, nil
+			}
+			master, err := o.GetElectedMaster(testDrainVMI())
+			assert.NoError(t, err)
+			assert.Equal(t, tt.master, master)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package dashboards
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	"hash/fnv"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+type (
+	IndexPatternAttributes struct {
+		Title         string `json:"title"`
+		TimeFieldName string `json:"timeFieldName,omitempty"`
+	}
+
+	IndexPattern struct {
+		Attributes IndexPatternAttributes `json:"attributes"`
+	}
+)
+
+const (
+	crossClusterPatternIDPrefix = "vmi-cross-cluster-"
+	timestampField              = "@timestamp"
+)
+
+// ConfigureCrossClusterIndexPatterns creates an index pattern in OpenSearch Dashboards for each cross-cluster index
+// pattern of the VMI, which searches the local cluster and all remote clusters. Index patterns previously created by
+// the operator, but no longer expected, are deleted.
+// The returned channel should be read for exactly one response, which tells whether the index patterns were configured.
+func (od *OSDashboardsClient) ConfigureCrossClusterIndexPatterns(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, previous []string) chan error {
+	ch := make(chan error)
+	patterns := vmi.Spec.Elasticsearch.CrossClusterIndexPatterns
+	var remoteClusters []string
+	for _, remote := range vmi.Spec.Elasticsearch.RemoteClusters {
+		remoteClusters = append(remoteClusters, remote.Name)
+	}
+	enabled := vmi.Spec.Kibana.Enabled
+	dashboardsEndpoint := resources.GetOpenSearchDashboardsHTTPEndpoint(vmi)
+	// configuration is done asynchronously, as this does not need to be blocking
+	go func() {
+		if !enabled || (len(patterns) == 0 && len(previous) == 0) {
+			ch <- nil
+			return
+		}
+		ch <- od.configureCrossClusterIndexPatterns(log, dashboardsEndpoint, patterns, previous, remoteClusters)
+	}()
+	return ch
+}
+
+func (od *OSDashboardsClient) configureCrossClusterIndexPatterns(log vzlog.VerrazzanoLogger, dashboardsEndpoint string, patterns, previous, remoteClusters []string) error {
+	expected := map[string]bool{}
+	for _, pattern := range patterns {
+		expected[pattern] = true
+		title := GetCrossClusterIndexPatternTitle(pattern, remoteClusters)
+		if err := od.putIndexPattern(log, dashboardsEndpoint, getCrossClusterIndexPatternID(pattern), title); err != nil {
+			return fmt.Errorf("failed to create cross-cluster index pattern %s: %v", pattern, err)
+		}
+	}
+	for _, pattern := range previous {
+		if expected[pattern] {
+			continue
+		}
+		log.Infof("Deleting cross-cluster index pattern %s from OpenSearch Dashboards", pattern)
+		if err := od.deleteIndexPattern(dashboardsEndpoint, getCrossClusterIndexPatternID(pattern)); err != nil {
+			return fmt.Errorf("failed to delete cross-cluster index pattern %s: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// GetCrossClusterIndexPatternTitle returns an index pattern which matches the indices of the local cluster, and of each
+// remote cluster, e.g., verrazzano-system,managed1:verrazzano-system
+func GetCrossClusterIndexPatternTitle(pattern string, remoteClusters []string) string {
+	patterns := []string{pattern}
+	for _, remote := range remoteClusters {
+		patterns = append(patterns, remote+":"+pattern)
+	}
+	return strings.Join(patterns, ",")
+}
+
+// putIndexPattern creates the index pattern, or overwrites it when its title has changed
+func (od *OSDashboardsClient) putIndexPattern(log vzlog.VerrazzanoLogger, dashboardsEndpoint, id, title string) error {
+	url := fmt.Sprintf("%s/api/saved_objects/index-pattern/%s", dashboardsEndpoint, id)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := od.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		existing := &IndexPattern{}
+		if err := json.NewDecoder(resp.Body).Decode(existing); err != nil {
+			return err
+		}
+		if existing.Attributes.Title == title {
+			return nil
+		}
+	case http.StatusNotFound:
+	default:
+		return fmt.Errorf("got status code %d when getting index pattern %s", resp.StatusCode, id)
+	}
+
+	payload, err := json.Marshal(&IndexPattern{Attributes: IndexPatternAttributes{Title: title, TimeFieldName: timestampField}})
+	if err != nil {
+		return err
+	}
+	log.Infof("Creating index pattern %s in OpenSearch Dashboards", title)
+	req, err = http.NewRequest("POST", url+"?overwrite=true", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("osd-xsrf", "true")
+	resp, err = od.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		responseBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("got status code %d when creating index pattern %s: %s", resp.StatusCode, id, string(responseBody))
+	}
+	return nil
+}
+
+// deleteIndexPattern deletes the index pattern, if it exists
+func (od *OSDashboardsClient) deleteIndexPattern(dashboardsEndpoint, id string) error {
+	url := fmt.Sprintf("%s/api/saved_objects/index-pattern/%s", dashboardsEndpoint, id)
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("osd-xsrf", "true")
+	resp, err := od.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("got status code %d when deleting index pattern %s", resp.StatusCode, id)
+	}
+	return nil
+}
+
+// getCrossClusterIndexPatternID returns a stable saved object ID for the index pattern, as patterns may hold
+// characters which are not valid in IDs
+func getCrossClusterIndexPatternID(pattern string) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(pattern))
+	return fmt.Sprintf("%s%x", crossClusterPatternIDPrefix, hash.Sum32())
+}
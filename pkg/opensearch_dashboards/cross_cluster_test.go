@@ -0,0 +1,67 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package dashboards
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestConfigureCrossClusterIndexPatterns Tests the cross-cluster index patterns created in OpenSearch Dashboards
+// GIVEN a VMI with remote clusters and cross-cluster index patterns
+// WHEN I call ConfigureCrossClusterIndexPatterns
+// THEN missing or changed index patterns are created, and removed index patterns are deleted
+func TestConfigureCrossClusterIndexPatterns(t *testing.T) {
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{}
+	vmi.Spec.Kibana.Enabled = true
+	vmi.Spec.Elasticsearch.RemoteClusters = []vmcontrollerv1.RemoteCluster{{Name: "managed1"}, {Name: "managed2"}}
+	vmi.Spec.Elasticsearch.CrossClusterIndexPatterns = []string{"verrazzano-system", "verrazzano-application-*"}
+	systemID := getCrossClusterIndexPatternID("verrazzano-system")
+	applicationID := getCrossClusterIndexPatternID("verrazzano-application-*")
+
+	var requests []string
+	var created IndexPattern
+	osd := NewOSDashboardsClient()
+	osd.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		id := request.URL.Path[strings.LastIndex(request.URL.Path, "/")+1:]
+		requests = append(requests, request.Method+" "+id)
+		statusCode := http.StatusOK
+		body := ""
+		switch {
+		case request.Method == "GET" && id == systemID:
+			body = `{"attributes": {"title": "verrazzano-system,managed1:verrazzano-system,managed2:verrazzano-system"}}`
+		case request.Method == "GET":
+			statusCode = http.StatusNotFound
+		case request.Method == "POST":
+			assert.NoError(t, json.NewDecoder(request.Body).Decode(&created))
+		}
+		return &http.Response{
+			StatusCode: statusCode,
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil
+	}
+	err := <-osd.ConfigureCrossClusterIndexPatterns(vzlog.DefaultLogger(), vmi, []string{"verrazzano-system", "verrazzano-*"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"GET " + systemID,
+		"GET " + applicationID,
+		"POST " + applicationID,
+		"DELETE " + getCrossClusterIndexPatternID("verrazzano-*"),
+	}, requests)
+	assert.Equal(t, "verrazzano-application-*,managed1:verrazzano-application-*,managed2:verrazzano-application-*", created.Attributes.Title)
+	assert.Equal(t, "@timestamp", created.Attributes.TimeFieldName)
+
+	// nothing is configured without cross-cluster index patterns
+	osd.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		t.Errorf("unexpected request %s %s", request.Method, request.URL.Path)
+		return nil, nil
+	}
+	assert.NoError(t, <-osd.ConfigureCrossClusterIndexPatterns(vzlog.DefaultLogger(), &vmcontrollerv1.VerrazzanoMonitoringInstance{}, nil))
+}
@@ -0,0 +1,146 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package dashboards
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	"io/ioutil"
+	"net/http"
+)
+
+type (
+	// DefaultIndexPattern is an index pattern the operator creates in OpenSearch Dashboards
+	DefaultIndexPattern struct {
+		ID    string
+		Title string
+	}
+
+	Settings struct {
+		Settings map[string]struct {
+			UserValue interface{} `json:"userValue"`
+		} `json:"settings"`
+	}
+)
+
+const (
+	// the IDs of the default index patterns are fixed, so a pattern is updated when its title changes
+	systemIndexPatternID      = "vmi-default-system"
+	applicationIndexPatternID = "vmi-default-application"
+	applicationIndexPattern   = "verrazzano-application-*"
+	defaultIndexSetting       = "defaultIndex"
+)
+
+// GetDefaultIndexPatterns returns the index patterns of the Verrazzano system and application data streams
+func GetDefaultIndexPatterns() []DefaultIndexPattern {
+	return []DefaultIndexPattern{
+		{ID: systemIndexPatternID, Title: config.DataStreamName()},
+		{ID: applicationIndexPatternID, Title: applicationIndexPattern},
+	}
+}
+
+// ConfigureDefaultIndexPatterns creates the default index patterns in OpenSearch Dashboards, unless an index pattern with
+// the same title exists, or updates their titles when the data stream names have changed, and sets the default index
+// pattern of OpenSearch Dashboards.
+// The returned channel should be read for exactly one response, which tells whether the index patterns were configured.
+func (od *OSDashboardsClient) ConfigureDefaultIndexPatterns(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) chan error {
+	ch := make(chan error)
+	enabled := vmi.Spec.Kibana.Enabled
+	defaultPattern := vmi.Spec.Kibana.DefaultIndexPattern
+	if defaultPattern == "" {
+		defaultPattern = config.DataStreamName()
+	}
+	dashboardsEndpoint := resources.GetOpenSearchDashboardsHTTPEndpoint(vmi)
+	// configuration is done asynchronously, as this does not need to be blocking
+	go func() {
+		if !enabled {
+			ch <- nil
+			return
+		}
+		ch <- od.configureDefaultIndexPatterns(log, dashboardsEndpoint, defaultPattern)
+	}()
+	return ch
+}
+
+func (od *OSDashboardsClient) configureDefaultIndexPatterns(log vzlog.VerrazzanoLogger, dashboardsEndpoint, defaultPattern string) error {
+	// index patterns are looked up by their title, so a pattern created by a user or before the IDs were fixed is not duplicated
+	patterns, err := od.getPatterns(dashboardsEndpoint, 100)
+	if err != nil {
+		return err
+	}
+	patternIDs := map[string]string{}
+	for _, pattern := range patterns {
+		if _, ok := patternIDs[pattern.Title]; !ok {
+			patternIDs[pattern.Title] = pattern.ID
+		}
+	}
+	for _, pattern := range GetDefaultIndexPatterns() {
+		if _, ok := patternIDs[pattern.Title]; ok {
+			continue
+		}
+		if err := od.putIndexPattern(log, dashboardsEndpoint, pattern.ID, pattern.Title); err != nil {
+			return fmt.Errorf("failed to create index pattern %s: %v", pattern.Title, err)
+		}
+		patternIDs[pattern.Title] = pattern.ID
+	}
+	// the default index pattern may be any index pattern
+	defaultPatternID, ok := patternIDs[defaultPattern]
+	if !ok {
+		return fmt.Errorf("the default index pattern %s does not exist", defaultPattern)
+	}
+	return od.setDefaultIndexPattern(log, dashboardsEndpoint, defaultPattern, defaultPatternID)
+}
+
+// setDefaultIndexPattern sets the default index pattern advanced setting, unless it is already set
+func (od *OSDashboardsClient) setDefaultIndexPattern(log vzlog.VerrazzanoLogger, dashboardsEndpoint, title, id string) error {
+	url := dashboardsEndpoint + "/api/opensearch-dashboards/settings"
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := od.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("got status code %d when getting the OpenSearch Dashboards settings", resp.StatusCode)
+	}
+	settings := &Settings{}
+	if err := json.NewDecoder(resp.Body).Decode(settings); err != nil {
+		return err
+	}
+	if settings.Settings[defaultIndexSetting].UserValue == id {
+		return nil
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"changes": map[string]string{defaultIndexSetting: id},
+	})
+	if err != nil {
+		return err
+	}
+	log.Infof("Setting the default index pattern of OpenSearch Dashboards to %s", title)
+	req, err = http.NewRequest("POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("osd-xsrf", "true")
+	resp, err = od.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		responseBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("got status code %d when setting the default index pattern: %s", resp.StatusCode, string(responseBody))
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package dashboards
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestConfigureDefaultIndexPatterns Tests the default index patterns created in OpenSearch Dashboards
+// GIVEN OpenSearch Dashboards with or without the default index patterns
+// WHEN I call ConfigureDefaultIndexPatterns
+// THEN missing or renamed default index patterns are created, unless an index pattern of the same title exists,
+// AND the default index pattern is set
+func TestConfigureDefaultIndexPatterns(t *testing.T) {
+	const (
+		systemPattern      = `{"attributes": {"title": "verrazzano-system"}}`
+		applicationPattern = `{"attributes": {"title": "verrazzano-application-*"}}`
+		defaultSet         = `{"settings": {"defaultIndex": {"userValue": "vmi-default-system"}}}`
+		noPatterns         = `{"total": 0, "saved_objects": []}`
+		configured         = `{"total": 3, "saved_objects": [{"id": "vmi-default-system", "attributes": {"title": "verrazzano-system"}}, {"id": "vmi-default-application", "attributes": {"title": "verrazzano-application-*"}}, {"id": "logs", "attributes": {"title": "logs-*"}}]}`
+		userCreated        = `{"total": 2, "saved_objects": [{"id": "user-system", "attributes": {"title": "verrazzano-system"}}, {"id": "vmi-default-application", "attributes": {"title": "verrazzano-application-*"}}]}`
+	)
+	var tests = []struct {
+		name           string
+		dataStreamName string
+		defaultPattern string
+		patterns       string
+		system         string
+		application    string
+		settings       string
+		requests       []string
+		defaultIndex   string
+		isError        bool
+	}{
+		{
+			"creates the index patterns on a fresh install",
+			"",
+			"",
+			noPatterns,
+			"",
+			"",
+			`{"settings": {}}`,
+			[]string{
+				"GET /api/saved_objects/_find",
+				"GET /api/saved_objects/index-pattern/vmi-default-system",
+				"POST /api/saved_objects/index-pattern/vmi-default-system",
+				"GET /api/saved_objects/index-pattern/vmi-default-application",
+				"POST /api/saved_objects/index-pattern/vmi-default-application",
+				"GET /api/opensearch-dashboards/settings",
+				"POST /api/opensearch-dashboards/settings",
+			},
+			"vmi-default-system",
+			false,
+		},
+		{
+			"nothing changes once configured",
+			"",
+			"",
+			configured,
+			systemPattern,
+			applicationPattern,
+			defaultSet,
+			[]string{
+				"GET /api/saved_objects/_find",
+				"GET /api/opensearch-dashboards/settings",
+			},
+			"",
+			false,
+		},
+		{
+			"the system index pattern follows the data stream name",
+			"verrazzano-logs",
+			"",
+			configured,
+			systemPattern,
+			applicationPattern,
+			defaultSet,
+			[]string{
+				"GET /api/saved_objects/_find",
+				"GET /api/saved_objects/index-pattern/vmi-default-system",
+				"POST /api/saved_objects/index-pattern/vmi-default-system",
+				"GET /api/opensearch-dashboards/settings",
+			},
+			"",
+			false,
+		},
+		{
+			"an index pattern with the same title is not duplicated",
+			"",
+			"",
+			userCreated,
+			"",
+			applicationPattern,
+			`{"settings": {}}`,
+			[]string{
+				"GET /api/saved_objects/_find",
+				"GET /api/opensearch-dashboards/settings",
+				"POST /api/opensearch-dashboards/settings",
+			},
+			"user-system",
+			false,
+		},
+		{
+			"the default index pattern may be any index pattern",
+			"",
+			"logs-*",
+			configured,
+			systemPattern,
+			applicationPattern,
+			defaultSet,
+			[]string{
+				"GET /api/saved_objects/_find",
+				"GET /api/opensearch-dashboards/settings",
+				"POST /api/opensearch-dashboards/settings",
+			},
+			"logs",
+			false,
+		},
+		{
+			"the default index pattern must exist",
+			"",
+			"missing-*",
+			configured,
+			systemPattern,
+			applicationPattern,
+			defaultSet,
+			[]string{
+				"GET /api/saved_objects/_find",
+			},
+			"",
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.dataStreamName != "" {
+				os.Setenv("VERRAZZANO_DATA_STREAM_NAME", tt.dataStreamName)
+				defer os.Unsetenv("VERRAZZANO_DATA_STREAM_NAME")
+			}
+			vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{}
+			vmi.Spec.Kibana.Enabled = true
+			vmi.Spec.Kibana.DefaultIndexPattern = tt.defaultPattern
+
+			var requests []string
+			defaultIndex := ""
+			osd := NewOSDashboardsClient()
+			osd.DoHTTP = func(request *http.Request) (*http.Response, error) {
+				requests = append(requests, request.Method+" "+request.URL.Path)
+				statusCode := http.StatusOK
+				body := ""
+				switch {
+				case request.Method == "GET" && strings.HasSuffix(request.URL.Path, "/vmi-default-system"):
+					body = tt.system
+				case request.Method == "GET" && strings.HasSuffix(request.URL.Path, "/vmi-default-application"):
+					body = tt.application
+				case request.Method == "GET" && strings.HasSuffix(request.URL.Path, "/_find"):
+					body = tt.patterns
+				case request.Method == "GET":
+					body = tt.settings
+				case request.Method == "POST" && strings.HasSuffix(request.URL.Path, "/settings"):
+					changes := struct {
+						Changes map[string]string `json:"changes"`
+					}{}
+					assert.NoError(t, json.NewDecoder(request.Body).Decode(&changes))
+					defaultIndex = changes.Changes["defaultIndex"]
+				}
+				if body == "" && request.Method == "GET" {
+					statusCode = http.StatusNotFound
+				}
+				return &http.Response{
+					StatusCode: statusCode,
+					Body:       io.NopCloser(strings.NewReader(body)),
+				}, nil
+			}
+			err := <-osd.ConfigureDefaultIndexPatterns(vzlog.DefaultLogger(), vmi)
+			if tt.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.requests, requests)
+			assert.Equal(t, tt.defaultIndex, defaultIndex)
+		})
+	}
+
+	// nothing is configured when OpenSearch Dashboards is disabled
+	osd := NewOSDashboardsClient()
+	osd.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		t.Errorf("unexpected request %s %s", request.Method, request.URL.Path)
+		return nil, nil
+	}
+	assert.NoError(t, <-osd.ConfigureDefaultIndexPatterns(vzlog.DefaultLogger(), &vmcontrollerv1.VerrazzanoMonitoringInstance{}))
+}
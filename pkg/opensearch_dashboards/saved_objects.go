@@ -0,0 +1,291 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package dashboards
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	"hash/fnv"
+	"io/ioutil"
+	corev1 "k8s.io/api/core/v1"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+type (
+	// ProvisionedObject is an OpenSearch Dashboards saved object held by a ConfigMap
+	ProvisionedObject struct {
+		Type string
+		ID   string
+		// ConfigMap and key holding the object, as <name>/<key>
+		Source string
+		// Hash of the object, which changes whenever the object does
+		Hash string
+		// The object as a single NDJSON line
+		Data []byte
+	}
+
+	ImportResponse struct {
+		Success      bool          `json:"success"`
+		SuccessCount int           `json:"successCount"`
+		Errors       []ImportError `json:"errors,omitempty"`
+	}
+
+	ImportError struct {
+		Type  string `json:"type"`
+		ID    string `json:"id"`
+		Error struct {
+			Type string `json:"type"`
+		} `json:"error"`
+	}
+
+	savedObjectKey struct {
+		Type string
+		ID   string
+	}
+)
+
+// savedObjectsFile is the name of the NDJSON file in import requests
+const savedObjectsFile = "saved-objects.ndjson"
+
+// GetSavedObjects returns the saved objects of the ConfigMaps, which hold NDJSON saved object exports. ConfigMaps are
+// read in name order, and their keys in key order. An object held by more than one ConfigMap entry is only taken from
+// the first one.
+func GetSavedObjects(log vzlog.VerrazzanoLogger, configMaps []*corev1.ConfigMap) ([]ProvisionedObject, error) {
+	sorted := append([]*corev1.ConfigMap{}, configMaps...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	var objects []ProvisionedObject
+	sources := map[savedObjectKey]string{}
+	for _, configMap := range sorted {
+		var keys []string
+		for key := range configMap.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			source := configMap.Name + "/" + key
+			parsed, err := parseSavedObjects(source, configMap.Data[key])
+			if err != nil {
+				return nil, err
+			}
+			for _, object := range parsed {
+				objectKey := savedObjectKey{Type: object.Type, ID: object.ID}
+				if existing, ok := sources[objectKey]; ok {
+					log.Errorf("Ignoring %s %s of %s, which is already provisioned by %s", object.Type, object.ID, source, existing)
+					continue
+				}
+				sources[objectKey] = source
+				objects = append(objects, object)
+			}
+		}
+	}
+	return objects, nil
+}
+
+// parseSavedObjects parses the saved objects of an NDJSON export. The export summary, which has no type, is skipped.
+func parseSavedObjects(source, ndjson string) ([]ProvisionedObject, error) {
+	var objects []ProvisionedObject
+	for i, line := range strings.Split(ndjson, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		object := struct {
+			Type          string `json:"type"`
+			ID            string `json:"id"`
+			ExportedCount *int   `json:"exportedCount"`
+		}{}
+		if err := json.Unmarshal([]byte(line), &object); err != nil {
+			return nil, fmt.Errorf("invalid saved object at line %d of %s: %v", i+1, source, err)
+		}
+		if object.Type == "" && object.ExportedCount != nil {
+			continue
+		}
+		if object.Type == "" || object.ID == "" {
+			return nil, fmt.Errorf("saved object at line %d of %s has no type or id", i+1, source)
+		}
+		// objects are compacted, so that formatting changes do not change the hash
+		data := &bytes.Buffer{}
+		if err := json.Compact(data, []byte(line)); err != nil {
+			return nil, err
+		}
+		hash := fnv.New32a()
+		hash.Write(data.Bytes())
+		objects = append(objects, ProvisionedObject{
+			Type:   object.Type,
+			ID:     object.ID,
+			Source: source,
+			Hash:   fmt.Sprintf("%08x", hash.Sum32()),
+			Data:   data.Bytes(),
+		})
+	}
+	return objects, nil
+}
+
+// ProvisionSavedObjects imports the saved objects which are new or have changed since they were last imported into
+// OpenSearch Dashboards, overwriting any existing object of the same type and ID. Saved objects which were imported
+// before, but are no longer held by a ConfigMap, are deleted. The imported objects are kept in the VMI status.
+func (od *OSDashboardsClient) ProvisionSavedObjects(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, objects []ProvisionedObject) error {
+	if !vmi.Spec.Kibana.Enabled {
+		vmi.Status.Kibana.SavedObjects = nil
+		return nil
+	}
+	previous := map[savedObjectKey]vmcontrollerv1.SavedObjectStatus{}
+	for _, status := range vmi.Status.Kibana.SavedObjects {
+		previous[savedObjectKey{Type: status.Type, ID: status.ID}] = status
+	}
+	if len(objects) == 0 && len(previous) == 0 {
+		return nil
+	}
+	dashboardsEndpoint := resources.GetOpenSearchDashboardsHTTPEndpoint(vmi)
+
+	provisioned := map[savedObjectKey]vmcontrollerv1.SavedObjectStatus{}
+	var changed []ProvisionedObject
+	for _, object := range objects {
+		key := savedObjectKey{Type: object.Type, ID: object.ID}
+		if status, ok := previous[key]; ok && status.Hash == object.Hash {
+			provisioned[key] = getSavedObjectStatus(object)
+			continue
+		}
+		changed = append(changed, object)
+	}
+
+	var errs []string
+	if len(changed) > 0 {
+		failed, err := od.importSavedObjects(dashboardsEndpoint, "", changed, true)
+		if err != nil {
+			return fmt.Errorf("failed to import saved objects: %v", err)
+		}
+		for _, object := range changed {
+			key := savedObjectKey{Type: object.Type, ID: object.ID}
+			if reason, ok := failed[key]; ok {
+				errs = append(errs, fmt.Sprintf("%s %s of %s: %s", object.Type, object.ID, object.Source, reason))
+				// a previously imported object is imported again on the next attempt
+				if status, ok := previous[key]; ok {
+					provisioned[key] = status
+				}
+				continue
+			}
+			log.Infof("Imported %s %s from %s into OpenSearch Dashboards", object.Type, object.ID, object.Source)
+			provisioned[key] = getSavedObjectStatus(object)
+		}
+	}
+
+	for key, status := range previous {
+		if _, ok := provisioned[key]; ok || isSavedObjectHeld(objects, key) {
+			continue
+		}
+		if err := od.deleteSavedObject(dashboardsEndpoint, status.Type, status.ID); err != nil {
+			errs = append(errs, fmt.Sprintf("%s %s: %v", status.Type, status.ID, err))
+			provisioned[key] = status
+			continue
+		}
+		log.Infof("Deleted %s %s from OpenSearch Dashboards, since it was removed from %s", status.Type, status.ID, status.Source)
+	}
+
+	vmi.Status.Kibana.SavedObjects = nil
+	for _, status := range provisioned {
+		vmi.Status.Kibana.SavedObjects = append(vmi.Status.Kibana.SavedObjects, status)
+	}
+	sort.Slice(vmi.Status.Kibana.SavedObjects, func(i, j int) bool {
+		a, b := vmi.Status.Kibana.SavedObjects[i], vmi.Status.Kibana.SavedObjects[j]
+		return a.Type < b.Type || (a.Type == b.Type && a.ID < b.ID)
+	})
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to provision saved objects: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// importSavedObjects imports the objects as an NDJSON file into the tenant, or into the global tenant when no tenant is
+// given, and returns the reason each failed object was not imported
+func (od *OSDashboardsClient) importSavedObjects(dashboardsEndpoint, tenant string, objects []ProvisionedObject, overwrite bool) (map[savedObjectKey]string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	file, err := writer.CreateFormFile("file", savedObjectsFile)
+	if err != nil {
+		return nil, err
+	}
+	for _, object := range objects {
+		file.Write(object.Data)
+		file.Write([]byte("\n"))
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/api/saved_objects/_import?overwrite=%t", dashboardsEndpoint, overwrite)
+	req, err := http.NewRequest("POST", url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", writer.FormDataContentType())
+	req.Header.Add("osd-xsrf", "true")
+	if tenant != "" {
+		req.Header.Add(tenantHeader, tenant)
+	}
+	resp, err := od.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		responseBody, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("got status code %d: %s", resp.StatusCode, string(responseBody))
+	}
+	importResponse := &ImportResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(importResponse); err != nil {
+		return nil, err
+	}
+	failed := map[savedObjectKey]string{}
+	for _, importError := range importResponse.Errors {
+		failed[savedObjectKey{Type: importError.Type, ID: importError.ID}] = importError.Error.Type
+	}
+	return failed, nil
+}
+
+// deleteSavedObject deletes the saved object, if it exists
+func (od *OSDashboardsClient) deleteSavedObject(dashboardsEndpoint, objectType, id string) error {
+	url := fmt.Sprintf("%s/api/saved_objects/%s/%s", dashboardsEndpoint, objectType, id)
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("osd-xsrf", "true")
+	resp, err := od.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("got status code %d when deleting the saved object", resp.StatusCode)
+	}
+	return nil
+}
+
+func getSavedObjectStatus(object ProvisionedObject) vmcontrollerv1.SavedObjectStatus {
+	return vmcontrollerv1.SavedObjectStatus{
+		Type:   object.Type,
+		ID:     object.ID,
+		Source: object.Source,
+		Hash:   object.Hash,
+	}
+}
+
+// isSavedObjectHeld returns true if a ConfigMap holds the object, even when it failed to import
+func isSavedObjectHeld(objects []ProvisionedObject, key savedObjectKey) bool {
+	for _, object := range objects {
+		if object.Type == key.Type && object.ID == key.ID {
+			return true
+		}
+	}
+	return false
+}
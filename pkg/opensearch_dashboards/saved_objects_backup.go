@@ -0,0 +1,80 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package dashboards
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// exportedTypes are the types of the saved objects which are exported
+var exportedTypes = []string{"config", "dashboard", "index-pattern", "query", "search", "url", "visualization"}
+
+// ExportSavedObjects exports all saved objects of the tenant, or of the global tenant when no tenant is given, as NDJSON
+func (od *OSDashboardsClient) ExportSavedObjects(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, tenant string) ([]byte, error) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"type":                  exportedTypes,
+		"includeReferencesDeep": true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	url := resources.GetOpenSearchDashboardsHTTPEndpoint(vmi) + "/api/saved_objects/_export"
+	req, err := http.NewRequest("POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("osd-xsrf", "true")
+	if tenant != "" {
+		req.Header.Add(tenantHeader, tenant)
+	}
+	resp, err := od.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got status code %d when exporting saved objects: %s", resp.StatusCode, string(body))
+	}
+	return body, nil
+}
+
+// RestoreSavedObjects imports the NDJSON export into the tenant, or into the global tenant when no tenant is given,
+// overwriting existing objects of the same type and ID
+func (od *OSDashboardsClient) RestoreSavedObjects(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, tenant, source, ndjson string) error {
+	objects, err := parseSavedObjects(source, ndjson)
+	if err != nil {
+		return err
+	}
+	if len(objects) == 0 {
+		return nil
+	}
+	failed, err := od.importSavedObjects(resources.GetOpenSearchDashboardsHTTPEndpoint(vmi), tenant, objects, true)
+	if err != nil {
+		return fmt.Errorf("failed to restore saved objects of %s: %v", source, err)
+	}
+	if len(failed) > 0 {
+		var errs []string
+		for key, reason := range failed {
+			errs = append(errs, fmt.Sprintf("%s %s: %s", key.Type, key.ID, reason))
+		}
+		sort.Strings(errs)
+		return fmt.Errorf("failed to restore saved objects of %s: %s", source, strings.Join(errs, ", "))
+	}
+	log.Infof("Restored %d saved objects of %s into OpenSearch Dashboards", len(objects), source)
+	return nil
+}
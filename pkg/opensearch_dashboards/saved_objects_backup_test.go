@@ -0,0 +1,69 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package dashboards
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestExportSavedObjects Tests exporting the saved objects of a tenant
+// GIVEN OpenSearch Dashboards with saved objects
+// WHEN I call ExportSavedObjects
+// THEN all saved object types of the tenant are exported, or an error is returned when the export fails
+func TestExportSavedObjects(t *testing.T) {
+	statusCode := http.StatusOK
+	osd := NewOSDashboardsClient()
+	osd.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/api/saved_objects/_export", request.URL.Path)
+		assert.Equal(t, "bobs-books", request.Header.Get(tenantHeader))
+		payload := struct {
+			Type []string `json:"type"`
+		}{}
+		assert.NoError(t, json.NewDecoder(request.Body).Decode(&payload))
+		assert.Equal(t, exportedTypes, payload.Type)
+		return &http.Response{StatusCode: statusCode, Body: io.NopCloser(strings.NewReader(testDashboard))}, nil
+	}
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{}
+	export, err := osd.ExportSavedObjects(vmi, "bobs-books")
+	assert.NoError(t, err)
+	assert.Equal(t, testDashboard, string(export))
+
+	statusCode = http.StatusBadRequest
+	_, err = osd.ExportSavedObjects(vmi, "bobs-books")
+	assert.Error(t, err)
+}
+
+// TestRestoreSavedObjects Tests restoring an export of saved objects
+// GIVEN an NDJSON export of saved objects
+// WHEN I call RestoreSavedObjects
+// THEN the objects are imported into the global tenant, overwriting existing objects, and import errors are returned
+func TestRestoreSavedObjects(t *testing.T) {
+	body := `{"success": true, "successCount": 2}`
+	imports := 0
+	osd := NewOSDashboardsClient()
+	osd.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		imports++
+		assert.Equal(t, "true", request.URL.Query().Get("overwrite"))
+		assert.Empty(t, request.Header.Get(tenantHeader))
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	}
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{}
+	export := testIndexPattern + "\n" + testDashboard + "\n" + testExportCount
+	assert.NoError(t, osd.RestoreSavedObjects(vzlog.DefaultLogger(), vmi, "", "export/global_tenant.ndjson", export))
+	assert.Equal(t, 1, imports)
+
+	body = `{"success": false, "successCount": 1, "errors": [{"type": "dashboard", "id": "overview", "error": {"type": "unknown"}}]}`
+	assert.Error(t, osd.RestoreSavedObjects(vzlog.DefaultLogger(), vmi, "", "export/global_tenant.ndjson", export))
+
+	// an empty export is not imported
+	assert.NoError(t, osd.RestoreSavedObjects(vzlog.DefaultLogger(), vmi, "", "export/global_tenant.ndjson", testExportCount))
+	assert.Equal(t, 2, imports)
+}
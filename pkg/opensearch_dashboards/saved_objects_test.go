@@ -0,0 +1,183 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package dashboards
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	"io"
+	"io/ioutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const (
+	testIndexPattern = `{"type": "index-pattern", "id": "logs", "attributes": {"title": "verrazzano-*"}}`
+	testDashboard    = `{"type":"dashboard","id":"overview","attributes":{"title":"Overview"}}`
+	testExportCount  = `{"exportedCount":2,"missingRefCount":0,"missingReferences":[]}`
+)
+
+func createSavedObjectsConfigMap(name string, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Data:       data,
+	}
+}
+
+// TestGetSavedObjects Tests parsing the saved objects of ConfigMaps
+// GIVEN ConfigMaps holding NDJSON saved object exports
+// WHEN I call GetSavedObjects
+// THEN the objects are returned in ConfigMap and key order, without the export summary or duplicates
+func TestGetSavedObjects(t *testing.T) {
+	objects, err := GetSavedObjects(vzlog.DefaultLogger(), []*corev1.ConfigMap{
+		createSavedObjectsConfigMap("b", map[string]string{"duplicate.ndjson": testIndexPattern}),
+		createSavedObjectsConfigMap("a", map[string]string{
+			"overview.ndjson": testIndexPattern + "\n\n" + testDashboard + "\n" + testExportCount + "\n",
+		}),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, objects, 2)
+	assert.Equal(t, "index-pattern", objects[0].Type)
+	assert.Equal(t, "logs", objects[0].ID)
+	assert.Equal(t, "a/overview.ndjson", objects[0].Source)
+	assert.Equal(t, `{"type":"index-pattern","id":"logs","attributes":{"title":"verrazzano-*"}}`, string(objects[0].Data))
+	assert.Equal(t, "dashboard", objects[1].Type)
+
+	// formatting does not change the hash
+	reformatted, err := GetSavedObjects(vzlog.DefaultLogger(), []*corev1.ConfigMap{
+		createSavedObjectsConfigMap("a", map[string]string{"overview.ndjson": strings.ReplaceAll(testDashboard, ",", ", ")}),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, objects[1].Hash, reformatted[0].Hash)
+
+	for _, invalid := range []string{`{"type": "dashboard"}`, `{"type": "dashboard", "id": `} {
+		_, err = GetSavedObjects(vzlog.DefaultLogger(), []*corev1.ConfigMap{
+			createSavedObjectsConfigMap("a", map[string]string{"invalid.ndjson": invalid}),
+		})
+		assert.Error(t, err, invalid)
+	}
+}
+
+// TestProvisionSavedObjects Tests importing saved objects into OpenSearch Dashboards
+// GIVEN saved objects of ConfigMaps, and the saved objects imported earlier
+// WHEN I call ProvisionSavedObjects
+// THEN new and changed objects are imported, removed objects are deleted, and the status holds the imported objects
+func TestProvisionSavedObjects(t *testing.T) {
+	objects, err := GetSavedObjects(vzlog.DefaultLogger(), []*corev1.ConfigMap{
+		createSavedObjectsConfigMap("a", map[string]string{"overview.ndjson": testIndexPattern + "\n" + testDashboard}),
+	})
+	assert.NoError(t, err)
+	unchanged := getSavedObjectStatus(objects[0])
+	changed := getSavedObjectStatus(objects[1])
+	changed.Hash = "0"
+	removed := vmcontrollerv1.SavedObjectStatus{Type: "visualization", ID: "removed", Source: "b/removed.ndjson", Hash: "1"}
+
+	var tests = []struct {
+		name           string
+		importErrors   string
+		deleteStatus   int
+		requests       []string
+		imported       string
+		isError        bool
+		expectedHashes map[string]string
+	}{
+		{
+			"changed objects are imported and removed objects are deleted",
+			"",
+			http.StatusOK,
+			[]string{"POST /api/saved_objects/_import", "DELETE /api/saved_objects/visualization/removed"},
+			testDashboard,
+			false,
+			map[string]string{"logs": objects[0].Hash, "overview": objects[1].Hash},
+		},
+		{
+			"objects which fail to import are retried",
+			`[{"type": "dashboard", "id": "overview", "error": {"type": "missing_references"}}]`,
+			http.StatusNotFound,
+			[]string{"POST /api/saved_objects/_import", "DELETE /api/saved_objects/visualization/removed"},
+			testDashboard,
+			true,
+			map[string]string{"logs": objects[0].Hash, "overview": "0"},
+		},
+		{
+			"objects which fail to delete are kept",
+			"",
+			http.StatusInternalServerError,
+			[]string{"POST /api/saved_objects/_import", "DELETE /api/saved_objects/visualization/removed"},
+			testDashboard,
+			true,
+			map[string]string{"logs": objects[0].Hash, "overview": objects[1].Hash, "removed": "1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{}
+			vmi.Spec.Kibana.Enabled = true
+			vmi.Status.Kibana.SavedObjects = []vmcontrollerv1.SavedObjectStatus{unchanged, changed, removed}
+
+			var requests []string
+			var imported []byte
+			osd := NewOSDashboardsClient()
+			osd.DoHTTP = func(request *http.Request) (*http.Response, error) {
+				requests = append(requests, request.Method+" "+request.URL.Path)
+				assert.Equal(t, "true", request.Header.Get("osd-xsrf"))
+				if request.Method == "DELETE" {
+					return &http.Response{StatusCode: tt.deleteStatus, Body: io.NopCloser(strings.NewReader(""))}, nil
+				}
+				assert.Equal(t, "true", request.URL.Query().Get("overwrite"))
+				file, _, err := request.FormFile("file")
+				assert.NoError(t, err)
+				imported, _ = ioutil.ReadAll(file)
+				body := `{"success": true, "successCount": 1}`
+				if tt.importErrors != "" {
+					body = `{"success": false, "successCount": 0, "errors": ` + tt.importErrors + `}`
+				}
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+			}
+
+			err := osd.ProvisionSavedObjects(vzlog.DefaultLogger(), vmi, objects)
+			if tt.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.requests, requests)
+			var expected map[string]interface{}
+			assert.NoError(t, json.Unmarshal([]byte(tt.imported), &expected))
+			var actual map[string]interface{}
+			assert.NoError(t, json.Unmarshal(imported, &actual))
+			assert.Equal(t, expected, actual)
+			hashes := map[string]string{}
+			for _, status := range vmi.Status.Kibana.SavedObjects {
+				hashes[status.ID] = status.Hash
+			}
+			assert.Equal(t, tt.expectedHashes, hashes)
+		})
+	}
+}
+
+// TestProvisionSavedObjectsNothingToDo Tests that OpenSearch Dashboards is not called without saved objects
+// GIVEN a VMI without saved objects, or with OpenSearch Dashboards disabled
+// WHEN I call ProvisionSavedObjects
+// THEN no request is sent, and the status is cleared when OpenSearch Dashboards is disabled
+func TestProvisionSavedObjectsNothingToDo(t *testing.T) {
+	osd := NewOSDashboardsClient()
+	osd.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		t.Errorf("unexpected request %s %s", request.Method, request.URL.Path)
+		return nil, nil
+	}
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{}
+	vmi.Spec.Kibana.Enabled = true
+	assert.NoError(t, osd.ProvisionSavedObjects(vzlog.DefaultLogger(), vmi, nil))
+
+	vmi.Spec.Kibana.Enabled = false
+	vmi.Status.Kibana.SavedObjects = []vmcontrollerv1.SavedObjectStatus{{Type: "dashboard", ID: "overview"}}
+	assert.NoError(t, osd.ProvisionSavedObjects(vzlog.DefaultLogger(), vmi, []ProvisionedObject{{Type: "dashboard", ID: "overview"}}))
+	assert.Empty(t, vmi.Status.Kibana.SavedObjects)
+}
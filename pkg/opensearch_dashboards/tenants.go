@@ -0,0 +1,131 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package dashboards
+
+import (
+	"encoding/json"
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+const (
+	// tenantHeader selects the tenant of an OpenSearch Dashboards API request
+	tenantHeader = "securitytenant"
+	// conflictError is the import error of an object which already exists, and is not overwritten
+	conflictError = "conflict"
+	// defaultIndexPatternsSource is the source of the default index patterns seeded into tenants
+	defaultIndexPatternsSource = "default-index-patterns"
+)
+
+// SeedTenants imports the default index patterns and the saved objects of ConfigMaps into the tenants seeded with them.
+// Objects which already exist in a tenant are never overwritten, so teams may change the objects seeded into their
+// tenant. Objects added later are seeded whenever the seeded objects change. The tenants are kept in the VMI status.
+func (od *OSDashboardsClient) SeedTenants(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance, objects []ProvisionedObject) error {
+	previous := map[string]string{}
+	for _, status := range vmi.Status.Kibana.Tenants {
+		previous[status.Name] = status.SeedHash
+	}
+	dashboardsEndpoint := resources.GetOpenSearchDashboardsHTTPEndpoint(vmi)
+
+	var statuses []vmcontrollerv1.TenantStatus
+	var errs []string
+	for _, tenant := range vmi.Spec.Kibana.Tenants {
+		status := vmcontrollerv1.TenantStatus{Name: tenant.Name, SeedHash: previous[tenant.Name]}
+		statuses = append(statuses, status)
+		seed, err := getTenantSeed(tenant, objects)
+		if err != nil {
+			return err
+		}
+		hash := getSeedHash(seed)
+		if !vmi.Spec.Kibana.Enabled || len(seed) == 0 || hash == status.SeedHash {
+			continue
+		}
+		if err := od.seedTenant(dashboardsEndpoint, tenant.Name, seed); err != nil {
+			errs = append(errs, fmt.Sprintf("tenant %s: %v", tenant.Name, err))
+			continue
+		}
+		log.Infof("Seeded OpenSearch Dashboards tenant %s with %d saved objects", tenant.Name, len(seed))
+		statuses[len(statuses)-1].SeedHash = hash
+	}
+	vmi.Status.Kibana.Tenants = statuses
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to seed tenants: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// seedTenant imports the objects into the tenant, without overwriting objects which already exist
+func (od *OSDashboardsClient) seedTenant(dashboardsEndpoint, tenant string, objects []ProvisionedObject) error {
+	failed, err := od.importSavedObjects(dashboardsEndpoint, tenant, objects, false)
+	if err != nil {
+		return err
+	}
+	var errs []string
+	for key, reason := range failed {
+		if reason != conflictError {
+			errs = append(errs, fmt.Sprintf("%s %s: %s", key.Type, key.ID, reason))
+		}
+	}
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("failed to import %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// getTenantSeed returns the objects the tenant is seeded with. A saved object of a ConfigMap replaces a default index
+// pattern with the same ID.
+func getTenantSeed(tenant vmcontrollerv1.DashboardsTenant, objects []ProvisionedObject) ([]ProvisionedObject, error) {
+	var seed []ProvisionedObject
+	held := map[savedObjectKey]bool{}
+	if tenant.SeedSavedObjects {
+		for _, object := range objects {
+			held[savedObjectKey{Type: object.Type, ID: object.ID}] = true
+		}
+		seed = append(seed, objects...)
+	}
+	if tenant.SeedIndexPatterns {
+		indexPatterns, err := getDefaultIndexPatternObjects()
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range indexPatterns {
+			if !held[savedObjectKey{Type: object.Type, ID: object.ID}] {
+				seed = append(seed, object)
+			}
+		}
+	}
+	return seed, nil
+}
+
+// getDefaultIndexPatternObjects returns the default index patterns as saved objects
+func getDefaultIndexPatternObjects() ([]ProvisionedObject, error) {
+	var lines []string
+	for _, pattern := range GetDefaultIndexPatterns() {
+		line, err := json.Marshal(map[string]interface{}{
+			"type":       "index-pattern",
+			"id":         pattern.ID,
+			"attributes": IndexPatternAttributes{Title: pattern.Title, TimeFieldName: timestampField},
+		})
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, string(line))
+	}
+	return parseSavedObjects(defaultIndexPatternsSource, strings.Join(lines, "\n"))
+}
+
+// getSeedHash returns a hash of the seeded objects, which changes whenever an object is added or changed
+func getSeedHash(objects []ProvisionedObject) string {
+	hash := fnv.New32a()
+	for _, object := range objects {
+		hash.Write([]byte(object.Type + "/" + object.ID + "/" + object.Hash + "\n"))
+	}
+	return fmt.Sprintf("%08x", hash.Sum32())
+}
@@ -0,0 +1,97 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package dashboards
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	"io"
+	"io/ioutil"
+	corev1 "k8s.io/api/core/v1"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestSeedTenants Tests seeding OpenSearch Dashboards tenants
+// GIVEN a VMI with tenants seeded with the default index patterns and the saved objects of ConfigMaps
+// WHEN I call SeedTenants
+// THEN the objects are imported into each tenant without overwriting existing objects, and only again once they change
+func TestSeedTenants(t *testing.T) {
+	objects, err := GetSavedObjects(vzlog.DefaultLogger(), []*corev1.ConfigMap{
+		createSavedObjectsConfigMap("a", map[string]string{"overview.ndjson": testDashboard}),
+	})
+	assert.NoError(t, err)
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{}
+	vmi.Spec.Kibana.Enabled = true
+	vmi.Spec.Kibana.Tenants = []vmcontrollerv1.DashboardsTenant{
+		{Name: "bobs-books", SeedIndexPatterns: true, SeedSavedObjects: true},
+		{Name: "todo-list", SeedIndexPatterns: true},
+		{Name: "unseeded"},
+	}
+
+	imported := map[string][]string{}
+	importErrors := ""
+	osd := NewOSDashboardsClient()
+	osd.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		assert.Equal(t, "POST", request.Method)
+		assert.Equal(t, "/api/saved_objects/_import", request.URL.Path)
+		assert.Equal(t, "false", request.URL.Query().Get("overwrite"))
+		file, _, err := request.FormFile("file")
+		assert.NoError(t, err)
+		data, _ := ioutil.ReadAll(file)
+		tenant := request.Header.Get(tenantHeader)
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			object := struct {
+				ID string `json:"id"`
+			}{}
+			assert.NoError(t, json.Unmarshal([]byte(line), &object))
+			imported[tenant] = append(imported[tenant], object.ID)
+		}
+		body := `{"success": true, "successCount": 1}`
+		if importErrors != "" {
+			body = `{"success": false, "successCount": 0, "errors": ` + importErrors + `}`
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	}
+
+	assert.NoError(t, osd.SeedTenants(vzlog.DefaultLogger(), vmi, objects))
+	assert.Equal(t, map[string][]string{
+		"bobs-books": {"overview", "vmi-default-system", "vmi-default-application"},
+		"todo-list":  {"vmi-default-system", "vmi-default-application"},
+	}, imported)
+	assert.Len(t, vmi.Status.Kibana.Tenants, 3)
+	assert.Equal(t, "unseeded", vmi.Status.Kibana.Tenants[2].Name)
+	assert.Empty(t, vmi.Status.Kibana.Tenants[2].SeedHash)
+
+	// seeded tenants are not seeded again
+	imported = map[string][]string{}
+	assert.NoError(t, osd.SeedTenants(vzlog.DefaultLogger(), vmi, objects))
+	assert.Empty(t, imported)
+
+	// changed objects are seeded again, where existing objects are not overwritten
+	changed, err := GetSavedObjects(vzlog.DefaultLogger(), []*corev1.ConfigMap{
+		createSavedObjectsConfigMap("a", map[string]string{"overview.ndjson": testDashboard + "\n" + testIndexPattern}),
+	})
+	assert.NoError(t, err)
+	importErrors = `[{"type": "dashboard", "id": "overview", "error": {"type": "conflict"}}]`
+	assert.NoError(t, osd.SeedTenants(vzlog.DefaultLogger(), vmi, changed))
+	assert.Equal(t, map[string][]string{
+		"bobs-books": {"overview", "logs", "vmi-default-system", "vmi-default-application"},
+	}, imported)
+
+	// tenants which fail to be seeded are seeded again
+	hash := vmi.Status.Kibana.Tenants[0].SeedHash
+	importErrors = `[{"type": "dashboard", "id": "overview", "error": {"type": "missing_references"}}]`
+	assert.Error(t, osd.SeedTenants(vzlog.DefaultLogger(), vmi, objects))
+	assert.Equal(t, hash, vmi.Status.Kibana.Tenants[0].SeedHash)
+
+	// removed tenants are removed from the status
+	vmi.Spec.Kibana.Tenants = vmi.Spec.Kibana.Tenants[1:]
+	assert.NoError(t, osd.SeedTenants(vzlog.DefaultLogger(), vmi, objects))
+	assert.Equal(t, "todo-list", vmi.Status.Kibana.Tenants[0].Name)
+	assert.Len(t, vmi.Status.Kibana.Tenants, 2)
+}
@@ -0,0 +1,45 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package resources
+
+import (
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"gopkg.in/yaml.v2"
+	"hash/fnv"
+)
+
+// IsOpenSearchDashboardsConfigured returns true if the VMI has OpenSearch Dashboards settings, which are kept in a ConfigMap
+func IsOpenSearchDashboardsConfigured(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) bool {
+	return vmo.Spec.Kibana.Enabled && len(vmo.Spec.Kibana.Config) > 0
+}
+
+// GetOpenSearchDashboardsConfigMapName returns the name of the ConfigMap holding opensearch_dashboards.yml
+func GetOpenSearchDashboardsConfigMapName(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) string {
+	return GetMetaName(vmo.Name, constants.OpenSearchDashboardsConfig)
+}
+
+// GetOpenSearchDashboardsConfig renders opensearch_dashboards.yml from the settings of the VMI, which OpenSearch Dashboards
+// merges over the config file of the image. Each value is parsed as YAML, so numbers, booleans and lists keep their type.
+func GetOpenSearchDashboardsConfig(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) string {
+	settings := map[string]interface{}{}
+	for key, value := range vmo.Spec.Kibana.Config {
+		var parsed interface{}
+		if err := yaml.Unmarshal([]byte(value), &parsed); err != nil || parsed == nil {
+			parsed = value
+		}
+		settings[key] = parsed
+	}
+	// keys are sorted, so the file only changes when the settings do
+	config, _ := yaml.Marshal(settings)
+	return string(config)
+}
+
+// GetOpenSearchDashboardsConfigHash returns a hash of opensearch_dashboards.yml, which changes whenever the file does
+func GetOpenSearchDashboardsConfigHash(config string) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(config))
+	return fmt.Sprintf("%08x", hash.Sum32())
+}
@@ -0,0 +1,52 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package resources
+
+import (
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"gopkg.in/yaml.v2"
+	"testing"
+)
+
+// TestGetOpenSearchDashboardsConfig Tests rendering opensearch_dashboards.yml
+// GIVEN a VMI with OpenSearch Dashboards settings
+// WHEN I call GetOpenSearchDashboardsConfig
+// THEN only the settings of the VMI are rendered, and keep their YAML type
+func TestGetOpenSearchDashboardsConfig(t *testing.T) {
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{}
+	vmo.Spec.Kibana.Enabled = true
+	assert.False(t, IsOpenSearchDashboardsConfigured(vmo))
+	vmo.Spec.Kibana.Config = map[string]string{
+		"server.basePath":              "/dashboards",
+		"opensearch.requestTimeout":    "60000",
+		"csp.strict":                   "true",
+		"csp.rules":                    `["script-src 'self'"]`,
+		"uiSettings.overrides.theme":   "{invalid",
+		"vis_type_vega.enabled":        "false",
+		"opensearchDashboards.index":   ".kibana",
+		"uiSettings.overrides.default": "",
+	}
+	assert.True(t, IsOpenSearchDashboardsConfigured(vmo))
+
+	config := GetOpenSearchDashboardsConfig(vmo)
+	settings := map[string]interface{}{}
+	assert.NoError(t, yaml.Unmarshal([]byte(config), &settings))
+	assert.Len(t, settings, len(vmo.Spec.Kibana.Config))
+	assert.Equal(t, "/dashboards", settings["server.basePath"])
+	assert.Equal(t, 60000, settings["opensearch.requestTimeout"])
+	assert.Equal(t, true, settings["csp.strict"])
+	assert.Equal(t, []interface{}{"script-src 'self'"}, settings["csp.rules"])
+	assert.Equal(t, "{invalid", settings["uiSettings.overrides.theme"])
+	assert.Equal(t, false, settings["vis_type_vega.enabled"])
+	assert.Equal(t, "", settings["uiSettings.overrides.default"])
+
+	// the config is stable, and its hash changes with the settings
+	assert.Equal(t, config, GetOpenSearchDashboardsConfig(vmo))
+	hash := GetOpenSearchDashboardsConfigHash(config)
+	vmo.Spec.Kibana.Config["server.host"] = "localhost"
+	changed := GetOpenSearchDashboardsConfig(vmo)
+	assert.Contains(t, changed, "server.host: localhost")
+	assert.NotEqual(t, hash, GetOpenSearchDashboardsConfigHash(changed))
+}
@@ -262,11 +262,43 @@ func NewOpenSearchDashboardsDeployment(vmo *vmcontrollerv1.VerrazzanoMonitoringI
 			deployment.Spec.Template.Annotations = make(map[string]string)
 		}
 		deployment.Spec.Template.Annotations["traffic.sidecar.istio.io/includeOutboundPorts"] = fmt.Sprintf("%d", constants.OSHTTPPort)
+
+		if resources.IsOpenSearchDashboardsConfigured(vmo) {
+			addOpenSearchDashboardsConfig(vmo, deployment)
+		}
 	}
 
 	return deployment
 }
 
+// addOpenSearchDashboardsConfig mounts opensearch_dashboards.yml from its ConfigMap next to the config file of the image.
+// OpenSearch Dashboards merges the config files in the order they are given, so the settings of the VMI override the
+// settings of the image, such as those of the security plugin. The hash of the file is a pod annotation, so the pods are
+// rolled out whenever the file changes.
+func addOpenSearchDashboardsConfig(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, deployment *appsv1.Deployment) {
+	const volumeName = "osd-config"
+	deployment.Spec.Template.Spec.Volumes = append(deployment.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name: volumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: resources.GetOpenSearchDashboardsConfigMapName(vmo)},
+			},
+		},
+	})
+	deployment.Spec.Template.Spec.Containers[0].VolumeMounts = append(deployment.Spec.Template.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      volumeName,
+		MountPath: constants.OpenSearchDashboardsVMIConfigMountPath,
+		ReadOnly:  true,
+	})
+	// the entrypoint of the image passes its arguments on to OpenSearch Dashboards
+	deployment.Spec.Template.Spec.Containers[0].Args = []string{
+		"--config=" + constants.OpenSearchDashboardsConfigPath,
+		"--config=" + constants.OpenSearchDashboardsVMIConfigMountPath + "/" + constants.OpenSearchDashboardsYaml,
+	}
+	config := resources.GetOpenSearchDashboardsConfig(vmo)
+	deployment.Spec.Template.Annotations[constants.OpenSearchDashboardsConfigHashAnnotation] = resources.GetOpenSearchDashboardsConfigHash(config)
+}
+
 func createVolumeElement(pvcName string) corev1.Volume {
 	return corev1.Volume{
 		Name: constants.StorageVolumeName,
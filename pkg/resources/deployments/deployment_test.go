@@ -373,3 +373,37 @@ func getDeploymentByName(deploymentName string, deploymentList []*appsv1.Deploym
 	}
 	return nil, fmt.Errorf("deployment %s not found", deploymentName)
 }
+
+// TestOpenSearchDashboardsDeploymentConfig Tests mounting opensearch_dashboards.yml
+// GIVEN a VMI with and without OpenSearch Dashboards settings
+// WHEN I call NewOpenSearchDashboardsDeployment
+// THEN the config file is only mounted with settings, next to the config file of the image, and the pods are annotated with its hash
+func TestOpenSearchDashboardsDeploymentConfig(t *testing.T) {
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{}
+	vmo.Name = "system"
+	vmo.Spec.Kibana.Enabled = true
+	deployment := NewOpenSearchDashboardsDeployment(vmo)
+	assert.Empty(t, deployment.Spec.Template.Spec.Volumes)
+	assert.Empty(t, deployment.Spec.Template.Spec.Containers[0].Args)
+	assert.NotContains(t, deployment.Spec.Template.Annotations, constants.OpenSearchDashboardsConfigHashAnnotation)
+
+	vmo.Spec.Kibana.Config = map[string]string{"server.basePath": "/dashboards"}
+	deployment = NewOpenSearchDashboardsDeployment(vmo)
+	assert.Len(t, deployment.Spec.Template.Spec.Volumes, 1)
+	assert.Equal(t, "vmi-system-osd-config", deployment.Spec.Template.Spec.Volumes[0].ConfigMap.Name)
+	mounts := deployment.Spec.Template.Spec.Containers[0].VolumeMounts
+	assert.Equal(t, constants.OpenSearchDashboardsVMIConfigMountPath, mounts[len(mounts)-1].MountPath)
+	assert.Empty(t, mounts[len(mounts)-1].SubPath)
+	// the settings of the VMI are merged over the config file of the image
+	assert.Equal(t, []string{
+		"--config=/usr/share/opensearch-dashboards/config/opensearch_dashboards.yml",
+		"--config=/usr/share/opensearch-dashboards/config/vmi/opensearch_dashboards.yml",
+	}, deployment.Spec.Template.Spec.Containers[0].Args)
+	hash := deployment.Spec.Template.Annotations[constants.OpenSearchDashboardsConfigHashAnnotation]
+	assert.NotEmpty(t, hash)
+
+	// changed settings roll out the pods
+	vmo.Spec.Kibana.Config["server.basePath"] = "/osd"
+	deployment = NewOpenSearchDashboardsDeployment(vmo)
+	assert.NotEqual(t, hash, deployment.Spec.Template.Annotations[constants.OpenSearchDashboardsConfigHashAnnotation])
+}
@@ -51,6 +51,7 @@ func (es ElasticsearchBasic) createCommonDeployment(vmo *vmcontrollerv1.Verrazza
 		corev1.EnvVar{Name: "cluster.name", Value: vmo.Name},
 		corev1.EnvVar{Name: "logger.org.opensearch", Value: "info"},
 	)
+	esContainer.Env = append(esContainer.Env, nodes.GetNodeAttributeEnvVars(&node)...)
 
 	esContainer.Ports = []corev1.ContainerPort{
 		{Name: "http", ContainerPort: int32(constants.OSHTTPPort)},
@@ -73,6 +74,8 @@ func (es ElasticsearchBasic) createCommonDeployment(vmo *vmcontrollerv1.Verrazza
 
 	// Add init containers
 	deploymentElement.Spec.Template.Spec.InitContainers = append(deploymentElement.Spec.Template.Spec.InitContainers, *resources.GetElasticsearchInitContainer())
+	resources.AddOpenSearchPluginInstaller(&deploymentElement.Spec.Template.Spec, resources.GetOpenSearchPlugins(vmo, node))
+	resources.AddRemoteClusterCAs(&deploymentElement.Spec.Template.Spec, vmo)
 
 	// Add node labels
 	deploymentElement.Spec.Selector.MatchLabels[constants.NodeGroupLabel] = node.Name
@@ -232,8 +235,10 @@ if [ "${OBJECT_STORE_SECRET_KEY_ID:-}" ]; then
     echo "Updating object store secret key..."
 	echo $OBJECT_STORE_SECRET_KEY_ID | /usr/share/opensearch/bin/opensearch-keystore add --stdin --force s3.client.default.secret_key;
 fi
-/usr/local/bin/docker-entrypoint.sh`,
+` + resources.SMTPCredentialsScript + `/usr/local/bin/docker-entrypoint.sh`,
 			}
+			// mount the SMTP credentials of email notification channels, for the keystore
+			resources.AddSMTPCredentials(&dataDeployment.Spec.Template.Spec, vmo)
 
 			// add the required istio annotations to allow inter-es component communication
 			if dataDeployment.Spec.Template.Annotations == nil {
@@ -247,6 +252,20 @@ fi
 	return deployments
 }
 
+//GetDataNodeAvailabilityDomains returns the distinct availability domains the OpenSearch data nodes are spread across
+func GetDataNodeAvailabilityDomains(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, pvcToAdMap map[string]string) []string {
+	var availabilityDomains []string
+	for _, node := range nodes.DataNodes(vmo) {
+		for i := 0; i < int(node.Replicas); i++ {
+			availabilityDomain := getAvailabilityDomainForPvcIndex(node.Storage, pvcToAdMap, i)
+			if availabilityDomain != "" && !resources.SliceContains(availabilityDomains, availabilityDomain) {
+				availabilityDomains = append(availabilityDomains, availabilityDomain)
+			}
+		}
+	}
+	return availabilityDomains
+}
+
 // Creates *all* Elasticsearch deployment elements
 func (es ElasticsearchBasic) createElasticsearchDeploymentElements(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, pvcToAdMap map[string]string) []*appsv1.Deployment {
 	var deployList []*appsv1.Deployment
@@ -112,6 +112,7 @@ func TestElasticsearchDefaultDeployments2(t *testing.T) {
 					},
 					Roles: []vmcontrollerv1.NodeRole{vmcontrollerv1.DataRole},
 					Name:  config.ElasticsearchData.Name,
+					Tier:  "warm",
 				},
 				Enabled: true,
 			},
@@ -133,7 +134,27 @@ func TestElasticsearchDefaultDeployments2(t *testing.T) {
 		assert.NotNil(t, dataDeployment, fmt.Sprintf("DataNodes deployment for index %d", i))
 		assert.Equal(t, int32(1), *dataDeployment.Spec.Replicas, fmt.Sprintf("DataNodes replicas for index %d", i))
 		assert.Equal(t, "data", getEnvVarValue("node.roles", dataDeployment.Spec.Template.Spec.Containers[0].Env))
+		assert.Equal(t, "warm", getEnvVarValue("node.attr.tier", dataDeployment.Spec.Template.Spec.Containers[0].Env))
 	}
+	assert.Equal(t, "", getEnvVarValue("node.attr.tier", ingestEnv))
+}
+
+func TestGetDataNodeAvailabilityDomains(t *testing.T) {
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Elasticsearch: vmcontrollerv1.Elasticsearch{
+				DataNode: vmcontrollerv1.ElasticsearchNode{
+					Replicas: 3,
+					Storage: &vmcontrollerv1.Storage{
+						PvcNames: []string{"pvc1", "pvc2", "pvc3"},
+					},
+				},
+			},
+		},
+	}
+	assert.Empty(t, GetDataNodeAvailabilityDomains(vmo, map[string]string{"pvc1": "", "pvc2": "", "pvc3": ""}))
+	assert.Equal(t, []string{"AD1"}, GetDataNodeAvailabilityDomains(vmo, map[string]string{"pvc1": "AD1", "pvc2": "AD1", "pvc3": "AD1"}))
+	assert.Equal(t, []string{"AD1", "AD2"}, GetDataNodeAvailabilityDomains(vmo, map[string]string{"pvc1": "AD1", "pvc2": "AD2", "pvc3": "AD1"}))
 }
 
 func getEnvVarValue(envVarName string, envVarList []corev1.EnvVar) string {
@@ -9,6 +9,8 @@ import (
 	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	corev1 "k8s.io/api/core/v1"
+	"sort"
 	"strings"
 )
 
@@ -31,6 +33,9 @@ var (
 	RoleAssigned = "true"
 )
 
+//TierAttribute is the node attribute which holds the tier of a node
+const TierAttribute = "tier"
+
 //MasterNodes returns the list of master role containing nodes in the VMI spec. These nodes will be created as statefulsets.
 func MasterNodes(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) []vmcontrollerv1.ElasticsearchNode {
 	return append([]vmcontrollerv1.ElasticsearchNode{vmo.Spec.Elasticsearch.MasterNode}, filterNodes(vmo, masterNodeMatcher)...)
@@ -72,6 +77,29 @@ func SetNodeRoleLabels(node *vmcontrollerv1.ElasticsearchNode, labels map[string
 	}
 }
 
+//GetNodeAttributeEnvVars returns the node.attr.* environment variables of a node's tier and custom attributes.
+// The tier takes precedence over a custom tier attribute.
+func GetNodeAttributeEnvVars(node *vmcontrollerv1.ElasticsearchNode) []corev1.EnvVar {
+	attributes := map[string]string{}
+	for name, value := range node.Attributes {
+		attributes[name] = value
+	}
+	if node.Tier != "" {
+		attributes[TierAttribute] = node.Tier
+	}
+	var names []string
+	for name := range attributes {
+		names = append(names, name)
+	}
+	// sorted, so the pod template does not change between reconciles
+	sort.Strings(names)
+	var envVars []corev1.EnvVar
+	for _, name := range names {
+		envVars = append(envVars, corev1.EnvVar{Name: "node.attr." + name, Value: attributes[name]})
+	}
+	return envVars
+}
+
 // IsSingleNodeCluster Returns true if only a single master node is requested; single-node ES cluster
 func IsSingleNodeCluster(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) bool {
 	nodeCount := GetNodeCount(vmo)
@@ -6,6 +6,7 @@ package nodes
 import (
 	"github.com/stretchr/testify/assert"
 	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	corev1 "k8s.io/api/core/v1"
 	"testing"
 )
 
@@ -204,3 +205,16 @@ func TestGetNodeRoleCount(t *testing.T) {
 	assert.EqualValues(t, 6, nodeRoles.IngestNodes)
 	assert.EqualValues(t, 8, nodeRoles.Replicas)
 }
+
+func TestGetNodeAttributeEnvVars(t *testing.T) {
+	assert.Nil(t, GetNodeAttributeEnvVars(&vmcontrollerv1.ElasticsearchNode{}))
+	node := &vmcontrollerv1.ElasticsearchNode{
+		Tier:       "warm",
+		Attributes: map[string]string{"zone": "ad1", "tier": "hot", "disk": "hdd"},
+	}
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "node.attr.disk", Value: "hdd"},
+		{Name: "node.attr.tier", Value: "warm"},
+		{Name: "node.attr.zone", Value: "ad1"},
+	}, GetNodeAttributeEnvVars(node))
+}
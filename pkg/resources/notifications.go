@@ -0,0 +1,70 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package resources
+
+import (
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// SMTPCredentialsPath is the directory which the SMTP credentials Secrets of email notification channels are mounted in
+	SMTPCredentialsPath = "/etc/opensearch/smtp-accounts/"
+	// SMTPCredentialsVolumePrefix is the name prefix of the volumes of the SMTP credentials Secrets
+	SMTPCredentialsVolumePrefix = "smtp-credentials-"
+	// smtpAccountSuffix is appended to the name of an email notification channel to name its SMTP account
+	smtpAccountSuffix = "-smtp-account"
+
+	// SMTPCredentialsScript adds the SMTP credentials mounted under SMTPCredentialsPath to the OpenSearch keystore,
+	// where the Notifications plugin reads them from
+	SMTPCredentialsScript = `# Updating the keystore with the SMTP credentials of the email notification channels
+for account in /etc/opensearch/smtp-accounts/*/; do
+    [ -d "$account" ] || continue
+    name=$(basename $account)
+    for key in username password; do
+        if [ -f "$account$key" ]; then
+            echo "Updating SMTP $key of $name..."
+            cat "$account$key" | /usr/share/opensearch/bin/opensearch-keystore add --stdin --force opensearch.notifications.core.email.$name.$key;
+        fi
+    done
+done
+`
+)
+
+// GetSMTPAccountName returns the name of the SMTP account of an email notification channel
+func GetSMTPAccountName(channelName string) string {
+	return channelName + smtpAccountSuffix
+}
+
+// AddSMTPCredentials mounts the SMTP credentials Secret of each email notification channel into the OpenSearch
+// container, at /etc/opensearch/smtp-accounts/<SMTP account name>, for SMTPCredentialsScript to add to the keystore
+func AddSMTPCredentials(podSpec *corev1.PodSpec, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) {
+	if len(podSpec.Containers) < 1 {
+		return
+	}
+	opensearchContainer := &podSpec.Containers[0]
+	optional := true
+	mounted := 0
+	for _, channel := range vmo.Spec.Elasticsearch.NotificationChannels {
+		if channel.Email == nil || channel.Email.CredentialsSecret == nil {
+			continue
+		}
+		// channel names may be longer than volume names, so the volumes are numbered instead
+		volumeName := fmt.Sprintf("%s%d", SMTPCredentialsVolumePrefix, mounted)
+		mounted++
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: volumeName,
+			VolumeSource: corev1.VolumeSource{
+				// nodes still start while the Secret does not exist, without the credentials
+				Secret: &corev1.SecretVolumeSource{SecretName: channel.Email.CredentialsSecret.Name, Optional: &optional},
+			},
+		})
+		opensearchContainer.VolumeMounts = append(opensearchContainer.VolumeMounts, corev1.VolumeMount{
+			Name:      volumeName,
+			MountPath: SMTPCredentialsPath + GetSMTPAccountName(channel.Name),
+			ReadOnly:  true,
+		})
+	}
+}
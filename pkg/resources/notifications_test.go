@@ -0,0 +1,36 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package resources
+
+import (
+	"github.com/stretchr/testify/assert"
+	vmov1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	corev1 "k8s.io/api/core/v1"
+	"testing"
+)
+
+// TestAddSMTPCredentials Tests mounting the SMTP credentials of email notification channels
+// GIVEN a Slack channel, an email channel with an SMTP credentials Secret and one without
+// WHEN AddSMTPCredentials is called
+// THEN only the credentials Secret is mounted into the OpenSearch container, under the SMTP account name
+func TestAddSMTPCredentials(t *testing.T) {
+	vmi := createTestVMI()
+	vmi.Spec.Elasticsearch.NotificationChannels = []vmov1.NotificationChannel{
+		{Name: "oncall", Type: "slack"},
+		{Name: "mail", Type: "email", Email: &vmov1.EmailChannel{Host: "smtp.example.com", CredentialsSecret: &corev1.LocalObjectReference{Name: "smtp"}}},
+		{Name: "relay", Type: "email", Email: &vmov1.EmailChannel{Host: "relay.example.com"}},
+	}
+	podSpec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "es-master"}}}
+	AddSMTPCredentials(podSpec, vmi)
+	optional := true
+	assert.Equal(t, []corev1.Volume{
+		{
+			Name:         "smtp-credentials-0",
+			VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "smtp", Optional: &optional}},
+		},
+	}, podSpec.Volumes)
+	assert.Equal(t, []corev1.VolumeMount{
+		{Name: "smtp-credentials-0", MountPath: "/etc/opensearch/smtp-accounts/mail-smtp-account", ReadOnly: true},
+	}, podSpec.Containers[0].VolumeMounts)
+}
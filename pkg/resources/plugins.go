@@ -0,0 +1,103 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package resources
+
+import (
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	pluginsVolumeName     = "opensearch-plugins"
+	pluginsInitContainer  = "install-plugins"
+	pluginsInstallPath    = "/plugins"
+	openSearchPluginsPath = "/usr/share/opensearch/plugins"
+)
+
+// The plugins are passed to the script as name or URL and checksum argument pairs, so plugin values are never
+// interpreted by the shell. The plugins of the image are copied along with the installed plugins, since the
+// shared volume hides the plugins directory of the image.
+const installPluginsScript = `set -e
+while [ $# -gt 0 ]; do
+  plugin="$1"; checksum="$2"; shift 2
+  case "$plugin" in
+  http://*|https://*)
+    if [ -z "$checksum" ]; then
+      echo "Plugin $plugin has no checksum"; exit 1
+    fi
+    echo "Installing plugin $plugin"
+    curl -sSfL -o /tmp/plugin.zip "$plugin"
+    echo "$checksum  /tmp/plugin.zip" | sha512sum -c -
+    /usr/share/opensearch/bin/opensearch-plugin install --batch file:///tmp/plugin.zip
+    rm -f /tmp/plugin.zip
+    ;;
+  *)
+    if [ -d "` + openSearchPluginsPath + `/$plugin" ]; then
+      echo "Plugin $plugin is already installed"
+    else
+      echo "Installing plugin $plugin"
+      /usr/share/opensearch/bin/opensearch-plugin install --batch "$plugin"
+    fi
+    ;;
+  esac
+done
+cp -a ` + openSearchPluginsPath + `/. ` + pluginsInstallPath + `/`
+
+//GetOpenSearchPlugins returns the plugins of all OpenSearch nodes followed by the plugins of the node pool
+func GetOpenSearchPlugins(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, node vmcontrollerv1.ElasticsearchNode) []vmcontrollerv1.OpenSearchPlugin {
+	var plugins []vmcontrollerv1.OpenSearchPlugin
+	installed := map[string]bool{}
+	for _, plugin := range append(append([]vmcontrollerv1.OpenSearchPlugin{}, vmo.Spec.Elasticsearch.Plugins...), node.Plugins...) {
+		location := getPluginLocation(plugin)
+		if location == "" || installed[location] {
+			continue
+		}
+		installed[location] = true
+		plugins = append(plugins, plugin)
+	}
+	return plugins
+}
+
+//AddOpenSearchPluginInstaller adds an init container which installs the plugins into a volume shared with the
+// OpenSearch container. The init container uses the OpenSearch image, so the plugins match the OpenSearch version.
+func AddOpenSearchPluginInstaller(podSpec *corev1.PodSpec, plugins []vmcontrollerv1.OpenSearchPlugin) {
+	if len(plugins) < 1 || len(podSpec.Containers) < 1 {
+		return
+	}
+	args := []string{"sh", "-c", installPluginsScript, pluginsInitContainer}
+	for _, plugin := range plugins {
+		args = append(args, getPluginLocation(plugin), plugin.Checksum)
+	}
+	var elasticsearchUID int64 = 1000
+	esContainer := &podSpec.Containers[0]
+	podSpec.InitContainers = append(podSpec.InitContainers, corev1.Container{
+		Name:            pluginsInitContainer,
+		Image:           esContainer.Image,
+		ImagePullPolicy: esContainer.ImagePullPolicy,
+		Command:         args,
+		SecurityContext: &corev1.SecurityContext{RunAsUser: &elasticsearchUID},
+		VolumeMounts: []corev1.VolumeMount{{
+			Name:      pluginsVolumeName,
+			MountPath: pluginsInstallPath,
+		}},
+	})
+	esContainer.VolumeMounts = append(esContainer.VolumeMounts, corev1.VolumeMount{
+		Name:      pluginsVolumeName,
+		MountPath: openSearchPluginsPath,
+	})
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: pluginsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+}
+
+//getPluginLocation returns the URL of the plugin, or its name if it has no URL
+func getPluginLocation(plugin vmcontrollerv1.OpenSearchPlugin) string {
+	if plugin.URL != "" {
+		return plugin.URL
+	}
+	return plugin.Name
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package resources
+
+import (
+	"github.com/stretchr/testify/assert"
+	vmov1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	corev1 "k8s.io/api/core/v1"
+	"strings"
+	"testing"
+)
+
+// TestGetOpenSearchPlugins Tests the plugins installed on a node pool
+// GIVEN plugins for all nodes and for a node pool
+// WHEN GetOpenSearchPlugins is called
+// THEN the plugins of all nodes come first, followed by the plugins of the node pool, without duplicates
+func TestGetOpenSearchPlugins(t *testing.T) {
+	vmi := createTestVMI()
+	vmi.Spec.Elasticsearch.Plugins = []vmov1.OpenSearchPlugin{{Name: "analysis-icu"}, {Name: "analysis-phonetic"}}
+	node := vmov1.ElasticsearchNode{
+		Name: "data",
+		Plugins: []vmov1.OpenSearchPlugin{
+			{Name: "analysis-icu"},
+			{URL: "https://example.com/plugin.zip", Checksum: strings.Repeat("a", 128)},
+		},
+	}
+	assert.Equal(t, []vmov1.OpenSearchPlugin{
+		{Name: "analysis-icu"},
+		{Name: "analysis-phonetic"},
+		{URL: "https://example.com/plugin.zip", Checksum: strings.Repeat("a", 128)},
+	}, GetOpenSearchPlugins(vmi, node))
+	assert.Nil(t, GetOpenSearchPlugins(createTestVMI(), vmov1.ElasticsearchNode{}))
+}
+
+// TestAddOpenSearchPluginInstaller Tests the init container which installs plugins
+// GIVEN an OpenSearch pod spec
+// WHEN AddOpenSearchPluginInstaller is called
+// THEN an init container installs the plugins into a volume mounted over the OpenSearch plugins directory
+func TestAddOpenSearchPluginInstaller(t *testing.T) {
+	newPodSpec := func() *corev1.PodSpec {
+		return &corev1.PodSpec{Containers: []corev1.Container{{Name: "es-master", Image: "opensearch:1.2.3"}}}
+	}
+	podSpec := newPodSpec()
+	AddOpenSearchPluginInstaller(podSpec, nil)
+	assert.Equal(t, newPodSpec(), podSpec)
+
+	checksum := strings.Repeat("a", 128)
+	AddOpenSearchPluginInstaller(podSpec, []vmov1.OpenSearchPlugin{
+		{Name: "analysis-icu"},
+		{Name: "custom", URL: "https://example.com/plugin.zip", Checksum: checksum},
+	})
+	assert.Len(t, podSpec.InitContainers, 1)
+	installer := podSpec.InitContainers[0]
+	assert.Equal(t, "opensearch:1.2.3", installer.Image)
+	assert.Equal(t, []string{"analysis-icu", "", "https://example.com/plugin.zip", checksum}, installer.Command[4:])
+	assert.Equal(t, pluginsInstallPath, installer.VolumeMounts[0].MountPath)
+	assert.Equal(t, []corev1.VolumeMount{{Name: pluginsVolumeName, MountPath: openSearchPluginsPath}}, podSpec.Containers[0].VolumeMounts)
+	assert.NotNil(t, podSpec.Volumes[0].EmptyDir)
+}
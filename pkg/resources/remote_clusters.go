@@ -0,0 +1,108 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package resources
+
+import (
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// TransportTrustedCAsSetting is the OpenSearch setting of the CA certificates the transport layer trusts
+	TransportTrustedCAsSetting = "plugins.security.ssl.transport.pemtrustedcas_filepath"
+
+	remoteClusterCAPath            = "/remote-cluster-cas/"
+	remoteClusterCAFile            = "ca.crt"
+	remoteClusterTruststoreName    = "remote-cluster-truststore"
+	remoteClusterTruststorePath    = "/usr/share/opensearch/config/remote-clusters"
+	remoteClusterTruststoreBundle  = "ca-bundle.pem"
+	remoteClusterTruststoreSetting = "remote-clusters/" + remoteClusterTruststoreBundle
+)
+
+// The bundle holds the CA certificates the image configures for the transport layer, followed by the CA
+// certificates of the remote clusters. The node would no longer trust the other nodes of its own cluster without
+// the CA certificates of the image, so the init container fails when they cannot be found.
+const buildTruststoreScript = `set -e
+config=/usr/share/opensearch/config
+ca=$(sed -n 's/^ *` + TransportTrustedCAsSetting + `: *//p' $config/opensearch.yml | tr -d "\"'")
+case "$ca" in
+  "") echo "No transport CA certificates are configured in opensearch.yml"; exit 1 ;;
+  /*) ;;
+  *) ca="$config/$ca" ;;
+esac
+if [ ! -f "$ca" ]; then
+  echo "Transport CA certificates $ca do not exist"; exit 1
+fi
+bundle=/truststore/` + remoteClusterTruststoreBundle + `
+cat "$ca" > $bundle
+echo >> $bundle
+for cert in ` + remoteClusterCAPath + `*/` + remoteClusterCAFile + `; do
+  if [ -f "$cert" ]; then
+    echo "Adding $cert"
+    cat "$cert" >> $bundle
+    echo >> $bundle
+  fi
+done`
+
+//AddRemoteClusterCAs adds the CA certificates of the remote clusters to the CA certificates the transport layer of
+// the OpenSearch container trusts. An init container, which uses the OpenSearch image, bundles them with the CA
+// certificates of the image in config/remote-clusters, and the transport layer is configured to trust the bundle.
+func AddRemoteClusterCAs(podSpec *corev1.PodSpec, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) {
+	if len(podSpec.Containers) < 1 {
+		return
+	}
+	esContainer := &podSpec.Containers[0]
+	var caMounts []corev1.VolumeMount
+	for _, remote := range vmo.Spec.Elasticsearch.RemoteClusters {
+		if remote.CASecret == nil {
+			continue
+		}
+		volumeName := "remote-cluster-" + remote.Name
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: volumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: remote.CASecret.Name,
+					Items:      []corev1.KeyToPath{{Key: remote.CASecret.Key, Path: remoteClusterCAFile}},
+					Optional:   remote.CASecret.Optional,
+				},
+			},
+		})
+		caMounts = append(caMounts, corev1.VolumeMount{
+			Name:      volumeName,
+			MountPath: remoteClusterCAPath + remote.Name,
+			ReadOnly:  true,
+		})
+	}
+	// the transport layer keeps the CA certificates of the image without remote cluster CA certificates
+	if len(caMounts) < 1 {
+		return
+	}
+
+	var elasticsearchUID int64 = 1000
+	podSpec.InitContainers = append(podSpec.InitContainers, corev1.Container{
+		Name:            remoteClusterTruststoreName,
+		Image:           esContainer.Image,
+		ImagePullPolicy: esContainer.ImagePullPolicy,
+		Command:         []string{"sh", "-c", buildTruststoreScript},
+		SecurityContext: &corev1.SecurityContext{RunAsUser: &elasticsearchUID},
+		VolumeMounts: append(caMounts, corev1.VolumeMount{
+			Name:      remoteClusterTruststoreName,
+			MountPath: "/truststore",
+		}),
+	})
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: remoteClusterTruststoreName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+	esContainer.VolumeMounts = append(esContainer.VolumeMounts, corev1.VolumeMount{
+		Name:      remoteClusterTruststoreName,
+		MountPath: remoteClusterTruststorePath,
+		ReadOnly:  true,
+	})
+	// certificate paths are relative to the OpenSearch config directory
+	esContainer.Env = append(esContainer.Env, corev1.EnvVar{Name: TransportTrustedCAsSetting, Value: remoteClusterTruststoreSetting})
+}
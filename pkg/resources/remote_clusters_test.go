@@ -0,0 +1,59 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package resources
+
+import (
+	"github.com/stretchr/testify/assert"
+	vmov1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	corev1 "k8s.io/api/core/v1"
+	"testing"
+)
+
+// TestAddRemoteClusterCAs Tests trusting the CA certificates of remote clusters
+// GIVEN remote clusters with and without a CA Secret
+// WHEN AddRemoteClusterCAs is called
+// THEN an init container bundles the CA certificates, and the transport layer of the OpenSearch container trusts the bundle
+func TestAddRemoteClusterCAs(t *testing.T) {
+	vmi := createTestVMI()
+	vmi.Spec.Elasticsearch.RemoteClusters = []vmov1.RemoteCluster{
+		{Name: "managed1", CASecret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "managed1-ca"}, Key: "ca.pem"}},
+		{Name: "managed2"},
+	}
+	podSpec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "es-data", Image: "opensearch"}, {Name: "sidecar"}}}
+	AddRemoteClusterCAs(podSpec, vmi)
+	assert.Len(t, podSpec.Volumes, 2)
+	assert.Equal(t, "managed1-ca", podSpec.Volumes[0].Secret.SecretName)
+	assert.Equal(t, []corev1.KeyToPath{{Key: "ca.pem", Path: "ca.crt"}}, podSpec.Volumes[0].Secret.Items)
+	assert.NotNil(t, podSpec.Volumes[1].EmptyDir)
+
+	assert.Len(t, podSpec.InitContainers, 1)
+	initContainer := podSpec.InitContainers[0]
+	assert.Equal(t, "remote-cluster-truststore", initContainer.Name)
+	assert.Equal(t, "opensearch", initContainer.Image)
+	assert.Contains(t, initContainer.Command[2], "plugins.security.ssl.transport.pemtrustedcas_filepath")
+	assert.Equal(t, []corev1.VolumeMount{
+		{Name: "remote-cluster-managed1", MountPath: "/remote-cluster-cas/managed1", ReadOnly: true},
+		{Name: "remote-cluster-truststore", MountPath: "/truststore"},
+	}, initContainer.VolumeMounts)
+
+	assert.Equal(t, []corev1.VolumeMount{{
+		Name:      "remote-cluster-truststore",
+		MountPath: "/usr/share/opensearch/config/remote-clusters",
+		ReadOnly:  true,
+	}}, podSpec.Containers[0].VolumeMounts)
+	assert.Equal(t, []corev1.EnvVar{{Name: "plugins.security.ssl.transport.pemtrustedcas_filepath", Value: "remote-clusters/ca-bundle.pem"}}, podSpec.Containers[0].Env)
+	assert.Empty(t, podSpec.Containers[1].VolumeMounts)
+}
+
+// TestAddRemoteClusterCAsWithoutSecrets Tests that the transport layer is left alone without remote cluster CAs
+// GIVEN remote clusters without a CA Secret
+// WHEN AddRemoteClusterCAs is called
+// THEN the pod spec is not changed
+func TestAddRemoteClusterCAsWithoutSecrets(t *testing.T) {
+	vmi := createTestVMI()
+	vmi.Spec.Elasticsearch.RemoteClusters = []vmov1.RemoteCluster{{Name: "managed1"}}
+	podSpec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "es-data"}}}
+	AddRemoteClusterCAs(podSpec, vmi)
+	assert.Equal(t, &corev1.PodSpec{Containers: []corev1.Container{{Name: "es-data"}}}, podSpec)
+}
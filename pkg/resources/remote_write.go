@@ -0,0 +1,170 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package resources
+
+import (
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// RemoteWriteVolumePrefix is the name prefix of the volumes of the credentials and certificates of remote storages
+	RemoteWriteVolumePrefix = "remote-write-"
+
+	remoteWritePath         = "/etc/prometheus/remote-write/"
+	remoteWritePasswordFile = "password"
+	remoteWriteTokenFile    = "token"
+	remoteWriteCAFile       = "ca.crt"
+	remoteWriteCertFile     = "tls.crt"
+	remoteWriteKeyFile      = "tls.key"
+)
+
+// remoteWriteConfig is a Prometheus remote_write entry
+type remoteWriteConfig struct {
+	URL                 string                `yaml:"url"`
+	Name                string                `yaml:"name"`
+	BasicAuth           *remoteWriteBasicAuth `yaml:"basic_auth,omitempty"`
+	BearerTokenFile     string                `yaml:"bearer_token_file,omitempty"`
+	TLSConfig           *remoteWriteTLSConfig `yaml:"tls_config,omitempty"`
+	QueueConfig         *remoteWriteQueue     `yaml:"queue_config,omitempty"`
+	WriteRelabelConfigs []relabelConfig       `yaml:"write_relabel_configs,omitempty"`
+}
+
+type remoteWriteBasicAuth struct {
+	Username     string `yaml:"username"`
+	PasswordFile string `yaml:"password_file"`
+}
+
+type remoteWriteTLSConfig struct {
+	CAFile             string `yaml:"ca_file,omitempty"`
+	CertFile           string `yaml:"cert_file,omitempty"`
+	KeyFile            string `yaml:"key_file,omitempty"`
+	ServerName         string `yaml:"server_name,omitempty"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify,omitempty"`
+}
+
+type remoteWriteQueue struct {
+	Capacity          int32  `yaml:"capacity,omitempty"`
+	MinShards         int32  `yaml:"min_shards,omitempty"`
+	MaxShards         int32  `yaml:"max_shards,omitempty"`
+	MaxSamplesPerSend int32  `yaml:"max_samples_per_send,omitempty"`
+	BatchSendDeadline string `yaml:"batch_send_deadline,omitempty"`
+	MinBackoff        string `yaml:"min_backoff,omitempty"`
+	MaxBackoff        string `yaml:"max_backoff,omitempty"`
+}
+
+type relabelConfig struct {
+	SourceLabels []string `yaml:"source_labels,omitempty"`
+	Separator    string   `yaml:"separator,omitempty"`
+	Regex        string   `yaml:"regex,omitempty"`
+	Modulus      int64    `yaml:"modulus,omitempty"`
+	TargetLabel  string   `yaml:"target_label,omitempty"`
+	Replacement  string   `yaml:"replacement,omitempty"`
+	Action       string   `yaml:"action,omitempty"`
+}
+
+// GetPrometheusRemoteWriteConfigs returns the remote_write section of the Prometheus configuration. Credentials and
+// certificates are referenced as the files mounted by AddRemoteWriteSecrets.
+func GetPrometheusRemoteWriteConfigs(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) ([]interface{}, error) {
+	var configs []remoteWriteConfig
+	for _, remote := range vmo.Spec.Prometheus.RemoteWrite {
+		dir := remoteWritePath + remote.Name + "/"
+		config := remoteWriteConfig{URL: remote.URL, Name: remote.Name}
+		if remote.BasicAuth != nil {
+			config.BasicAuth = &remoteWriteBasicAuth{Username: remote.BasicAuth.Username, PasswordFile: dir + remoteWritePasswordFile}
+		}
+		if remote.BearerTokenSecret != nil {
+			config.BearerTokenFile = dir + remoteWriteTokenFile
+		}
+		if tls := remote.TLS; tls != nil {
+			config.TLSConfig = &remoteWriteTLSConfig{ServerName: tls.ServerName, InsecureSkipVerify: tls.InsecureSkipVerify}
+			if tls.CASecret != nil {
+				config.TLSConfig.CAFile = dir + remoteWriteCAFile
+			}
+			if tls.CertSecret != nil {
+				config.TLSConfig.CertFile = dir + remoteWriteCertFile
+			}
+			if tls.KeySecret != nil {
+				config.TLSConfig.KeyFile = dir + remoteWriteKeyFile
+			}
+		}
+		if queue := remote.QueueConfig; queue != nil {
+			config.QueueConfig = &remoteWriteQueue{
+				Capacity:          queue.Capacity,
+				MinShards:         queue.MinShards,
+				MaxShards:         queue.MaxShards,
+				MaxSamplesPerSend: queue.MaxSamplesPerSend,
+				BatchSendDeadline: queue.BatchSendDeadline,
+				MinBackoff:        queue.MinBackoff,
+				MaxBackoff:        queue.MaxBackoff,
+			}
+		}
+		for _, relabel := range remote.WriteRelabelConfigs {
+			config.WriteRelabelConfigs = append(config.WriteRelabelConfigs, relabelConfig(relabel))
+		}
+		configs = append(configs, config)
+	}
+	if len(configs) == 0 {
+		return nil, nil
+	}
+	// the configs are returned as plain YAML values, so they compare equal to the configs read from the ConfigMap
+	data, err := yaml.Marshal(configs)
+	if err != nil {
+		return nil, err
+	}
+	var remoteWrite []interface{}
+	if err := yaml.Unmarshal(data, &remoteWrite); err != nil {
+		return nil, err
+	}
+	return remoteWrite, nil
+}
+
+// AddRemoteWriteSecrets mounts the credentials and certificates of each remote storage into the Prometheus container,
+// at /etc/prometheus/remote-write/<name>
+func AddRemoteWriteSecrets(podSpec *corev1.PodSpec, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) {
+	if len(podSpec.Containers) < 1 {
+		return
+	}
+	prometheusContainer := &podSpec.Containers[0]
+	for _, remote := range vmo.Spec.Prometheus.RemoteWrite {
+		var sources []corev1.VolumeProjection
+		addSource := func(secret *corev1.SecretKeySelector, file string) {
+			if secret == nil {
+				return
+			}
+			sources = append(sources, corev1.VolumeProjection{
+				Secret: &corev1.SecretProjection{
+					LocalObjectReference: secret.LocalObjectReference,
+					Items:                []corev1.KeyToPath{{Key: secret.Key, Path: file}},
+					Optional:             secret.Optional,
+				},
+			})
+		}
+		if remote.BasicAuth != nil {
+			addSource(&remote.BasicAuth.PasswordSecret, remoteWritePasswordFile)
+		}
+		addSource(remote.BearerTokenSecret, remoteWriteTokenFile)
+		if remote.TLS != nil {
+			addSource(remote.TLS.CASecret, remoteWriteCAFile)
+			addSource(remote.TLS.CertSecret, remoteWriteCertFile)
+			addSource(remote.TLS.KeySecret, remoteWriteKeyFile)
+		}
+		if len(sources) == 0 {
+			continue
+		}
+		volumeName := RemoteWriteVolumePrefix + remote.Name
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: volumeName,
+			VolumeSource: corev1.VolumeSource{
+				Projected: &corev1.ProjectedVolumeSource{Sources: sources},
+			},
+		})
+		prometheusContainer.VolumeMounts = append(prometheusContainer.VolumeMounts, corev1.VolumeMount{
+			Name:      volumeName,
+			MountPath: remoteWritePath + remote.Name,
+			ReadOnly:  true,
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package resources
+
+import (
+	"github.com/stretchr/testify/assert"
+	vmov1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	"testing"
+)
+
+// TestGetPrometheusRemoteWriteConfigs Tests rendering the remote_write section of the Prometheus configuration
+// GIVEN remote storages with basic auth, a bearer token, TLS, queue tuning and write relabel configs
+// WHEN GetPrometheusRemoteWriteConfigs is called
+// THEN the remote storages are rendered in the Prometheus format, with credentials referenced as files
+func TestGetPrometheusRemoteWriteConfigs(t *testing.T) {
+	vmi := createTestVMI()
+	configs, err := GetPrometheusRemoteWriteConfigs(vmi)
+	assert.NoError(t, err)
+	assert.Nil(t, configs)
+
+	vmi.Spec.Prometheus.RemoteWrite = []vmov1.RemoteWrite{
+		{
+			Name:      "thanos",
+			URL:       "https://thanos.example.com/api/v1/receive",
+			BasicAuth: &vmov1.RemoteWriteBasicAuth{Username: "vmi", PasswordSecret: *secretKey("thanos-auth", "pw")},
+			TLS:       &vmov1.RemoteWriteTLS{CASecret: secretKey("thanos-ca", "ca.pem"), ServerName: "thanos"},
+			QueueConfig: &vmov1.RemoteWriteQueueConfig{
+				MaxShards:         10,
+				BatchSendDeadline: "5s",
+			},
+			WriteRelabelConfigs: []vmov1.RelabelConfig{{SourceLabels: []string{"__name__"}, Regex: "go_.*", Action: "drop"}},
+		},
+		{
+			Name:              "cortex",
+			URL:               "http://cortex:9009/api/v1/push",
+			BearerTokenSecret: secretKey("cortex-token", "token"),
+		},
+	}
+	configs, err = GetPrometheusRemoteWriteConfigs(vmi)
+	assert.NoError(t, err)
+	rendered, err := yaml.Marshal(map[string]interface{}{"remote_write": configs})
+	assert.NoError(t, err)
+	assert.Equal(t, `remote_write:
+- basic_auth:
+    password_file: /etc/prometheus/remote-write/thanos/password
+    username: vmi
+  name: thanos
+  queue_config:
+    batch_send_deadline: 5s
+    max_shards: 10
+  tls_config:
+    ca_file: /etc/prometheus/remote-write/thanos/ca.crt
+    server_name: thanos
+  url: https://thanos.example.com/api/v1/receive
+  write_relabel_configs:
+  - action: drop
+    regex: go_.*
+    source_labels:
+    - __name__
+- bearer_token_file: /etc/prometheus/remote-write/cortex/token
+  name: cortex
+  url: http://cortex:9009/api/v1/push
+`, string(rendered))
+}
+
+// TestAddRemoteWriteSecrets Tests mounting the credentials and certificates of remote storages
+// GIVEN remote storages with and without Secrets
+// WHEN AddRemoteWriteSecrets is called
+// THEN the Secrets of each remote storage are projected into a volume mounted into the Prometheus container
+func TestAddRemoteWriteSecrets(t *testing.T) {
+	vmi := createTestVMI()
+	vmi.Spec.Prometheus.RemoteWrite = []vmov1.RemoteWrite{
+		{
+			Name:      "thanos",
+			URL:       "https://thanos.example.com/api/v1/receive",
+			BasicAuth: &vmov1.RemoteWriteBasicAuth{Username: "vmi", PasswordSecret: *secretKey("thanos-auth", "pw")},
+			TLS: &vmov1.RemoteWriteTLS{
+				CASecret:   secretKey("thanos-ca", "ca.pem"),
+				CertSecret: secretKey("thanos-client", "tls.crt"),
+				KeySecret:  secretKey("thanos-client", "tls.key"),
+			},
+		},
+		{Name: "open", URL: "http://open:9009/api/v1/push"},
+	}
+	podSpec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "prometheus"}, {Name: "config-reloader"}}}
+	AddRemoteWriteSecrets(podSpec, vmi)
+	assert.Len(t, podSpec.Volumes, 1)
+	assert.Equal(t, "remote-write-thanos", podSpec.Volumes[0].Name)
+	var files []string
+	for _, source := range podSpec.Volumes[0].Projected.Sources {
+		files = append(files, source.Secret.Name+"/"+source.Secret.Items[0].Key+":"+source.Secret.Items[0].Path)
+	}
+	assert.Equal(t, []string{"thanos-auth/pw:password", "thanos-ca/ca.pem:ca.crt", "thanos-client/tls.crt:tls.crt", "thanos-client/tls.key:tls.key"}, files)
+	assert.Equal(t, []corev1.VolumeMount{{
+		Name:      "remote-write-thanos",
+		MountPath: "/etc/prometheus/remote-write/thanos",
+		ReadOnly:  true,
+	}}, podSpec.Containers[0].VolumeMounts)
+	assert.Empty(t, podSpec.Containers[1].VolumeMounts)
+}
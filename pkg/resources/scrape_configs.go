@@ -0,0 +1,46 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package resources
+
+import (
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ScrapeConfigSecretsPath is the directory which the Secrets holding additional scrape configs are mounted in
+	ScrapeConfigSecretsPath = "/etc/prometheus/scrape-configs/"
+	// ScrapeConfigSecretVolumePrefix is the name prefix of the volumes of the Secrets holding additional scrape configs
+	ScrapeConfigSecretVolumePrefix = "scrape-config-secret-"
+)
+
+// AddScrapeConfigSecrets mounts each Secret holding additional scrape configs into the Prometheus container, at
+// /etc/prometheus/scrape-configs/<secret name>, so the scrape configs can reference its keys with the *_file fields
+func AddScrapeConfigSecrets(podSpec *corev1.PodSpec, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) {
+	if len(podSpec.Containers) < 1 {
+		return
+	}
+	prometheusContainer := &podSpec.Containers[0]
+	mounted := map[string]bool{}
+	for _, source := range vmo.Spec.Prometheus.AdditionalScrapeConfigs {
+		if source.Secret == nil || mounted[source.Secret.Name] {
+			continue
+		}
+		// Secret names may be longer than volume names, so the volumes are numbered instead
+		volumeName := fmt.Sprintf("%s%d", ScrapeConfigSecretVolumePrefix, len(mounted))
+		mounted[source.Secret.Name] = true
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: volumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: source.Secret.Name, Optional: source.Secret.Optional},
+			},
+		})
+		prometheusContainer.VolumeMounts = append(prometheusContainer.VolumeMounts, corev1.VolumeMount{
+			Name:      volumeName,
+			MountPath: ScrapeConfigSecretsPath + source.Secret.Name,
+			ReadOnly:  true,
+		})
+	}
+}
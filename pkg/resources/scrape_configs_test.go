@@ -0,0 +1,49 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package resources
+
+import (
+	"github.com/stretchr/testify/assert"
+	vmov1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	corev1 "k8s.io/api/core/v1"
+	"testing"
+)
+
+func secretKey(name, key string) *corev1.SecretKeySelector {
+	return &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: key}
+}
+
+// TestAddScrapeConfigSecrets Tests mounting the Secrets which hold additional scrape configs
+// GIVEN additional scrape configs in two keys of a Secret, in another optional Secret and in a ConfigMap
+// WHEN AddScrapeConfigSecrets is called
+// THEN each Secret is mounted once into the Prometheus container, and the ConfigMap is not mounted
+func TestAddScrapeConfigSecrets(t *testing.T) {
+	vmi := createTestVMI()
+	optional := true
+	optionalSecret := secretKey("optional-jobs", "jobs.yaml")
+	optionalSecret.Optional = &optional
+	vmi.Spec.Prometheus.AdditionalScrapeConfigs = []vmov1.ScrapeConfigSource{
+		{Secret: secretKey("jobs", "a.yaml")},
+		{ConfigMap: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "jobs"}, Key: "c.yaml"}},
+		{Secret: secretKey("jobs", "b.yaml")},
+		{Secret: optionalSecret},
+	}
+	podSpec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "prometheus"}, {Name: "config-reloader"}}}
+	AddScrapeConfigSecrets(podSpec, vmi)
+	assert.Equal(t, []corev1.Volume{
+		{
+			Name:         "scrape-config-secret-0",
+			VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "jobs"}},
+		},
+		{
+			Name:         "scrape-config-secret-1",
+			VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "optional-jobs", Optional: &optional}},
+		},
+	}, podSpec.Volumes)
+	assert.Equal(t, []corev1.VolumeMount{
+		{Name: "scrape-config-secret-0", MountPath: "/etc/prometheus/scrape-configs/jobs", ReadOnly: true},
+		{Name: "scrape-config-secret-1", MountPath: "/etc/prometheus/scrape-configs/optional-jobs", ReadOnly: true},
+	}, podSpec.Containers[0].VolumeMounts)
+	assert.Empty(t, podSpec.Containers[1].VolumeMounts)
+}
@@ -78,9 +78,8 @@ func CreatePlan(log vzlog.VerrazzanoLogger, existingList, expectedList []*appsv1
 }
 
 //createStatefulSetMapping creates a mapping of statefulset and checks if the plan would scale the cluster to an inconsistent state.
-// A cluster cannot be scaled down if:
-// - if has less than minClusterSize master replicas
-// - the scale down would remove half or more of the master replicas
+// A cluster cannot be scaled down if it would have less than minClusterSize master replicas.
+// Master nodes are removed one at a time, after being excluded from the voting configuration, so the cluster keeps its quorum.
 func createStatefulSetMapping(existingList, expectedList []*appsv1.StatefulSet) *statefulSetMapping {
 	mapping := &statefulSetMapping{
 		existing: map[string]*appsv1.StatefulSet{},
@@ -103,8 +102,7 @@ func createStatefulSetMapping(existingList, expectedList []*appsv1.StatefulSet)
 		// scale down is allowed
 		mapping.isScaleDownAllowed = true
 	} else {
-		mapping.isScaleDownAllowed = expectedSize >= minClusterSize &&
-			expectedSize > existingSize/2
+		mapping.isScaleDownAllowed = expectedSize >= minClusterSize
 	}
 	mapping.existingSize = existingSize
 	mapping.expectedSize = expectedSize
@@ -160,6 +160,37 @@ func TestCreatePlan(t *testing.T) {
 			},
 			&StatefulSetPlan{ExistingCluster: true},
 		},
+		{
+			"update when scaling down to at least the minimum cluster size",
+			[]*appsv1.StatefulSet{
+				createTestSTS("foo", 5),
+			},
+			[]*appsv1.StatefulSet{
+				createTestSTS("foo", 3),
+			},
+			&StatefulSetPlan{
+				ExistingCluster: true,
+				Update: []*appsv1.StatefulSet{
+					createTestSTS("foo", 3),
+				},
+			},
+		},
+		{
+			"delete when half or more of the master nodes are removed, and the minimum cluster size remains",
+			[]*appsv1.StatefulSet{
+				createTestSTS("foo", 3),
+				createTestSTS("bar", 3),
+			},
+			[]*appsv1.StatefulSet{
+				createTestSTS("foo", 3),
+			},
+			&StatefulSetPlan{
+				ExistingCluster: true,
+				Delete: []*appsv1.StatefulSet{
+					createTestSTS("bar", 3),
+				},
+			},
+		},
 		{
 			"scaling should be allowed on single node clusters",
 			[]*appsv1.StatefulSet{
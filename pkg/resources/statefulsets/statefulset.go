@@ -35,6 +35,22 @@ func New(log vzlog.VerrazzanoLogger, vmo *vmcontrollerv1.VerrazzanoMonitoringIns
 	return statefulSets, nil
 }
 
+//IsOpenSearchDataStatefulSet checks template labels to see if a given statefulset runs OpenSearch data nodes
+func IsOpenSearchDataStatefulSet(vmoName string, statefulSet *appsv1.StatefulSet) bool {
+	return hasOpenSearchRole(vmoName, statefulSet, nodes.RoleData)
+}
+
+//IsOpenSearchMasterStatefulSet checks template labels to see if a given statefulset runs OpenSearch master eligible nodes
+func IsOpenSearchMasterStatefulSet(vmoName string, statefulSet *appsv1.StatefulSet) bool {
+	return hasOpenSearchRole(vmoName, statefulSet, nodes.RoleMaster)
+}
+
+func hasOpenSearchRole(vmoName string, statefulSet *appsv1.StatefulSet, roleLabel string) bool {
+	labels := statefulSet.Spec.Template.Labels
+	return labels[constants.ServiceAppLabel] == vmoName+"-"+config.ElasticsearchMaster.Name &&
+		labels[roleLabel] == nodes.RoleAssigned
+}
+
 func createOpenSearchStatefulSets(log vzlog.VerrazzanoLogger, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, storageClass *storagev1.StorageClass, initialMasterNodes string) []*appsv1.StatefulSet {
 	var statefulSets []*appsv1.StatefulSet
 	for _, node := range nodes.MasterNodes(vmo) {
@@ -59,6 +75,8 @@ func createOpenSearchStatefulSet(log vzlog.VerrazzanoLogger, vmo *vmcontrollerv1
 	statefulSet.Spec.Template.Labels[constants.NodeGroupLabel] = node.Name
 
 	statefulSet.Spec.Replicas = resources.NewVal(node.Replicas)
+	// Pods are restarted by the operator, so shard allocation can be managed around each restart
+	statefulSet.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}
 	statefulSet.Spec.Template.Spec.Affinity = resources.CreateZoneAntiAffinityElement(vmo.Name, config.ElasticsearchMaster.Name)
 
 	var elasticsearchUID int64 = 1000
@@ -82,7 +100,7 @@ if [ "${OBJECT_STORE_SECRET_KEY_ID:-}" ]; then
     echo "Updating object store secret key..."
 	echo $OBJECT_STORE_SECRET_KEY_ID | /usr/share/opensearch/bin/opensearch-keystore add --stdin --force s3.client.default.secret_key;
 fi
-/usr/local/bin/docker-entrypoint.sh`,
+` + resources.SMTPCredentialsScript + `/usr/local/bin/docker-entrypoint.sh`,
 	}
 	var envVars = []corev1.EnvVar{
 		{
@@ -159,6 +177,7 @@ fi
 			envVars = append(envVars, corev1.EnvVar{Name: constants.ClusterInitialMasterNodes, Value: initialMasterNodes})
 		}
 	}
+	envVars = append(envVars, nodes.GetNodeAttributeEnvVars(&node)...)
 	esMasterContainer.Env = envVars
 
 	basicAuthParams := ""
@@ -272,6 +291,10 @@ fi`,
 		}
 	}
 
+	// Install the plugins before OpenSearch starts
+	resources.AddOpenSearchPluginInstaller(&statefulSet.Spec.Template.Spec, resources.GetOpenSearchPlugins(vmo, node))
+	resources.AddRemoteClusterCAs(&statefulSet.Spec.Template.Spec, vmo)
+
 	// add istio annotations required for inter component communication
 	if statefulSet.Spec.Template.Annotations == nil {
 		statefulSet.Spec.Template.Annotations = make(map[string]string)
@@ -281,6 +304,9 @@ fi`,
 
 	// set Node Role labels for role based selectors
 	nodes.SetNodeRoleLabels(&node, statefulSet.Spec.Template.Labels)
+
+	// mount the SMTP credentials of email notification channels, for the keystore
+	resources.AddSMTPCredentials(&statefulSet.Spec.Template.Spec, vmo)
 	return statefulSet
 }
 
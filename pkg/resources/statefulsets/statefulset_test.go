@@ -395,4 +395,55 @@ func TestCreateMultiMasterWithStorage(t *testing.T) {
 	assert.Equal(t, nodes.RoleAssigned, sts.Spec.Template.Labels[nodes.RoleData])
 	assert.Equal(t, nodes.RoleAssigned, sts.Spec.Template.Labels[nodes.RoleIngest])
 	assert.Equal(t, constants.ComponentOpenSearchValue, sts.Spec.Template.Labels[constants.ComponentLabel])
+	// No plugins are installed
+	assert.Equal(t, 1, len(sts.Spec.Template.Spec.InitContainers))
+
+	// Plugins are installed by an init container
+	vmi.Spec.Elasticsearch.Plugins = []vmcontrollerv1.OpenSearchPlugin{{Name: "analysis-icu"}}
+	result, err = New(vzlog.DefaultLogger(), vmi, &storageClass, initialMasterNodes)
+	assert.NoError(t, err)
+	initContainers := result[0].Spec.Template.Spec.InitContainers
+	assert.Equal(t, 2, len(initContainers))
+	assert.Equal(t, result[0].Spec.Template.Spec.Containers[0].Image, initContainers[1].Image)
+	assert.Contains(t, initContainers[1].Command, "analysis-icu")
+}
+
+func TestIsOpenSearchStatefulSetRoles(t *testing.T) {
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Elasticsearch: vmcontrollerv1.Elasticsearch{
+				Enabled: true,
+				Nodes: []vmcontrollerv1.ElasticsearchNode{
+					{
+						Name:     "es-master",
+						Replicas: 3,
+						Roles:    []vmcontrollerv1.NodeRole{vmcontrollerv1.MasterRole},
+					},
+					{
+						Name:     "es-master-data",
+						Replicas: 3,
+						Roles:    []vmcontrollerv1.NodeRole{vmcontrollerv1.MasterRole, vmcontrollerv1.DataRole},
+					},
+				},
+			},
+			AlertManager: vmcontrollerv1.AlertManager{
+				Enabled: true,
+			},
+		},
+	}
+	statefulSets, err := New(vzlog.DefaultLogger(), vmo, &storageClass, "")
+	assert.NoError(t, err)
+	assert.Len(t, statefulSets, 3)
+	masters := 0
+	for _, sts := range statefulSets {
+		assert.Equal(t, sts.Name == resources.GetMetaName(vmo.Name, "es-master-data"), IsOpenSearchDataStatefulSet(vmo.Name, sts), sts.Name)
+		if IsOpenSearchMasterStatefulSet(vmo.Name, sts) {
+			masters++
+			assert.Equal(t, appsv1.OnDeleteStatefulSetStrategyType, sts.Spec.UpdateStrategy.Type)
+		}
+	}
+	assert.Equal(t, 2, masters)
 }
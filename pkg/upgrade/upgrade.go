@@ -4,7 +4,6 @@
 package upgrade
 
 import (
-	"errors"
 	"fmt"
 	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
@@ -14,89 +13,47 @@ import (
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
 )
 
-type Monitor struct {
-	running bool
-	ch      chan error
-}
+// Monitor migrates old indices to data streams. The migration progress is kept in the VMI status,
+// so a migration interrupted by an operator restart is resumed.
+type Monitor struct{}
 
+//MigrateOldIndices moves the migration of old indices a step forward. Progress and failed migrations are reported in
+// the VMI status, so nil is returned while the reindex is in progress, and before the cluster is reachable.
 func (m *Monitor) MigrateOldIndices(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance,
-	o *opensearch.OSClient, od *dashboards.OSDashboardsClient) error {
-	// if not already migrating, start migrating indices
-	if !m.running {
-		m.run(log, vmi, o, od)
+	o opensearch.OpenSearch, od *dashboards.OSDashboardsClient) error {
+	if !vmi.Spec.Elasticsearch.Enabled {
+		vmi.Status.Elasticsearch.IndexMigrations = nil
 		return nil
 	}
+	openSearchEndpoint := resources.GetOpenSearchHTTPEndpoint(vmi)
+	// Make sure that the data stream template is created before re-indexing
+	if len(vmi.Status.Elasticsearch.IndexMigrations) == 0 {
+		exists, err := o.DataStreamExists(openSearchEndpoint, config.DataStreamName())
+		if err != nil {
+			// the cluster may not be reachable yet, e.g., on a fresh install
+			log.Debugf("Unable to verify existence of data stream: %v", err)
+			return nil
+		}
+		// If the migration data stream exists, the old backing indices must be reindexed
+		if !exists {
+			return nil
+		}
+	}
 
-	complete, err := m.isUpgradeComplete()
-	// an error occurred during reindex
+	// During upgrade, reindex and delete old indices
+	complete, err := o.MigrateIndicesToDataStreams(log, vmi, openSearchEndpoint)
 	if err != nil {
-		// reset the monitor so we can retry the upgrade
-		m.reset()
 		return err
 	}
 	// reindex is still in progress
 	if !complete {
-		return errors.New("reindex in progress")
-	}
-	// reindex was successful
-	m.reset()
-	return nil
-}
-
-func (m *Monitor) isUpgradeComplete() (bool, error) {
-	if m.ch == nil {
-		return false, nil
+		log.Progressf("Reindex of old indices to data streams is in progress")
+		return nil
 	}
-
-	select {
-	case e := <-m.ch:
-		return true, e
-	default:
-		return false, nil
+	// Update if any index patterns configured for old indices in OpenSearch Dashboards
+	if err := od.UpdatePatterns(log, vmi); err != nil {
+		return fmt.Errorf("error in updating index patterns"+
+			" in OpenSearch Dashboards: %v", err)
 	}
-}
-
-func (m *Monitor) reset() {
-	m.running = false
-	close(m.ch)
-}
-
-func (m *Monitor) run(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance,
-	o *opensearch.OSClient, od *dashboards.OSDashboardsClient) {
-	ch := make(chan error)
-	m.running = true
-	m.ch = ch
-	// configuration is done asynchronously, as this does not need to be blocking
-	go func() {
-		if !vmi.Spec.Elasticsearch.Enabled {
-			ch <- nil
-			return
-		}
-
-		openSearchEndpoint := resources.GetOpenSearchHTTPEndpoint(vmi)
-		// Make sure that the data stream template is created before re-indexing
-		exists, err := o.DataStreamExists(openSearchEndpoint, config.DataStreamName())
-		if err != nil {
-			ch <- fmt.Errorf("failed to verify existence of data stream: %v", err)
-			return
-		}
-
-		// If the migration data stream exists, the old backing indices must be reindexed
-		if exists {
-			// During upgrade, reindex and delete old indices
-			if err := o.MigrateIndicesToDataStreams(log, vmi, openSearchEndpoint); err != nil {
-				ch <- err
-				return
-			}
-
-			// Update if any index patterns configured for old indices in OpenSearch Dashboards
-			err = od.UpdatePatterns(log, vmi)
-			if err != nil {
-				ch <- fmt.Errorf("error in updating index patterns"+
-					" in OpenSearch Dashboards: %v", err)
-				return
-			}
-		}
-		ch <- nil
-	}()
+	return nil
 }
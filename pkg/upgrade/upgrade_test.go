@@ -0,0 +1,118 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package upgrade
+
+import (
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch/opensearchtest"
+	dashboards "github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch_dashboards"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+// maxReconciles bounds the number of reconciles a migration may take
+const maxReconciles = 10
+
+func createMigrationVMI() *vmcontrollerv1.VerrazzanoMonitoringInstance {
+	return &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "system",
+			Namespace: "verrazzano-system",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Elasticsearch: vmcontrollerv1.Elasticsearch{
+				Enabled: true,
+			},
+		},
+	}
+}
+
+func newFakeClients(fake *opensearchtest.FakeOpenSearch) (opensearch.OpenSearch, *dashboards.OSDashboardsClient) {
+	o := opensearch.NewOSClient()
+	o.DoHTTP = fake.DoHTTP
+	return o, dashboards.NewOSDashboardsClient()
+}
+
+// TestMigrateOldIndices Tests migrating old indices to data streams over several reconciles
+// GIVEN a fake OpenSearch cluster with old Verrazzano indices and the Verrazzano data streams
+// WHEN MigrateOldIndices is called until the migration completes
+// THEN the documents of the old indices are reindexed to the data streams, the old indices are deleted,
+// AND later reconciles and ISM configuration apply to the data streams
+func TestMigrateOldIndices(t *testing.T) {
+	fake := opensearchtest.NewFakeOpenSearch()
+	defer fake.Close()
+	fake.AddDataStream("verrazzano-system")
+	fake.AddDataStreamTemplate("verrazzano-application*")
+	fake.AddIndex("verrazzano-namespace-verrazzano-system", 3)
+	fake.AddIndex("verrazzano-systemd-journal", 4)
+	fake.AddIndex("verrazzano-namespace-bobs-books", 2)
+	fake.AddIndex(".kibana_1", 1)
+	o, od := newFakeClients(fake)
+	log := vzlog.DefaultLogger()
+	m := &Monitor{}
+
+	vmi := createMigrationVMI()
+	for i := 0; i < maxReconciles; i++ {
+		assert.NoError(t, m.MigrateOldIndices(log, vmi, o, od))
+		if i > 0 && len(vmi.Status.Elasticsearch.IndexMigrations) == 0 {
+			break
+		}
+	}
+	assert.Empty(t, vmi.Status.Elasticsearch.IndexMigrations)
+	assert.Equal(t, map[string]int{
+		"verrazzano-system":                 7,
+		"verrazzano-application-bobs-books": 2,
+	}, fake.DataStreams())
+	assert.Equal(t, map[string]int{
+		".kibana_1":                    1,
+		".ds-verrazzano-system-000001": 7,
+		".ds-verrazzano-application-bobs-books-000001": 2,
+	}, fake.Indices())
+
+	// a completed migration has nothing to do
+	assert.NoError(t, m.MigrateOldIndices(log, vmi, o, od))
+	assert.Len(t, fake.Indices(), 3)
+
+	vmi.Spec.Elasticsearch.Policies = []vmcontrollerv1.IndexManagementPolicy{{
+		PolicyName:   "verrazzano-application",
+		IndexPattern: "verrazzano-application*",
+	}}
+	assert.NoError(t, <-o.ConfigureISM(vmi))
+	assert.Equal(t, map[string]string{
+		".ds-verrazzano-application-bobs-books-000001": "verrazzano-application",
+	}, fake.ManagedIndices())
+}
+
+// TestMigrateOldIndicesWithoutDataStream Tests that indices are not migrated before the data stream exists
+// GIVEN a fake OpenSearch cluster with old Verrazzano indices but no Verrazzano data stream
+// WHEN MigrateOldIndices is called
+// THEN no index is migrated
+func TestMigrateOldIndicesWithoutDataStream(t *testing.T) {
+	fake := opensearchtest.NewFakeOpenSearch()
+	defer fake.Close()
+	fake.AddIndex("verrazzano-namespace-verrazzano-system", 3)
+	o, od := newFakeClients(fake)
+
+	vmi := createMigrationVMI()
+	assert.NoError(t, (&Monitor{}).MigrateOldIndices(vzlog.DefaultLogger(), vmi, o, od))
+	assert.Empty(t, vmi.Status.Elasticsearch.IndexMigrations)
+	assert.Equal(t, map[string]int{"verrazzano-namespace-verrazzano-system": 3}, fake.Indices())
+}
+
+// TestMigrateOldIndicesUnreachable Tests that an unreachable cluster does not fail the migration
+// GIVEN a VMI whose OpenSearch cluster is not reachable yet
+// WHEN MigrateOldIndices is called
+// THEN no error is returned, and no migration is started
+func TestMigrateOldIndicesUnreachable(t *testing.T) {
+	fake := opensearchtest.NewFakeOpenSearch()
+	o, od := newFakeClients(fake)
+	fake.Close()
+
+	vmi := createMigrationVMI()
+	assert.NoError(t, (&Monitor{}).MigrateOldIndices(vzlog.DefaultLogger(), vmi, o, od))
+	assert.Empty(t, vmi.Status.Elasticsearch.IndexMigrations)
+}
@@ -0,0 +1,31 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+)
+
+//getNotificationChannelURLs reads the Slack and webhook URLs of the notification channels from their Secrets.
+// Channels whose optional URL Secret or key does not exist are left out.
+func getNotificationChannelURLs(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) (map[string]string, error) {
+	urls := map[string]string{}
+	if !vmo.Spec.Elasticsearch.Enabled {
+		return urls, nil
+	}
+	for _, channel := range vmo.Spec.Elasticsearch.NotificationChannels {
+		if channel.URLSecret == nil {
+			continue
+		}
+		secret, url, err := getSecretKeyValue(controller, vmo.Namespace, *channel.URLSecret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the URL of notification channel %s: %v", channel.Name, err)
+		}
+		if secret != nil {
+			urls[channel.Name] = string(url)
+		}
+	}
+	return urls, nil
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+// TestGetNotificationChannelURLs Tests reading the URLs of notification channels
+// GIVEN Slack, webhook and email notification channels
+// WHEN getNotificationChannelURLs is called
+// THEN the URLs of the channels with URL Secrets are returned
+func TestGetNotificationChannelURLs(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "alerting", Namespace: testvmo.Namespace},
+		Data:       map[string][]byte{"slack": []byte("https://hooks.slack.com/oncall")},
+	})
+	c := &Controller{secretLister: &simpleSecretLister{kubeClient: client}}
+	urlSecret := func(key string) *corev1.SecretKeySelector {
+		return &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "alerting"}, Key: key}
+	}
+	vmo := testvmo.DeepCopy()
+	vmo.Spec.Elasticsearch.Enabled = true
+	vmo.Spec.Elasticsearch.NotificationChannels = []vmcontrollerv1.NotificationChannel{
+		{Name: "oncall", Type: "slack", URLSecret: urlSecret("slack")},
+		{Name: "mail", Type: "email", Email: &vmcontrollerv1.EmailChannel{Host: "smtp.example.com"}},
+	}
+	urls, err := getNotificationChannelURLs(c, vmo)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"oncall": "https://hooks.slack.com/oncall"}, urls)
+
+	vmo.Spec.Elasticsearch.NotificationChannels = append(vmo.Spec.Elasticsearch.NotificationChannels,
+		vmcontrollerv1.NotificationChannel{Name: "hook", Type: "webhook", URLSecret: urlSecret("webhook")})
+	_, err = getNotificationChannelURLs(c, vmo)
+	assert.Error(t, err)
+}
@@ -98,10 +98,16 @@ func CreateConfigmaps(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMoni
 		vzClusterName, _ = GetClusterNameFromSecret(controller, vmo.Namespace)
 	}
 
-	err = reconcilePrometheusConfigMap(controller, vmo, vmo.Spec.Prometheus.ConfigMap, map[string]string{"prometheus.yml": resources.GetDefaultPrometheusConfiguration(vmo, vzClusterName)})
+	prometheusConfig, jobs, droppedJobs := mergeAdditionalScrapeConfigs(controller, vmo, resources.GetDefaultPrometheusConfiguration(vmo, vzClusterName))
+	prometheusConfig, err = addRemoteWriteConfigs(controller, vmo, prometheusConfig)
 	if err != nil {
 		return err
 	}
+	err = reconcilePrometheusConfigMap(controller, vmo, vmo.Spec.Prometheus.ConfigMap, map[string]string{"prometheus.yml": prometheusConfig}, droppedJobs)
+	if err != nil {
+		return err
+	}
+	vmo.Status.Prometheus.AdditionalScrapeConfigJobs = jobs
 	configMaps = append(configMaps, vmo.Spec.Prometheus.ConfigMap)
 
 	//configmap for prometheus config versions
@@ -113,6 +119,20 @@ func CreateConfigmaps(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMoni
 	}
 	configMaps = append(configMaps, vmo.Spec.Prometheus.VersionsConfigMap)
 
+	//configmap for opensearch dashboards config, only managed while the VMI has settings
+	if resources.IsOpenSearchDashboardsConfigured(vmo) {
+		if err := reconcileOpenSearchDashboardsConfigMap(controller, vmo); err != nil {
+			controller.log.Errorf("Failed to create OpenSearch Dashboards configmap for VMI %s: %v", vmo.Name, err)
+			return err
+		}
+		configMaps = append(configMaps, resources.GetOpenSearchDashboardsConfigMapName(vmo))
+	}
+
+	// the index migration report is kept while the migration is a dry run
+	if isIndexMigrationDryRun(vmo) {
+		configMaps = append(configMaps, getMigrationReportConfigMapName(vmo))
+	}
+
 	// Delete configmaps that shouldn't exist
 	controller.log.Debugf("Deleting unwanted ConfigMaps for VMI %s/%s", vmo.Namespace, vmo.Name)
 	selector := labels.SelectorFromSet(map[string]string{constants.VMOLabel: vmo.Name})
@@ -243,8 +263,9 @@ func getConfigMap(controller *Controller, namespace string, configmapName string
 	return configMap, nil
 }
 
-// reconcilePrometheusConfigMap reconciles the prometheus configmap data between restarts/upgrades
-func reconcilePrometheusConfigMap(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, configmap string, data map[string]string) error {
+// reconcilePrometheusConfigMap reconciles the prometheus configmap data between restarts/upgrades. Scrape configs of the
+// dropped jobs are removed, all other scrape configs which are not part of the new config are preserved.
+func reconcilePrometheusConfigMap(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, configmap string, data map[string]string, droppedJobs []string) error {
 	existingConfig, err := getConfigMap(controller, vmo.Namespace, configmap)
 	if err != nil {
 		controller.log.Errorf("Failed to get configmap %s%s: %v", vmo.Namespace, configmap, err)
@@ -302,8 +323,10 @@ func reconcilePrometheusConfigMap(controller *Controller, vmo *vmcontrollerv1.Ve
 							break
 						}
 					}
-					// Preserve all scrape configs that are not part of default config
-					if !found {
+					// Preserve all scrape configs that are not part of default config, unless their job was dropped
+					if !found && isDroppedJob(existingScrapeConfig, droppedJobs) {
+						scrapeConfigChanged = true
+					} else if !found {
 						scrapeConfigs = append(scrapeConfigs, existingScrapeConfig)
 					}
 				}
@@ -326,8 +349,9 @@ func reconcilePrometheusConfigMap(controller *Controller, vmo *vmcontrollerv1.Ve
 				}
 			}
 
-			// Update the configmap only when there is a change to scrap config data
-			if len(scrapeConfigs) > 0 && (len(scrapeConfigs) != len(existingScrapeConfigs) || scrapeConfigChanged) {
+			// Update the configmap only when there is a change to scrap config or remote write data
+			remoteWriteChanged := !reflect.DeepEqual(newConfig[remoteWriteKey], existingConfigYaml[remoteWriteKey])
+			if len(scrapeConfigs) > 0 && (len(scrapeConfigs) != len(existingScrapeConfigs) || scrapeConfigChanged || remoteWriteChanged) {
 				newConfig["scrape_configs"] = scrapeConfigs
 				newConfigYaml, err := yaml.Marshal(&newConfig)
 				if err != nil {
@@ -348,3 +372,28 @@ func reconcilePrometheusConfigMap(controller *Controller, vmo *vmcontrollerv1.Ve
 	}
 	return nil
 }
+
+// isDroppedJob returns true if the scrape config belongs to one of the dropped jobs
+func isDroppedJob(scrapeConfig map[interface{}]interface{}, droppedJobs []string) bool {
+	job, ok := scrapeConfig["job_name"].(string)
+	return ok && contains(droppedJobs, job)
+}
+
+// reconcileOpenSearchDashboardsConfigMap creates or updates the ConfigMap holding opensearch_dashboards.yml
+func reconcileOpenSearchDashboardsConfigMap(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	name := resources.GetOpenSearchDashboardsConfigMapName(vmo)
+	configMap := configmaps.NewConfig(vmo, name, map[string]string{constants.OpenSearchDashboardsYaml: resources.GetOpenSearchDashboardsConfig(vmo)})
+	existingConfigMap, err := getConfigMap(controller, vmo.Namespace, name)
+	if err != nil {
+		return err
+	}
+	if existingConfigMap == nil {
+		_, err = controller.kubeclientset.CoreV1().ConfigMaps(vmo.Namespace).Create(context.TODO(), configMap, metav1.CreateOptions{})
+		return err
+	}
+	if !reflect.DeepEqual(existingConfigMap.Data, configMap.Data) {
+		controller.log.Oncef("Updating OpenSearch Dashboards configmap %s", name)
+		_, err = controller.kubeclientset.CoreV1().ConfigMaps(vmo.Namespace).Update(context.TODO(), configMap, metav1.UpdateOptions{})
+	}
+	return err
+}
@@ -296,3 +296,40 @@ func TestReconcileConfigmapsNewScrapeConfigsIntactAfterReconcile(t *testing.T) {
 	}
 	assert.True(t, found)
 }
+
+// TestCreateOpenSearchDashboardsConfigMap tests that the OpenSearch Dashboards ConfigMap follows the VMI settings
+// GIVEN a VMI with OpenSearch Dashboards settings
+// WHEN CreateConfigmaps is called as the settings change, and once they are removed
+// THEN the ConfigMap holds the rendered settings, and is deleted without settings
+func TestCreateOpenSearchDashboardsConfigMap(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	controller := &Controller{
+		kubeclientset:   client,
+		configMapLister: &simpleConfigMapLister{kubeClient: client},
+		secretLister:    &simpleSecretLister{kubeClient: client},
+		log:             vzlog.DefaultLogger(),
+	}
+	vmo := &vmctl.VerrazzanoMonitoringInstance{}
+	vmo.Name = constants.VMODefaultName
+	vmo.Namespace = constants.VerrazzanoSystemNamespace
+	vmo.Spec.Prometheus.ConfigMap = "myPrometheusConfigMap"
+	vmo.Spec.Kibana.Enabled = true
+	vmo.Spec.Kibana.Config = map[string]string{"server.basePath": "/dashboards"}
+	name := "vmi-system-osd-config"
+
+	assert.NoError(t, CreateConfigmaps(controller, vmo))
+	cm, err := client.CoreV1().ConfigMaps(vmo.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Contains(t, cm.Data[constants.OpenSearchDashboardsYaml], "server.basePath: /dashboards")
+
+	vmo.Spec.Kibana.Config["server.basePath"] = "/osd"
+	assert.NoError(t, CreateConfigmaps(controller, vmo))
+	cm, err = client.CoreV1().ConfigMaps(vmo.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Contains(t, cm.Data[constants.OpenSearchDashboardsYaml], "server.basePath: /osd")
+
+	vmo.Spec.Kibana.Config = nil
+	assert.NoError(t, CreateConfigmaps(controller, vmo))
+	_, err = client.CoreV1().ConfigMaps(vmo.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	assert.Error(t, err)
+}
@@ -18,8 +18,10 @@ import (
 	listers "github.com/verrazzano/verrazzano-monitoring-operator/pkg/client/listers/vmcontroller/v1"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/metrics"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch"
 	dashboards "github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch_dashboards"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/deployments"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/signals"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/upgrade"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
@@ -112,12 +114,15 @@ type Controller struct {
 	log vzlog.VerrazzanoLogger
 
 	// OpenSearch Client
-	osClient *opensearch.OSClient
+	osClient opensearch.OpenSearch
 
 	// OpenSearchDashboards Client
 	osDashboardsClient *dashboards.OSDashboardsClient
 
 	indexUpgradeMonitor *upgrade.Monitor
+
+	// Exports OpenSearch statistics as operator metrics
+	openSearchCollector *metrics.OpenSearchCollector
 }
 
 // ClusterInfo has info like ContainerRuntime and managed cluster name
@@ -254,6 +259,7 @@ func NewController(namespace string, configmapName string, buildVersion string,
 		osClient:              osClient,
 		osDashboardsClient:    osDashboardsClient,
 		indexUpgradeMonitor:   &upgrade.Monitor{},
+		openSearchCollector:   metrics.NewOpenSearchCollector(),
 	}
 
 	zap.S().Infow("Setting up event handlers")
@@ -321,6 +327,8 @@ func (c *Controller) Run(threadiness int) error {
 		go wait.Until(c.runWorker, time.Second, c.stopCh)
 	}
 
+	go wait.Until(c.collectOpenSearchStats, openSearchStatsInterval, c.stopCh)
+
 	zap.S().Infow("Started workers")
 	<-c.stopCh
 	zap.S().Infow("Shutting down workers")
@@ -456,13 +464,44 @@ func (c *Controller) syncHandlerStandardMode(vmo *vmcontrollerv1.VerrazzanoMonit
 	 **********************/
 	ismChannel := c.osClient.ConfigureISM(vmo)
 
+	/*********************
+	 * Configure OpenSearch Security and OpenSearch Dashboards Tenants
+	 **********************/
+	securityChannel, tenantsChannel := configureSecurityAndTenants(c, vmo)
+
+	/*********************
+	 * Configure OpenSearch Alerting
+	 **********************/
+	var alertingChannel chan error
+	alertingConfigured := opensearch.IsAlertingConfigured(vmo)
+	channelURLs, err := getNotificationChannelURLs(c, vmo)
+	if err != nil {
+		alertingChannel = errorChannel(fmt.Errorf("failed to get OpenSearch notification channel URLs: %v", err))
+	} else {
+		alertingChannel = c.osClient.ConfigureAlerting(c.log, vmo, channelURLs)
+	}
+
+	/*********************
+	 * Configure Cross-Cluster Index Patterns
+	 **********************/
+	crossClusterChannel := c.osDashboardsClient.ConfigureCrossClusterIndexPatterns(c.log, vmo, vmo.Status.Elasticsearch.CrossClusterIndexPatterns)
+
+	/*********************
+	 * Configure Default Index Patterns
+	 **********************/
+	defaultIndexPatternsChannel := c.osDashboardsClient.ConfigureDefaultIndexPatterns(c.log, vmo)
+
 	/********************************************
 	 * Migrate old indices if any to data streams
 	*********************************************/
-	err = c.indexUpgradeMonitor.MigrateOldIndices(c.log, vmo, c.osClient, c.osDashboardsClient)
-	if err != nil {
-		c.log.Errorf("Failed to migrate old indices to data stream: %v", err)
-		errorObserved = true
+	if isIndexMigrationDryRun(vmo) {
+		// the report is informational, and is built again on the next reconcile, so it does not hold back deployments
+		if err := reportIndexMigration(c, vmo); err != nil {
+			c.log.Errorf("Failed to report the migration of old indices to data stream: %v", err)
+		}
+	} else {
+		vmo.Status.Elasticsearch.MigrationReport = nil
+		migrateOldIndices(c, vmo)
 	}
 
 	/*********************
@@ -501,6 +540,30 @@ func (c *Controller) syncHandlerStandardMode(vmo *vmcontrollerv1.VerrazzanoMonit
 		errorObserved = true
 	}
 
+	/*********************
+	 * Configure OpenSearch Cluster Settings
+	 **********************/
+	var clusterSettings map[string]string
+	var clusterSettingsChannel chan error
+	// allocation awareness depends on the PVC availability domains, so they must be known
+	if err == nil {
+		clusterSettings = opensearch.GetClusterSettings(vmo, deployments.GetDataNodeAvailabilityDomains(vmo, pvcToAdMap))
+		clusterSettingsChannel = c.osClient.ConfigureClusterSettings(vmo, clusterSettings, vmo.Status.Elasticsearch.ClusterSettings)
+	}
+
+	/*********************
+	 * Report OpenSearch Health
+	 **********************/
+	reconcileOpenSearchHealth(c, vmo)
+
+	/*********************
+	 * Track OpenSearch Upgrades
+	 **********************/
+	// the upgrade is reported in the status, and is tracked again on the next reconcile, so it does not hold back deployments
+	if err := reconcileOpenSearchUpgrade(c, vmo); err != nil {
+		c.log.Errorf("Failed to track OpenSearch upgrade for VMI %s: %v", vmo.Name, err)
+	}
+
 	/*********************
 	 * Create StatefulSets
 	 **********************/
@@ -519,6 +582,24 @@ func (c *Controller) syncHandlerStandardMode(vmo *vmcontrollerv1.VerrazzanoMonit
 			errorObserved = true
 		}
 	}
+	/*********************
+	 * Finish OpenSearch node removals and restarts
+	 **********************/
+	if !errorObserved {
+		if err := finishOpenSearchNodeDrain(c, vmo); err != nil {
+			c.log.Errorf("Failed to finish draining OpenSearch node: %v", err)
+			errorObserved = true
+		}
+		if err := finishVotingConfigExclusions(c, vmo); err != nil {
+			c.log.Errorf("Failed to clear OpenSearch voting config exclusions: %v", err)
+			errorObserved = true
+		}
+		if err := finishOrphanedOpenSearchNodeRestart(c, vmo); err != nil {
+			c.log.Errorf("Failed to finish OpenSearch node restart: %v", err)
+			errorObserved = true
+		}
+	}
+
 	/*********************
 	 * Create Ingresses
 	 **********************/
@@ -528,6 +609,55 @@ func (c *Controller) syncHandlerStandardMode(vmo *vmcontrollerv1.VerrazzanoMonit
 		errorObserved = true
 	}
 
+	if clusterSettingsChannel != nil {
+		if err := <-clusterSettingsChannel; err != nil {
+			c.log.Errorf("Failed to configure OpenSearch cluster settings: %v", err)
+			errorObserved = true
+		} else {
+			vmo.Status.Elasticsearch.ClusterSettings = clusterSettings
+		}
+	}
+
+	if err := <-defaultIndexPatternsChannel; err != nil {
+		c.log.Errorf("Failed to configure default index patterns: %v", err)
+		errorObserved = true
+	}
+
+	if err := <-crossClusterChannel; err != nil {
+		c.log.Errorf("Failed to configure cross-cluster index patterns: %v", err)
+		errorObserved = true
+	} else {
+		vmo.Status.Elasticsearch.CrossClusterIndexPatterns = vmo.Spec.Elasticsearch.CrossClusterIndexPatterns
+	}
+
+	// tenants are seeded once they exist, and are part of the status, so they must be known before the VMO is updated
+	if err := <-tenantsChannel; err != nil {
+		c.log.Errorf("Failed to configure OpenSearch Dashboards tenants: %v", err)
+		errorObserved = true
+	} else if err := seedDashboardsTenants(c, vmo); err != nil {
+		c.log.Errorf("Failed to seed OpenSearch Dashboards tenants: %v", err)
+		errorObserved = true
+	}
+
+	// OpenSearch Dashboards is only reachable once it is deployed, and the saved objects and their backups are part of
+	// the status, so they are provisioned and backed up after the deployments are created, and before the VMO is updated
+	if err := provisionDashboardsSavedObjects(c, vmo); err != nil {
+		c.log.Errorf("Failed to provision OpenSearch Dashboards saved objects: %v", err)
+		errorObserved = true
+	}
+	if err := backupDashboardsSavedObjects(c, vmo); err != nil {
+		c.log.Errorf("Failed to back up OpenSearch Dashboards saved objects: %v", err)
+		errorObserved = true
+	}
+
+	// the managed state is part of the status, so it must be known before the VMO is updated
+	if err := <-alertingChannel; err != nil {
+		c.log.Errorf("Failed to configure OpenSearch monitors and notification channels: %v", err)
+		errorObserved = true
+	} else {
+		vmo.Status.Elasticsearch.AlertingManaged = vmo.Spec.Elasticsearch.Enabled && alertingConfigured
+	}
+
 	/*********************
 	* Update VMO itself (if necessary, if anything has changed)
 	**********************/
@@ -549,6 +679,11 @@ func (c *Controller) syncHandlerStandardMode(vmo *vmcontrollerv1.VerrazzanoMonit
 		errorObserved = true
 	}
 
+	if err := <-securityChannel; err != nil {
+		c.log.Errorf("Failed to configure OpenSearch security: %v", err)
+		errorObserved = true
+	}
+
 	if !errorObserved && !deploymentsDirty && len(c.buildVersion) > 0 && vmo.Spec.Versioning.CurrentVersion != c.buildVersion {
 		// The spec.versioning.currentVersion field should not be updated to the new value until a sync produces no
 		// changes.  This allows observers (e.g. the controlled rollout scripts used to put new versions of operator
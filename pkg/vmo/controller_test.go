@@ -0,0 +1,119 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/client/clientset/versioned/fake"
+	vmoinformers "github.com/verrazzano/verrazzano-monitoring-operator/pkg/client/informers/externalversions"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch/opensearchtest"
+	dashboards "github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch_dashboards"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	"net/http"
+	"testing"
+	"time"
+)
+
+//makeSyncController creates a controller for a fresh install, whose OpenSearch client is backed by the fake cluster,
+// and whose OpenSearch Dashboards client cannot reach OpenSearch Dashboards, as it is not yet deployed
+func makeSyncController(t *testing.T, cluster *opensearchtest.FakeOpenSearch) *Controller {
+	operatorConfig, err := config.NewConfigFromConfigMap(&corev1.ConfigMap{Data: map[string]string{"config": "envName: test"}})
+	assert.NoError(t, err)
+	kubeClient := kubefake.NewSimpleClientset()
+	vmoClient := fake.NewSimpleClientset(testvmo.DeepCopy())
+	kubeInformers := informers.NewSharedInformerFactory(kubeClient, 0)
+	assert.NoError(t, kubeInformers.Rbac().V1().ClusterRoles().Informer().GetIndexer().Add(&rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: constants.ClusterRoleForVMOInstances + "-default"},
+	}))
+	assert.NoError(t, kubeInformers.Storage().V1().StorageClasses().Informer().GetIndexer().Add(&storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "standard",
+			Annotations: map[string]string{constants.K8sDefaultStorageClassAnnotation: "true"},
+		},
+	}))
+	assert.NoError(t, kubeInformers.Core().V1().ConfigMaps().Informer().GetIndexer().Add(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "system-dashboards",
+			Namespace: testvmo.Namespace,
+			Labels:    map[string]string{constants.DashboardsSavedObjectsLabel: testvmo.Name},
+		},
+		Data: map[string]string{"dashboard.ndjson": `{"type": "dashboard", "id": "system"}`},
+	}))
+	vmoInformers := vmoinformers.NewSharedInformerFactory(vmoClient, 0)
+	osClient := opensearch.NewOSClient()
+	osClient.DoHTTP = cluster.DoHTTP
+	osDashboardsClient := dashboards.NewOSDashboardsClient()
+	osDashboardsClient.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	}
+	return &Controller{
+		kubeclientset:      kubeClient,
+		vmoclientset:       vmoClient,
+		clusterRoleLister:  kubeInformers.Rbac().V1().ClusterRoles().Lister(),
+		configMapLister:    kubeInformers.Core().V1().ConfigMaps().Lister(),
+		deploymentLister:   kubeInformers.Apps().V1().Deployments().Lister(),
+		ingressLister:      kubeInformers.Networking().V1().Ingresses().Lister(),
+		nodeLister:         kubeInformers.Core().V1().Nodes().Lister(),
+		pvcLister:          kubeInformers.Core().V1().PersistentVolumeClaims().Lister(),
+		roleBindingLister:  kubeInformers.Rbac().V1().RoleBindings().Lister(),
+		secretLister:       kubeInformers.Core().V1().Secrets().Lister(),
+		serviceLister:      kubeInformers.Core().V1().Services().Lister(),
+		statefulSetLister:  kubeInformers.Apps().V1().StatefulSets().Lister(),
+		vmoLister:          vmoInformers.Verrazzano().V1().VerrazzanoMonitoringInstances().Lister(),
+		storageClassLister: kubeInformers.Storage().V1().StorageClasses().Lister(),
+		operatorConfig:     operatorConfig,
+		recorder:           record.NewFakeRecorder(100),
+		osClient:           osClient,
+		osDashboardsClient: osDashboardsClient,
+		log:                vzlog.DefaultLogger(),
+	}
+}
+
+// TestSyncHandlerStandardModeFreshInstall Tests the first reconcile of a VMI
+// GIVEN a VMI with OpenSearch and OpenSearch Dashboards, which are not yet deployed, with saved objects and their backup
+// WHEN syncHandlerStandardMode is called
+// THEN it returns, and the Deployments are created, although OpenSearch Dashboards cannot be configured yet
+func TestSyncHandlerStandardModeFreshInstall(t *testing.T) {
+	cluster := opensearchtest.NewFakeOpenSearch()
+	defer cluster.Close()
+	cluster.AddNode("master-0", "1.3.6", "master", "data", "ingest")
+	c := makeSyncController(t, cluster)
+	vmo := testvmo.DeepCopy()
+	vmo.Labels = map[string]string{}
+	vmo.Spec.Elasticsearch.Enabled = true
+	vmo.Spec.Kibana.Enabled = true
+	vmo.Spec.Kibana.Backup = &vmcontrollerv1.SavedObjectsBackup{}
+
+	done := make(chan error)
+	go func() {
+		done <- c.syncHandlerStandardMode(vmo)
+	}()
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("syncHandlerStandardMode did not return")
+	}
+
+	deployments, err := c.kubeclientset.AppsV1().Deployments(vmo.Namespace).List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, deployments.Items)
+	var names []string
+	for _, deployment := range deployments.Items {
+		names = append(names, deployment.Name)
+	}
+	assert.Contains(t, names, "vmi-system-kibana")
+}
@@ -13,12 +13,40 @@ import (
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/deployments"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	"strings"
 )
 
+//setOpenSearchDashboardsStrategy rolls out OpenSearch Dashboards one pod at a time while it has several replicas, so it
+// stays available while its settings change. Each pod migrates the saved objects when it starts, so pods of different
+// versions must not run together, and all pods are recreated when the image changes.
+func setOpenSearchDashboardsStrategy(osd, existing *appsv1.Deployment) {
+	if osd.Spec.Replicas == nil || *osd.Spec.Replicas < 2 {
+		return
+	}
+	for _, container := range osd.Spec.Template.Spec.Containers {
+		for _, existingContainer := range existing.Spec.Template.Spec.Containers {
+			if existingContainer.Name == container.Name && existingContainer.Image != container.Image {
+				return
+			}
+		}
+	}
+	maxUnavailable := intstr.FromInt(1)
+	maxSurge := intstr.FromInt(0)
+	osd.Spec.Strategy = appsv1.DeploymentStrategy{
+		Type: appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{
+			MaxUnavailable: &maxUnavailable,
+			MaxSurge:       &maxSurge,
+		},
+	}
+}
+
 func updateOpenSearchDashboardsDeployment(osd *appsv1.Deployment, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
 	if osd == nil {
 		return nil
@@ -37,6 +65,7 @@ func updateOpenSearchDashboardsDeployment(osd *appsv1.Deployment, controller *Co
 		if err != nil {
 			return err
 		}
+		setOpenSearchDashboardsStrategy(osd, existingDeployment)
 		err = updateDeployment(controller, vmo, existingDeployment, osd)
 	}
 	if err != nil {
@@ -47,6 +76,47 @@ func updateOpenSearchDashboardsDeployment(osd *appsv1.Deployment, controller *Co
 	return nil
 }
 
+//keepVolumes replaces the volumes of a pod spec whose names start with a prefix, and their mounts, with those of the
+// existing pod spec
+func keepVolumes(podSpec, existing *corev1.PodSpec, prefix string) {
+	var volumes []corev1.Volume
+	for _, volume := range podSpec.Volumes {
+		if !strings.HasPrefix(volume.Name, prefix) {
+			volumes = append(volumes, volume)
+		}
+	}
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		var mounts []corev1.VolumeMount
+		for _, mount := range container.VolumeMounts {
+			if !strings.HasPrefix(mount.Name, prefix) {
+				mounts = append(mounts, mount)
+			}
+		}
+		if existing != nil {
+			for _, existingContainer := range existing.Containers {
+				if existingContainer.Name != container.Name {
+					continue
+				}
+				for _, mount := range existingContainer.VolumeMounts {
+					if strings.HasPrefix(mount.Name, prefix) {
+						mounts = append(mounts, mount)
+					}
+				}
+			}
+		}
+		container.VolumeMounts = mounts
+	}
+	if existing != nil {
+		for _, volume := range existing.Volumes {
+			if strings.HasPrefix(volume.Name, prefix) {
+				volumes = append(volumes, volume)
+			}
+		}
+	}
+	podSpec.Volumes = volumes
+}
+
 // CreateDeployments create/update VMO deployment k8s resources
 func CreateDeployments(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, pvcToAdMap map[string]string, existingCluster bool) (dirty bool, err error) {
 	// Assigning the following spec members seems like a hack; is any
@@ -59,6 +129,15 @@ func CreateDeployments(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMon
 		return false, err
 	}
 	deployList := expected.Deployments
+	for _, deployment := range deployList {
+		var existing *corev1.PodSpec
+		if existingDeployment, err := controller.deploymentLister.Deployments(vmo.Namespace).Get(deployment.Name); err == nil {
+			existing = &existingDeployment.Spec.Template.Spec
+		}
+		pinOpenSearchImage(vmo, &deployment.Spec.Template.Spec, existing)
+		keepScrapeConfigSecrets(vmo, &deployment.Spec.Template.Spec, existing)
+		keepRemoteWriteSecrets(vmo, &deployment.Spec.Template.Spec, existing)
+	}
 
 	var prometheusDeployments []*appsv1.Deployment
 	var openSearchDeployments []*appsv1.Deployment
@@ -119,6 +198,13 @@ func CreateDeployments(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMon
 		}
 	}
 
+	// A data node that is expected again no longer needs to be drained
+	if drain := vmo.Status.Elasticsearch.Drain; drain != nil && contains(deploymentNames, drain.Owner) {
+		if err := cancelOpenSearchNodeDrain(controller, vmo); err != nil {
+			return false, err
+		}
+	}
+
 	// Delete deployments that shouldn't exist
 	selector := labels.SelectorFromSet(map[string]string{constants.VMOLabel: vmo.Name})
 	existingDeploymentsList, err := controller.deploymentLister.Deployments(vmo.Namespace).List(selector)
@@ -134,6 +220,13 @@ func CreateDeployments(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMon
 					controller.log.Oncef("Scale down of deployment %s not allowed: cluster health is not green", deployment.Name)
 					continue
 				}
+				// move the shards off of a running node before it leaves the cluster
+				if expected.OpenSearchDataDeployments > 0 && deployment.Status.ReadyReplicas > 0 {
+					drained, err := drainDeploymentNode(controller, vmo, deployment)
+					if err != nil || !drained {
+						return true, err
+					}
+				}
 				return false, deleteDeployment(controller, vmo, deployment)
 			}
 			if err := deleteDeployment(controller, vmo, deployment); err != nil {
@@ -145,6 +238,22 @@ func CreateDeployments(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMon
 	return prometheusDirty || openSearchDirty, nil
 }
 
+//drainDeploymentNode drains the OpenSearch node running in the deployment, returning true once it holds no shards
+func drainDeploymentNode(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, deployment *appsv1.Deployment) (bool, error) {
+	if drain := vmo.Status.Elasticsearch.Drain; drain != nil && drain.Owner == deployment.Name {
+		return drainOpenSearchNode(controller, vmo, deployment.Name, drain.Node)
+	}
+	nodeName, err := getDeploymentNodeName(controller, deployment)
+	if err != nil {
+		return false, err
+	}
+	// a node that isn't running has no shards to drain
+	if nodeName == "" {
+		return true, nil
+	}
+	return drainOpenSearchNode(controller, vmo, deployment.Name, nodeName)
+}
+
 func deleteDeployment(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, deployment *appsv1.Deployment) error {
 	controller.log.Debugf("Deleting deployment %s", deployment.Name)
 	err := controller.kubeclientset.AppsV1().Deployments(vmo.Namespace).Delete(context.TODO(), deployment.Name, metav1.DeleteOptions{})
@@ -177,8 +286,18 @@ func rollingUpdate(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitor
 			return false, err
 		}
 
+		// If the deployment's OpenSearch node is restarting, re-enable shard allocation once it is running again
+		if restart := vmo.Status.Elasticsearch.Restart; restart != nil && restart.Owner == existing.Name {
+			if !isDeploymentRestarted(existing, restart) {
+				return true, nil
+			}
+			if err := finishOpenSearchNodeRestart(controller, vmo); err != nil {
+				return false, err
+			}
+		}
+
 		// check if the current node is ready to be updated. If it can't, skip it for the next reconcile
-		if !isUpdateAllowed(controller, vmo, current) {
+		if !isUpdateAllowed(controller, vmo, existing) {
 			continue
 		}
 
@@ -189,10 +308,17 @@ func rollingUpdate(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitor
 		if specDiffs != "" {
 			controller.log.Debugf("Deployment %s : Spec differences %s", current.Name, specDiffs)
 			controller.log.Oncef("Updating deployment %s in namespace %s", current.Name, current.Namespace)
-			_, err = controller.kubeclientset.AppsV1().Deployments(vmo.Namespace).Update(context.TODO(), current, metav1.UpdateOptions{})
+			restarting, err := prepareDeploymentRestart(controller, vmo, existing)
+			if err != nil {
+				return false, err
+			}
+			updated, err := controller.kubeclientset.AppsV1().Deployments(vmo.Namespace).Update(context.TODO(), current, metav1.UpdateOptions{})
 			if err != nil {
 				return false, err
 			}
+			if restarting {
+				vmo.Status.Elasticsearch.Restart.Generation = updated.Generation
+			}
 			//okay to return dirty=false after updating the *last* deployment
 			return index < len(deployments)-1, nil
 		}
@@ -231,19 +357,31 @@ func updateAllDeployments(controller *Controller, vmo *vmcontrollerv1.Verrazzano
 
 //isUpdateAllowed checks if OpenSearch nodes are allowed to update. If a data node is removed when the cluster is yellow,
 // data loss may occur.
-func isUpdateAllowed(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, current *appsv1.Deployment) bool {
-	// if current is an OpenSearch data node
-	if deployments.IsOpenSearchDataDeployment(vmo.Namespace, current) {
+func isUpdateAllowed(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, existing *appsv1.Deployment) bool {
+	// if existing is an OpenSearch data node
+	if deployments.IsOpenSearchDataDeployment(vmo.Name, existing) {
 		// if the node is down, we should try to fix it
-		if current.Status.ReadyReplicas == 0 {
+		if existing.Status.ReadyReplicas == 0 {
 			return true
 		}
 
 		// if the node is running, we shouldn't take it down unless the cluster is green (to avoid data loss)
 		if err := controller.osClient.IsGreen(vmo); err != nil {
-			controller.log.Oncef("OpenSearch node %s was not upgraded, since the cluster is not ready", current.Name)
+			controller.log.Oncef("OpenSearch node %s was not upgraded, since the cluster is not ready", existing.Name)
 			return false
 		}
 	}
 	return true
 }
+
+//prepareDeploymentRestart restricts shard allocation before a running OpenSearch data node is restarted.
+// Returns true if the restart is being tracked.
+func prepareDeploymentRestart(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, existing *appsv1.Deployment) (bool, error) {
+	if !deployments.IsOpenSearchDataDeployment(vmo.Name, existing) || existing.Status.ReadyReplicas == 0 {
+		return false, nil
+	}
+	if err := prepareOpenSearchNodeRestart(controller, vmo, existing.Name, ""); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"testing"
+)
+
+// TestSetOpenSearchDashboardsStrategy Tests the rollout strategy of OpenSearch Dashboards
+// GIVEN an OpenSearch Dashboards Deployment with one or several replicas
+// WHEN setOpenSearchDashboardsStrategy is called
+// THEN several replicas are rolled out one at a time, unless the image changes, and a single replica is recreated
+func TestSetOpenSearchDashboardsStrategy(t *testing.T) {
+	deployment := func(replicas int32, image string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			Spec: appsv1.DeploymentSpec{
+				Replicas: resources.NewVal(replicas),
+				Strategy: appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType},
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "kibana", Image: image}, {Name: "oidc", Image: "proxy"}},
+					},
+				},
+			},
+		}
+	}
+	var tests = []struct {
+		name     string
+		replicas int32
+		image    string
+		strategy appsv1.DeploymentStrategyType
+	}{
+		{"a single replica is recreated", 1, "osd:1.3.6", appsv1.RecreateDeploymentStrategyType},
+		{"several replicas are rolled out one at a time", 3, "osd:1.3.6", appsv1.RollingUpdateDeploymentStrategyType},
+		{"several replicas are recreated when the image changes", 3, "osd:2.3.0", appsv1.RecreateDeploymentStrategyType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			osd := deployment(tt.replicas, tt.image)
+			setOpenSearchDashboardsStrategy(osd, deployment(1, "osd:1.3.6"))
+			assert.Equal(t, tt.strategy, osd.Spec.Strategy.Type)
+			if tt.strategy == appsv1.RollingUpdateDeploymentStrategyType {
+				assert.Equal(t, 1, osd.Spec.Strategy.RollingUpdate.MaxUnavailable.IntValue())
+				assert.Equal(t, 0, osd.Spec.Strategy.RollingUpdate.MaxSurge.IntValue())
+			} else {
+				assert.Nil(t, osd.Spec.Strategy.RollingUpdate)
+			}
+		})
+	}
+}
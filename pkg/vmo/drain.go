@@ -0,0 +1,164 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// nodeDrainTimeout is how long a node may take to move its shards before the drain is reported as stalled
+	nodeDrainTimeout       = 1 * time.Hour
+	reasonNodeDrainStalled = "OpenSearchNodeDrainStalled"
+)
+
+//drainOpenSearchNode moves all shards off of an OpenSearch node, so the node can be removed without data loss.
+// Only one node is drained at a time. Returns true once the node holds no shards.
+func drainOpenSearchNode(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, owner, nodeName string) (bool, error) {
+	drain := vmo.Status.Elasticsearch.Drain
+	if drain != nil && drain.Node != nodeName {
+		controller.log.Oncef("OpenSearch node %s is waiting for node %s to finish draining", nodeName, drain.Node)
+		return false, nil
+	}
+	if drain == nil {
+		if err := controller.osClient.ExcludeNode(vmo, nodeName); err != nil {
+			return false, err
+		}
+		now := metav1.Now()
+		drain = &vmcontrollerv1.NodeDrainStatus{
+			Node:      nodeName,
+			Owner:     owner,
+			Phase:     vmcontrollerv1.NodeDraining,
+			StartTime: &now,
+		}
+		vmo.Status.Elasticsearch.Drain = drain
+		controller.log.Oncef("Draining OpenSearch node %s", nodeName)
+	}
+
+	shards, err := controller.osClient.GetNodeShardCount(vmo, nodeName)
+	if err != nil {
+		return false, err
+	}
+	drain.Shards = shards
+	if shards > 0 {
+		if drain.StartTime != nil && time.Since(drain.StartTime.Time) > nodeDrainTimeout {
+			if drain.Phase != vmcontrollerv1.NodeDrainStalled {
+				drain.Phase = vmcontrollerv1.NodeDrainStalled
+				drain.Message = fmt.Sprintf("node %s still holds %d shards after %s, the remaining nodes may not be able to hold them", nodeName, shards, nodeDrainTimeout)
+				controller.log.Errorf("Draining OpenSearch node is stalled: %s", drain.Message)
+				controller.recorder.Event(vmo, corev1.EventTypeWarning, reasonNodeDrainStalled, drain.Message)
+			}
+			return false, nil
+		}
+		drain.Phase = vmcontrollerv1.NodeDraining
+		controller.log.Progressf("OpenSearch node %s is draining, %d shards remaining", nodeName, shards)
+		return false, nil
+	}
+	drain.Phase = vmcontrollerv1.NodeDrained
+	drain.Message = ""
+	return true, nil
+}
+
+//cancelOpenSearchNodeDrain allows shards to be allocated to the drained node again
+func cancelOpenSearchNodeDrain(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	drain := vmo.Status.Elasticsearch.Drain
+	if drain == nil {
+		return nil
+	}
+	controller.log.Oncef("Cancelling drain of OpenSearch node %s", drain.Node)
+	if err := controller.osClient.ClearNodeExclusion(vmo, drain.Node); err != nil {
+		return err
+	}
+	vmo.Status.Elasticsearch.Drain = nil
+	return nil
+}
+
+//finishOpenSearchNodeDrain clears the node exclusion once the drained node has left the cluster
+func finishOpenSearchNodeDrain(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	drain := vmo.Status.Elasticsearch.Drain
+	if drain == nil {
+		return nil
+	}
+	if !vmo.Spec.Elasticsearch.Enabled {
+		vmo.Status.Elasticsearch.Drain = nil
+		return nil
+	}
+	if drain.Phase != vmcontrollerv1.NodeDrained {
+		return nil
+	}
+	inCluster, err := controller.osClient.IsNodeInCluster(vmo, drain.Node)
+	if err != nil || inCluster {
+		return err
+	}
+	if err := controller.osClient.ClearNodeExclusion(vmo, drain.Node); err != nil {
+		return err
+	}
+	controller.log.Oncef("OpenSearch node %s was drained and removed from the cluster", drain.Node)
+	vmo.Status.Elasticsearch.Drain = nil
+	return nil
+}
+
+//cancelStatefulSetNodeDrain cancels the drain if the drained statefulset node is expected to stay in the cluster
+func cancelStatefulSetNodeDrain(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, expectedList []*appsv1.StatefulSet) error {
+	drain := vmo.Status.Elasticsearch.Drain
+	if drain != nil && isExpectedStatefulSetNode(expectedList, drain.Node) {
+		return cancelOpenSearchNodeDrain(controller, vmo)
+	}
+	return nil
+}
+
+//isExpectedStatefulSetNode returns true if the node belongs to an expected statefulset, and its ordinal is within the expected replicas
+func isExpectedStatefulSetNode(expectedList []*appsv1.StatefulSet, nodeName string) bool {
+	for _, sts := range expectedList {
+		if !strings.HasPrefix(nodeName, sts.Name+"-") {
+			continue
+		}
+		ordinal, err := strconv.Atoi(strings.TrimPrefix(nodeName, sts.Name+"-"))
+		if err == nil && *sts.Spec.Replicas > int32(ordinal) {
+			return true
+		}
+	}
+	return false
+}
+
+//getStatefulSetNodeName returns the name of the OpenSearch node running in the statefulset pod with the given ordinal
+func getStatefulSetNodeName(statefulSet *appsv1.StatefulSet, ordinal int32) string {
+	return fmt.Sprintf("%s-%d", statefulSet.Name, ordinal)
+}
+
+//getDeploymentNodeName returns the name of the OpenSearch node running in the deployment's pod, if any
+func getDeploymentNodeName(controller *Controller, deployment *appsv1.Deployment) (string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return "", err
+	}
+	pods, err := controller.kubeclientset.CoreV1().Pods(deployment.Namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return "", err
+	}
+	for _, pod := range pods.Items {
+		// data deployment selectors may overlap, so only match the deployment's own pods
+		if !isDeploymentPodName(deployment.Name, pod.Name) {
+			continue
+		}
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil {
+			return pod.Name, nil
+		}
+	}
+	return "", nil
+}
+
+//isDeploymentPodName returns true if the pod is named <deployment>-<replicaset hash>-<suffix>
+func isDeploymentPodName(deploymentName, podName string) bool {
+	suffix := strings.TrimPrefix(podName, deploymentName+"-")
+	return suffix != podName && strings.Count(suffix, "-") == 1
+}
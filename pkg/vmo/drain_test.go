@@ -0,0 +1,178 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	"io"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+//makeOpenSearchController creates a controller whose OpenSearch client responds with the body mapped to the request path
+func makeOpenSearchController(responses map[string]string) *Controller {
+	osClient := opensearch.NewOSClient()
+	osClient.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(responses[request.URL.Path])),
+		}, nil
+	}
+	return &Controller{
+		log:      vzlog.DefaultLogger(),
+		osClient: osClient,
+		recorder: record.NewFakeRecorder(10),
+	}
+}
+
+func TestDrainOpenSearchNode(t *testing.T) {
+	var tests = []struct {
+		name    string
+		drain   *vmcontrollerv1.NodeDrainStatus
+		shards  string
+		drained bool
+		phase   vmcontrollerv1.NodeDrainPhase
+	}{
+		{
+			"starts draining a node with shards",
+			nil,
+			`[{"index": "a", "shard": "0", "state": "STARTED", "node": "data-1"}]`,
+			false,
+			vmcontrollerv1.NodeDraining,
+		},
+		{
+			"node without shards is drained",
+			&vmcontrollerv1.NodeDrainStatus{Node: "data-1", Owner: "data", Phase: vmcontrollerv1.NodeDraining, Shards: 1},
+			`[{"index": "a", "shard": "0", "state": "STARTED", "node": "data-0"}]`,
+			true,
+			vmcontrollerv1.NodeDrained,
+		},
+		{
+			"drain is stalled after the timeout",
+			&vmcontrollerv1.NodeDrainStatus{Node: "data-1", Owner: "data", Phase: vmcontrollerv1.NodeDraining, Shards: 1, StartTime: &metav1.Time{Time: time.Now().Add(-2 * nodeDrainTimeout)}},
+			`[{"index": "a", "shard": "0", "state": "STARTED", "node": "data-1"}]`,
+			false,
+			vmcontrollerv1.NodeDrainStalled,
+		},
+		{
+			"waits for another node to finish draining",
+			&vmcontrollerv1.NodeDrainStatus{Node: "data-2", Owner: "data", Phase: vmcontrollerv1.NodeDraining, Shards: 1},
+			`[]`,
+			false,
+			vmcontrollerv1.NodeDraining,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vmo := testvmo.DeepCopy()
+			vmo.Spec.Elasticsearch.Enabled = true
+			vmo.Status.Elasticsearch.Drain = tt.drain
+			drained, err := drainOpenSearchNode(makeOpenSearchController(map[string]string{"/_cat/shards": tt.shards, "/_cluster/settings": `{"persistent": {}}`}), vmo, "data", "data-1")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.drained, drained)
+			assert.NotNil(t, vmo.Status.Elasticsearch.Drain)
+			assert.Equal(t, tt.phase, vmo.Status.Elasticsearch.Drain.Phase)
+		})
+	}
+}
+
+func TestFinishOpenSearchNodeDrain(t *testing.T) {
+	var tests = []struct {
+		name     string
+		phase    vmcontrollerv1.NodeDrainPhase
+		nodes    string
+		finished bool
+	}{
+		{
+			"drain is not finished while the node is draining",
+			vmcontrollerv1.NodeDraining,
+			`[]`,
+			false,
+		},
+		{
+			"drain is not finished while the node is in the cluster",
+			vmcontrollerv1.NodeDrained,
+			`[{"name": "data-1"}]`,
+			false,
+		},
+		{
+			"drain is finished after the node left the cluster",
+			vmcontrollerv1.NodeDrained,
+			`[{"name": "data-0"}]`,
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vmo := testvmo.DeepCopy()
+			vmo.Spec.Elasticsearch.Enabled = true
+			vmo.Status.Elasticsearch.Drain = &vmcontrollerv1.NodeDrainStatus{Node: "data-1", Owner: "data", Phase: tt.phase}
+			assert.NoError(t, finishOpenSearchNodeDrain(makeOpenSearchController(map[string]string{"/_cat/nodes": tt.nodes, "/_cluster/settings": `{"persistent": {"cluster.routing.allocation.exclude._name": "data-1"}}`}), vmo))
+			assert.Equal(t, tt.finished, vmo.Status.Elasticsearch.Drain == nil)
+		})
+	}
+}
+
+func TestCancelStatefulSetNodeDrain(t *testing.T) {
+	replicas := int32(2)
+	expected := []*appsv1.StatefulSet{{
+		ObjectMeta: metav1.ObjectMeta{Name: "data"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+	}}
+	c := makeOpenSearchController(map[string]string{"/_cluster/settings": `{"persistent": {"cluster.routing.allocation.exclude._name": "data-1"}}`})
+
+	vmo := testvmo.DeepCopy()
+	vmo.Status.Elasticsearch.Drain = &vmcontrollerv1.NodeDrainStatus{Node: "data-2", Owner: "data"}
+	assert.NoError(t, cancelStatefulSetNodeDrain(c, vmo, expected))
+	assert.NotNil(t, vmo.Status.Elasticsearch.Drain)
+
+	vmo.Status.Elasticsearch.Drain = &vmcontrollerv1.NodeDrainStatus{Node: "data-1", Owner: "data"}
+	assert.NoError(t, cancelStatefulSetNodeDrain(c, vmo, expected))
+	assert.Nil(t, vmo.Status.Elasticsearch.Drain)
+}
+
+func TestGetDeploymentNodeName(t *testing.T) {
+	makePod := func(name string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: constants.VerrazzanoSystemNamespace,
+				Labels:    map[string]string{"app": "system-es-data"},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vmi-system-es-data-1",
+			Namespace: constants.VerrazzanoSystemNamespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "system-es-data"}},
+		},
+	}
+	c := &Controller{
+		kubeclientset: fake.NewSimpleClientset(
+			makePod("vmi-system-es-data-0-5f8b9c7d4-abcde", corev1.PodRunning),
+			makePod("vmi-system-es-data-1-7c9d8f6b5-fghij", corev1.PodPending),
+			makePod("vmi-system-es-data-1-7c9d8f6b5-klmno", corev1.PodRunning),
+		),
+	}
+	nodeName, err := getDeploymentNodeName(c, deployment)
+	assert.NoError(t, err)
+	assert.Equal(t, "vmi-system-es-data-1-7c9d8f6b5-klmno", nodeName)
+}
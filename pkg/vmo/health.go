@@ -0,0 +1,75 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"reflect"
+	"strings"
+)
+
+const (
+	// Event reasons for OpenSearch health changes
+	reasonOpenSearchUnhealthy = "OpenSearchUnhealthy"
+	reasonOpenSearchHealthy   = "OpenSearchHealthy"
+	// maxEventMessageLength keeps event messages below the API server limit
+	maxEventMessageLength = 1024
+)
+
+//reconcileOpenSearchHealth records the OpenSearch cluster health in the VMI status, and emits an Event whenever
+// the health or the reasons shards cannot be allocated change. The shard and task counts are updated on each
+// reconcile, while the transition time only changes with the health or the reasons.
+func reconcileOpenSearchHealth(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) {
+	if !vmo.Spec.Elasticsearch.Enabled {
+		vmo.Status.Elasticsearch.Health = nil
+		return
+	}
+	health, err := controller.osClient.GetHealthDiagnostics(vmo)
+	if err != nil {
+		// the cluster may not be reachable yet
+		controller.log.Debugf("Unable to get OpenSearch health diagnostics: %v", err)
+		return
+	}
+	previous := vmo.Status.Elasticsearch.Health
+	status := &vmcontrollerv1.ClusterHealthStatus{
+		Status:             health.Status,
+		UnassignedShards:   health.UnassignedShards,
+		InitializingShards: health.InitializingShards,
+		RelocatingShards:   health.RelocatingShards,
+		PendingTasks:       health.NumberOfPendingTasks,
+		BlockingReasons:    health.BlockingReasons,
+	}
+	vmo.Status.Elasticsearch.Health = status
+	if previous != nil && previous.Status == health.Status && reflect.DeepEqual(previous.BlockingReasons, health.BlockingReasons) {
+		status.LastTransitionTime = previous.LastTransitionTime
+		return
+	}
+	now := metav1.Now()
+	status.LastTransitionTime = &now
+
+	if health.Status == opensearch.HealthGreen {
+		if previous != nil && previous.Status != opensearch.HealthGreen {
+			controller.recorder.Event(vmo, corev1.EventTypeNormal, reasonOpenSearchHealthy, "OpenSearch health is green")
+		}
+		return
+	}
+	controller.log.Oncef("OpenSearch health is %s: %s", health.Status, strings.Join(health.BlockingReasons, "; "))
+	controller.recorder.Event(vmo, corev1.EventTypeWarning, reasonOpenSearchUnhealthy, getHealthEventMessage(health))
+}
+
+func getHealthEventMessage(health *opensearch.HealthDiagnostics) string {
+	message := fmt.Sprintf("OpenSearch health is %s, with %d unassigned, %d initializing and %d relocating shards",
+		health.Status, health.UnassignedShards, health.InitializingShards, health.RelocatingShards)
+	if len(health.BlockingReasons) > 0 {
+		message = fmt.Sprintf("%s: %s", message, strings.Join(health.BlockingReasons, "; "))
+	}
+	if len(message) > maxEventMessageLength {
+		message = message[:maxEventMessageLength-3] + "..."
+	}
+	return message
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"testing"
+	"time"
+)
+
+func TestReconcileOpenSearchHealth(t *testing.T) {
+	const (
+		green  = `{"status": "green", "number_of_nodes": 3}`
+		yellow = `{"status": "yellow", "number_of_nodes": 3, "unassigned_shards": 1}`
+		moving = `{"status": "yellow", "number_of_nodes": 3, "unassigned_shards": 1, "initializing_shards": 2, "number_of_pending_tasks": 4}`
+		shards = `[{"index": "verrazzano-system", "shard": "0", "prirep": "r", "state": "UNASSIGNED"}]`
+		reason = `{"index": "verrazzano-system", "shard": 0, "allocate_explanation": "no valid node"}`
+	)
+	transitionTime := metav1.NewTime(time.Now().Add(-time.Hour))
+	yellowStatus := &vmcontrollerv1.ClusterHealthStatus{
+		Status:             "yellow",
+		UnassignedShards:   1,
+		BlockingReasons:    []string{"verrazzano-system[0] replica: no valid node"},
+		LastTransitionTime: &transitionTime,
+	}
+	movingStatus := yellowStatus.DeepCopy()
+	movingStatus.InitializingShards = 2
+	movingStatus.PendingTasks = 4
+
+	var tests = []struct {
+		name     string
+		previous *vmcontrollerv1.ClusterHealthStatus
+		health   string
+		status   string
+		expected *vmcontrollerv1.ClusterHealthStatus
+		event    string
+	}{
+		{"no event for a green cluster", nil, green, "green", nil, ""},
+		{"warning when the cluster becomes yellow", nil, yellow, "yellow", nil, "Warning OpenSearchUnhealthy OpenSearch health is yellow, with 1 unassigned, 0 initializing and 0 relocating shards: verrazzano-system[0] replica: no valid node"},
+		{"no event when the blocking reasons have not changed", yellowStatus, yellow, "yellow", yellowStatus, ""},
+		{"only the counts change when shards move", yellowStatus, moving, "yellow", movingStatus, ""},
+		{"normal event when the cluster is green again", yellowStatus, green, "green", nil, "Normal OpenSearchHealthy OpenSearch health is green"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := makeOpenSearchController(map[string]string{
+				"/_cluster/health":             tt.health,
+				"/_cat/shards":                 shards,
+				"/_cluster/allocation/explain": reason,
+			})
+			recorder := c.recorder.(*record.FakeRecorder)
+			vmo := testvmo.DeepCopy()
+			vmo.Spec.Elasticsearch.Enabled = true
+			vmo.Status.Elasticsearch.Health = tt.previous
+			reconcileOpenSearchHealth(c, vmo)
+			assert.Equal(t, tt.status, vmo.Status.Elasticsearch.Health.Status)
+			// the transition time only changes when the health or the blocking reasons change
+			if tt.expected != nil {
+				assert.Equal(t, tt.expected, vmo.Status.Elasticsearch.Health)
+			} else {
+				assert.NotEqual(t, &transitionTime, vmo.Status.Elasticsearch.Health.LastTransitionTime)
+			}
+			select {
+			case event := <-recorder.Events:
+				assert.Equal(t, tt.event, event)
+			default:
+				assert.Empty(t, tt.event)
+			}
+		})
+	}
+}
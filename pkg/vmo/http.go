@@ -8,6 +8,8 @@ import (
 	"path"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"k8s.io/apimachinery/pkg/util/wait"
 )
 
@@ -35,4 +37,8 @@ func setupHandlers(controller *Controller) {
 			w.WriteHeader(http.StatusInternalServerError)
 		}
 	})
+
+	// OpenSearch statistics are exported along with the operator's own metrics
+	prometheus.MustRegister(controller.openSearchCollector)
+	http.Handle("/metrics", promhttp.Handler())
 }
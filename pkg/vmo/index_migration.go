@@ -0,0 +1,33 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Event reason for index migrations which failed after all reindex attempts
+const reasonIndexMigrationFailed = "IndexMigrationFailed"
+
+//migrateOldIndices moves the migration of old indices to data streams a step forward, and emits a Warning Event for
+// each index whose migration failed. The migration progress is reported in the VMI status, so errors are only logged
+// and do not hold back the other resources of the VMI.
+func migrateOldIndices(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) {
+	failed := map[string]bool{}
+	for _, migration := range vmo.Status.Elasticsearch.IndexMigrations {
+		if migration.Phase == vmcontrollerv1.IndexMigrationFailed {
+			failed[migration.Index] = true
+		}
+	}
+	if err := controller.indexUpgradeMonitor.MigrateOldIndices(controller.log, vmo, controller.osClient, controller.osDashboardsClient); err != nil {
+		controller.log.Errorf("Failed to migrate old indices to data stream: %v", err)
+	}
+	for _, migration := range vmo.Status.Elasticsearch.IndexMigrations {
+		if migration.Phase == vmcontrollerv1.IndexMigrationFailed && !failed[migration.Index] {
+			controller.recorder.Eventf(vmo, corev1.EventTypeWarning, reasonIndexMigrationFailed,
+				"Failed to migrate index %s to data stream %s: %s", migration.Index, migration.DataStream, migration.Message)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"k8s.io/client-go/tools/record"
+	"testing"
+)
+
+// TestMigrateOldIndices tests reporting index migrations which failed after all reindex attempts
+// GIVEN an index migration on its last reindex attempt
+// WHEN the reindex task fails, and the migration is reconciled again
+// THEN the failed migration is kept in the status, with a single Warning Event
+func TestMigrateOldIndices(t *testing.T) {
+	c := makeOpenSearchController(map[string]string{
+		"/_tasks/node:1": `{"completed": true, "error": {"type": "search_phase_execution_exception", "reason": "all shards failed"}}`,
+	})
+	recorder := c.recorder.(*record.FakeRecorder)
+	vmo := testvmo.DeepCopy()
+	vmo.Spec.Elasticsearch.Enabled = true
+	vmo.Status.Elasticsearch.IndexMigrations = []vmcontrollerv1.IndexMigrationStatus{{
+		Index:      "verrazzano-namespace-verrazzano-system",
+		DataStream: "verrazzano-system",
+		Phase:      vmcontrollerv1.IndexMigrationReindexing,
+		TaskID:     "node:1",
+		Attempts:   3,
+	}}
+
+	migrateOldIndices(c, vmo)
+	migrateOldIndices(c, vmo)
+	assert.Len(t, vmo.Status.Elasticsearch.IndexMigrations, 1)
+	assert.Equal(t, vmcontrollerv1.IndexMigrationFailed, vmo.Status.Elasticsearch.IndexMigrations[0].Phase)
+	assert.Len(t, recorder.Events, 1)
+	assert.Equal(t, "Warning IndexMigrationFailed Failed to migrate index verrazzano-namespace-verrazzano-system to data stream verrazzano-system: reindex task node:1 failed: all shards failed, the source index was kept", <-recorder.Events)
+}
@@ -0,0 +1,130 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/configmaps"
+	"gopkg.in/yaml.v2"
+	"hash/fnv"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"reflect"
+)
+
+const (
+	migrationReportConfigMap = "index-migration-report"
+	migrationReportKey       = "report.yaml"
+)
+
+//isIndexMigrationDryRun returns true if the migration of old indices should only be reported
+func isIndexMigrationDryRun(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) bool {
+	return vmo.Spec.Elasticsearch.Enabled && vmo.Spec.Elasticsearch.Migration != nil && vmo.Spec.Elasticsearch.Migration.DryRun
+}
+
+func getMigrationReportConfigMapName(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) string {
+	return resources.GetMetaName(vmo.Name, migrationReportConfigMap)
+}
+
+//reportIndexMigration writes what the migration of old indices would do to a ConfigMap, and summarizes it in the VMI status.
+// No index is reindexed or deleted. Counting the documents of each index is expensive, so the report is only built
+// again when the indices to migrate or the migration settings change.
+func reportIndexMigration(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	migrations, err := controller.osClient.GetIndexMigrations(controller.log, vmo)
+	if err != nil {
+		return err
+	}
+	dataStreams, err := getExistingDataStreams(controller, vmo, migrations)
+	if err != nil {
+		return err
+	}
+	hash, err := getMigrationReportHash(vmo, migrations, dataStreams)
+	if err != nil {
+		return err
+	}
+	name := getMigrationReportConfigMapName(vmo)
+	existingConfigMap, err := getConfigMap(controller, vmo.Namespace, name)
+	if err != nil {
+		return err
+	}
+	if existingConfigMap != nil && vmo.Status.Elasticsearch.MigrationReport != nil && vmo.Status.Elasticsearch.MigrationReport.Hash == hash {
+		return nil
+	}
+
+	plans, err := controller.osClient.GetIndexMigrationPlans(controller.log, vmo, migrations)
+	if err != nil {
+		return err
+	}
+	for i := range plans {
+		plans[i].DataStreamExists = dataStreams[plans[i].DataStream]
+	}
+	report, err := yaml.Marshal(plans)
+	if err != nil {
+		return err
+	}
+	configMap := configmaps.NewConfig(vmo, name, map[string]string{migrationReportKey: string(report)})
+	if existingConfigMap == nil {
+		if _, err := controller.kubeclientset.CoreV1().ConfigMaps(vmo.Namespace).Create(context.TODO(), configMap, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+	} else if !reflect.DeepEqual(existingConfigMap.Data, configMap.Data) {
+		if _, err := controller.kubeclientset.CoreV1().ConfigMaps(vmo.Namespace).Update(context.TODO(), configMap, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+
+	summary := &vmcontrollerv1.IndexMigrationReportSummary{
+		ConfigMap: name,
+		Indices:   len(plans),
+		Hash:      hash,
+	}
+	for _, plan := range plans {
+		summary.Documents += plan.Documents
+		summary.DroppedDocuments += plan.DroppedDocuments
+		summary.SizeBytes += plan.SizeBytes
+	}
+	if !reflect.DeepEqual(vmo.Status.Elasticsearch.MigrationReport, summary) {
+		controller.log.Oncef("Migration of %d indices to data streams would drop %d of %d documents, see ConfigMap %s",
+			summary.Indices, summary.DroppedDocuments, summary.Documents, name)
+	}
+	vmo.Status.Elasticsearch.MigrationReport = summary
+	return nil
+}
+
+//getExistingDataStreams returns whether each destination data stream of the migrations already exists.
+// Data streams are created as new documents are written, so they are looked up on every reconcile.
+func getExistingDataStreams(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, migrations []vmcontrollerv1.IndexMigrationStatus) (map[string]bool, error) {
+	openSearchEndpoint := resources.GetOpenSearchHTTPEndpoint(vmo)
+	dataStreams := map[string]bool{}
+	for _, migration := range migrations {
+		if _, ok := dataStreams[migration.DataStream]; ok {
+			continue
+		}
+		exists, err := controller.osClient.DataStreamExists(openSearchEndpoint, migration.DataStream)
+		if err != nil {
+			return nil, err
+		}
+		dataStreams[migration.DataStream] = exists
+	}
+	return dataStreams, nil
+}
+
+//getMigrationReportHash returns a hash of the indices to migrate, of their existing data streams and of the migration
+// settings, which changes whenever the report would
+func getMigrationReportHash(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, migrations []vmcontrollerv1.IndexMigrationStatus, dataStreams map[string]bool) (string, error) {
+	data, err := json.Marshal(struct {
+		Migrations  []vmcontrollerv1.IndexMigrationStatus
+		DataStreams map[string]bool
+		Settings    *vmcontrollerv1.IndexMigrationSettings
+	}{migrations, dataStreams, vmo.Spec.Elasticsearch.Migration})
+	if err != nil {
+		return "", err
+	}
+	hash := fnv.New32a()
+	hash.Write(data)
+	return fmt.Sprintf("%08x", hash.Sum32()), nil
+}
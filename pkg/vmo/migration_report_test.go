@@ -0,0 +1,87 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch"
+	"io"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestReportIndexMigration Tests the migration dry run
+// GIVEN a VMI with a migration dry run, and old indices
+// WHEN reportIndexMigration is called
+// THEN the report is written to a ConfigMap and summarized in the status, with whether each data stream exists,
+// and the ConfigMap is deleted once the dry run is turned off
+func TestReportIndexMigration(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	c := makeOpenSearchController(map[string]string{
+		"/_aliases":     `{"verrazzano-namespace-bobs-books": {"aliases": {}}, ".kibana_1": {"aliases": {}}}`,
+		"/_cat/indices": `[{"index": "verrazzano-namespace-bobs-books", "store.size": "2048"}]`,
+		"/verrazzano-namespace-bobs-books/_count": `{"count": 100}`,
+	})
+	c.kubeclientset = client
+	c.configMapLister = &simpleConfigMapLister{kubeClient: client}
+	c.secretLister = &simpleSecretLister{kubeClient: client}
+	// only the data stream of the renamed destination exists
+	osClient := c.osClient.(*opensearch.OSClient)
+	doHTTP := osClient.DoHTTP
+	osClient.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		if strings.HasPrefix(request.URL.Path, "/_data_stream/") && request.URL.Path != "/_data_stream/verrazzano-application-books" {
+			return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(""))}, nil
+		}
+		return doHTTP(request)
+	}
+
+	vmo := testvmo.DeepCopy()
+	vmo.Spec.Elasticsearch.Enabled = true
+	vmo.Spec.Elasticsearch.Migration = &vmcontrollerv1.IndexMigrationSettings{DryRun: true}
+	assert.True(t, isIndexMigrationDryRun(vmo))
+	assert.NoError(t, reportIndexMigration(c, vmo))
+	summary := *vmo.Status.Elasticsearch.MigrationReport
+	assert.NotEmpty(t, summary.Hash)
+	summary.Hash = ""
+	assert.Equal(t, vmcontrollerv1.IndexMigrationReportSummary{
+		ConfigMap: "vmi-system-index-migration-report",
+		Indices:   1,
+		Documents: 100,
+		SizeBytes: 2048,
+	}, summary)
+
+	configMap, err := client.CoreV1().ConfigMaps(vmo.Namespace).Get(context.TODO(), "vmi-system-index-migration-report", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Contains(t, configMap.Data[migrationReportKey], "dataStream: verrazzano-application-bobs-books\n  dataStreamExists: false")
+
+	// the report is not built again while the indices and the settings are the same
+	configMap.Data[migrationReportKey] = ""
+	_, err = client.CoreV1().ConfigMaps(vmo.Namespace).Update(context.TODO(), configMap, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	assert.NoError(t, reportIndexMigration(c, vmo))
+	configMap, err = client.CoreV1().ConfigMaps(vmo.Namespace).Get(context.TODO(), "vmi-system-index-migration-report", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, configMap.Data[migrationReportKey])
+
+	// the report is built again as the settings change
+	vmo.Spec.Elasticsearch.Migration.Rules = []vmcontrollerv1.IndexMigrationRule{{Source: "verrazzano-namespace-bobs-books", Destination: "verrazzano-application-books"}}
+	assert.NoError(t, reportIndexMigration(c, vmo))
+	configMap, err = client.CoreV1().ConfigMaps(vmo.Namespace).Get(context.TODO(), "vmi-system-index-migration-report", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Contains(t, configMap.Data[migrationReportKey], "dataStream: verrazzano-application-books\n  dataStreamExists: true")
+
+	// the report is deleted once the migration is no longer a dry run
+	vmo.Spec.Elasticsearch.Migration.DryRun = false
+	assert.False(t, isIndexMigrationDryRun(vmo))
+	vmo.Spec.Prometheus.ConfigMap = "myPrometheusConfigMap"
+	assert.NoError(t, CreateConfigmaps(c, vmo))
+	_, err = client.CoreV1().ConfigMaps(vmo.Namespace).Get(context.TODO(), "vmi-system-index-migration-report", metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+}
@@ -0,0 +1,38 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/metrics"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch"
+	"k8s.io/apimachinery/pkg/labels"
+	"time"
+)
+
+// How often the OpenSearch statistics exported as operator metrics are collected
+const openSearchStatsInterval = time.Minute
+
+//collectOpenSearchStats collects the statistics of the OpenSearch cluster of each VMI, and exports them as metrics.
+// A VMI whose statistics cannot be collected is exported as down.
+func (c *Controller) collectOpenSearchStats() {
+	vmos, err := c.vmoLister.List(labels.Everything())
+	if err != nil {
+		c.log.Errorf("Failed to list VMIs for OpenSearch statistics: %v", err)
+		return
+	}
+	stats := map[metrics.VMIKey]*opensearch.ClusterStats{}
+	for _, vmo := range vmos {
+		if !vmo.Spec.Elasticsearch.Enabled || (c.watchVmi != "" && c.watchVmi != vmo.Name) {
+			continue
+		}
+		key := metrics.VMIKey{Namespace: vmo.Namespace, Name: vmo.Name}
+		vmiStats, err := c.osClient.GetClusterStats(vmo)
+		if err != nil {
+			// OpenSearch is unavailable while the cluster starts, which is reported by the stats_up metric
+			c.log.Debugf("Failed to collect OpenSearch statistics of VMI %s: %v", vmo.Name, err)
+		}
+		stats[key] = vmiStats
+	}
+	c.openSearchCollector.Update(stats)
+}
@@ -0,0 +1,43 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	listers "github.com/verrazzano/verrazzano-monitoring-operator/pkg/client/listers/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/metrics"
+	"k8s.io/client-go/tools/cache"
+	"strings"
+	"testing"
+)
+
+// TestCollectOpenSearchStats Tests the periodic collection of OpenSearch statistics
+// GIVEN VMIs with and without OpenSearch
+// WHEN collectOpenSearchStats is called
+// THEN the statistics of each VMI with OpenSearch are exported
+func TestCollectOpenSearchStats(t *testing.T) {
+	c := makeOpenSearchController(map[string]string{
+		"/_cluster/health":             `{"status": "green"}`,
+		"/_nodes/stats/jvm,fs,indices": `{"nodes": {}}`,
+		"/_cat/indices":                `[]`,
+	})
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	vmo := testvmo.DeepCopy()
+	vmo.Spec.Elasticsearch.Enabled = true
+	assert.NoError(t, indexer.Add(vmo))
+	disabled := testvmo.DeepCopy()
+	disabled.Name = "disabled"
+	assert.NoError(t, indexer.Add(disabled))
+	c.vmoLister = listers.NewVerrazzanoMonitoringInstanceLister(indexer)
+	c.openSearchCollector = metrics.NewOpenSearchCollector()
+
+	c.collectOpenSearchStats()
+	expected := `
+# HELP vmo_opensearch_stats_up Whether the last collection of OpenSearch statistics succeeded
+# TYPE vmo_opensearch_stats_up gauge
+vmo_opensearch_stats_up{namespace="verrazzano-system",vmi="system"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(c.openSearchCollector, strings.NewReader(expected), "vmo_opensearch_stats_up"))
+}
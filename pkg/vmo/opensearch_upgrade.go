@@ -0,0 +1,249 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch"
+	nodetool "github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/nodes"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sort"
+	"time"
+)
+
+// upgradeNodeTimeout is how long an upgraded node may take to rejoin the cluster before the upgrade is considered failed
+const upgradeNodeTimeout = 30 * time.Minute
+
+//reconcileOpenSearchUpgrade records the version of each OpenSearch node, and moves the upgrade to its next phase.
+// Non-master nodes are upgraded first, followed by the master eligible nodes. If a node fails to rejoin the cluster
+// before any master has been upgraded, the nodes are rolled back to the previous image.
+func reconcileOpenSearchUpgrade(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	if !vmo.Spec.Elasticsearch.Enabled {
+		vmo.Status.Elasticsearch.Upgrade = nil
+		return nil
+	}
+	nodes, err := controller.osClient.GetNodes(vmo)
+	if err != nil {
+		// there is no running cluster to upgrade
+		controller.log.Debugf("Unable to get OpenSearch node versions: %v", err)
+		return nil
+	}
+
+	targetVersion := config.ESWaitTargetVersion
+	targetImage := config.ElasticsearchMaster.Image
+	needsUpgrade := false
+	for _, node := range nodes {
+		if node.Version != targetVersion {
+			needsUpgrade = true
+		}
+	}
+
+	upgrade := vmo.Status.Elasticsearch.Upgrade
+	if upgrade == nil || isUpgradeFinished(upgrade) {
+		if !needsUpgrade {
+			return nil
+		}
+		// a rejected or rolled back upgrade is only retried for a new image
+		if upgrade == nil || upgrade.Phase == vmcontrollerv1.UpgradeCompleted || upgrade.TargetImage != targetImage {
+			upgrade, err = newUpgradeStatus(controller, vmo, nodes, targetVersion, targetImage)
+			if err != nil {
+				return err
+			}
+			vmo.Status.Elasticsearch.Upgrade = upgrade
+		}
+	}
+	upgrade.Nodes = getNodeUpgradeStatus(nodes, upgrade.TargetVersion)
+
+	switch upgrade.Phase {
+	case vmcontrollerv1.UpgradeInProgress, vmcontrollerv1.UpgradePaused:
+		if !needsUpgrade {
+			setUpgradePhase(controller, upgrade, vmcontrollerv1.UpgradeCompleted, "")
+			return nil
+		}
+		if restart := vmo.Status.Elasticsearch.Restart; restart != nil && restart.StartTime != nil && time.Since(restart.StartTime.Time) > upgradeNodeTimeout {
+			return failUpgrade(controller, vmo, upgrade, fmt.Sprintf("%s did not rejoin the cluster within %s", restart.Owner, upgradeNodeTimeout))
+		}
+		if err := controller.osClient.IsGreen(vmo); err != nil {
+			setUpgradePhase(controller, upgrade, vmcontrollerv1.UpgradePaused, err.Error())
+		} else {
+			setUpgradePhase(controller, upgrade, vmcontrollerv1.UpgradeInProgress, "")
+		}
+	case vmcontrollerv1.UpgradeRollingBack:
+		for _, node := range nodes {
+			if node.Version != upgrade.FromVersion {
+				return nil
+			}
+		}
+		setUpgradePhase(controller, upgrade, vmcontrollerv1.UpgradeRolledBack, upgrade.Message)
+	case vmcontrollerv1.UpgradeFailed:
+		if !needsUpgrade {
+			setUpgradePhase(controller, upgrade, vmcontrollerv1.UpgradeCompleted, "")
+		}
+	}
+	return nil
+}
+
+//newUpgradeStatus starts tracking an upgrade to the target version, which is rejected if OpenSearch does not support it
+func newUpgradeStatus(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, nodes []opensearch.Node, targetVersion, targetImage string) (*vmcontrollerv1.UpgradeStatus, error) {
+	fromVersion := ""
+	var lowest opensearch.Version
+	var parseErr error
+	for _, node := range nodes {
+		version, err := opensearch.ParseVersion(node.Version)
+		if err != nil {
+			parseErr = fmt.Errorf("unable to parse the version of node %s: %v", node.Name, err)
+			break
+		}
+		if fromVersion == "" || version.Compare(lowest) < 0 {
+			fromVersion = node.Version
+			lowest = version
+		}
+	}
+	fromImage, err := getRunningOpenSearchImage(controller, vmo)
+	if err != nil {
+		return nil, err
+	}
+	now := metav1.Now()
+	upgrade := &vmcontrollerv1.UpgradeStatus{
+		Phase:         vmcontrollerv1.UpgradeInProgress,
+		FromVersion:   fromVersion,
+		TargetVersion: targetVersion,
+		FromImage:     fromImage,
+		TargetImage:   targetImage,
+		StartTime:     &now,
+	}
+	controller.log.Oncef("Upgrading OpenSearch from %s to %s", fromVersion, targetVersion)
+	// an upgrade from an unknown version is rejected, so it is reported in the status instead of failing every reconcile
+	if parseErr != nil {
+		setUpgradePhase(controller, upgrade, vmcontrollerv1.UpgradeRejected, parseErr.Error())
+	} else if err := opensearch.ValidateUpgrade(fromVersion, targetVersion); err != nil {
+		setUpgradePhase(controller, upgrade, vmcontrollerv1.UpgradeRejected, err.Error())
+	}
+	return upgrade, nil
+}
+
+//failUpgrade rolls the nodes back to the previous image, unless a master has already been upgraded
+func failUpgrade(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, upgrade *vmcontrollerv1.UpgradeStatus, reason string) error {
+	for _, node := range upgrade.Nodes {
+		if node.Upgraded && opensearch.IsMasterNode(opensearch.Node{Roles: node.Roles}) {
+			setUpgradePhase(controller, upgrade, vmcontrollerv1.UpgradeFailed, fmt.Sprintf("%s, and master node %s was already upgraded", reason, node.Name))
+			return nil
+		}
+	}
+	if upgrade.FromImage == "" {
+		setUpgradePhase(controller, upgrade, vmcontrollerv1.UpgradeFailed, fmt.Sprintf("%s, and the previous image is unknown", reason))
+		return nil
+	}
+	setUpgradePhase(controller, upgrade, vmcontrollerv1.UpgradeRollingBack, reason)
+	// the failed node will not rejoin, so shard allocation is enabled again
+	return finishOpenSearchNodeRestart(controller, vmo)
+}
+
+func setUpgradePhase(controller *Controller, upgrade *vmcontrollerv1.UpgradeStatus, phase vmcontrollerv1.UpgradePhase, message string) {
+	if upgrade.Phase != phase {
+		controller.log.Oncef("OpenSearch upgrade to %s is %s: %s", upgrade.TargetVersion, phase, message)
+	}
+	upgrade.Phase = phase
+	upgrade.Message = message
+}
+
+func isUpgradeFinished(upgrade *vmcontrollerv1.UpgradeStatus) bool {
+	switch upgrade.Phase {
+	case vmcontrollerv1.UpgradeCompleted, vmcontrollerv1.UpgradeRejected, vmcontrollerv1.UpgradeRolledBack:
+		return true
+	}
+	return false
+}
+
+func getNodeUpgradeStatus(nodes []opensearch.Node, targetVersion string) []vmcontrollerv1.NodeUpgradeStatus {
+	var nodeStatus []vmcontrollerv1.NodeUpgradeStatus
+	for _, node := range nodes {
+		nodeStatus = append(nodeStatus, vmcontrollerv1.NodeUpgradeStatus{
+			Name:     node.Name,
+			Roles:    node.Roles,
+			Version:  node.Version,
+			Upgraded: node.Version == targetVersion,
+		})
+	}
+	sort.Slice(nodeStatus, func(i, j int) bool {
+		return nodeStatus[i].Name < nodeStatus[j].Name
+	})
+	return nodeStatus
+}
+
+//getRunningOpenSearchImage returns the image of the existing OpenSearch master statefulsets, if it differs from the operator's image
+func getRunningOpenSearchImage(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) (string, error) {
+	selector := labels.SelectorFromSet(map[string]string{constants.VMOLabel: vmo.Name})
+	existingList, err := controller.statefulSetLister.StatefulSets(vmo.Namespace).List(selector)
+	if err != nil {
+		return "", err
+	}
+	for _, sts := range existingList {
+		for _, container := range sts.Spec.Template.Spec.Containers {
+			if container.Name == config.ElasticsearchMaster.Name && container.Image != config.ElasticsearchMaster.Image {
+				return container.Image, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+//pinOpenSearchImage keeps OpenSearch containers on the previous image while an upgrade is rejected or rolled back.
+// When the previous image is unknown, the containers keep the image of the existing pod spec, if any.
+func pinOpenSearchImage(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, podSpec, existing *corev1.PodSpec) {
+	upgrade := vmo.Status.Elasticsearch.Upgrade
+	if upgrade == nil || upgrade.TargetImage != config.ElasticsearchMaster.Image {
+		return
+	}
+	switch upgrade.Phase {
+	case vmcontrollerv1.UpgradeRejected, vmcontrollerv1.UpgradeRollingBack, vmcontrollerv1.UpgradeRolledBack:
+		var existingContainers, existingInitContainers []corev1.Container
+		if existing != nil {
+			existingContainers = existing.Containers
+			existingInitContainers = existing.InitContainers
+		}
+		pinContainerImages(podSpec.Containers, existingContainers, upgrade)
+		// the plugin installer uses the OpenSearch image
+		pinContainerImages(podSpec.InitContainers, existingInitContainers, upgrade)
+	}
+}
+
+func pinContainerImages(containers, existingContainers []corev1.Container, upgrade *vmcontrollerv1.UpgradeStatus) {
+	for i := range containers {
+		if containers[i].Image != upgrade.TargetImage {
+			continue
+		}
+		if upgrade.FromImage != "" {
+			containers[i].Image = upgrade.FromImage
+			continue
+		}
+		for _, existing := range existingContainers {
+			if existing.Name == containers[i].Name {
+				containers[i].Image = existing.Image
+			}
+		}
+	}
+}
+
+//isMasterUpgradeAllowed returns false while an upgrade is waiting for the expected non-master nodes to be upgraded
+func isMasterUpgradeAllowed(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) bool {
+	upgrade := vmo.Status.Elasticsearch.Upgrade
+	if upgrade == nil || (upgrade.Phase != vmcontrollerv1.UpgradeInProgress && upgrade.Phase != vmcontrollerv1.UpgradePaused) {
+		return true
+	}
+	if len(upgrade.Nodes) < int(nodetool.GetNodeCount(vmo).Replicas) {
+		return false
+	}
+	for _, node := range upgrade.Nodes {
+		if !node.Upgraded && !opensearch.IsMasterNode(opensearch.Node{Roles: node.Roles}) {
+			return false
+		}
+	}
+	return true
+}
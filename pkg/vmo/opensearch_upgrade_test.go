@@ -0,0 +1,234 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch/opensearchtest"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+	"time"
+)
+
+const (
+	testOldImage = "opensearch:1.2.4"
+	testNewImage = "opensearch:1.3.6"
+)
+
+func makeUpgradeController(t *testing.T, nodes, health string) *Controller {
+	c := makeOpenSearchController(map[string]string{
+		"/_nodes/settings":   nodes,
+		"/_cluster/health":   health,
+		"/_cluster/settings": `{}`,
+	})
+	c.kubeclientset = fake.NewSimpleClientset()
+	statefulSetInformer := informers.NewSharedInformerFactory(c.kubeclientset, 0).Apps().V1().StatefulSets()
+	assert.NoError(t, statefulSetInformer.Informer().GetIndexer().Add(&appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vmi-system-es-master",
+			Namespace: constants.VerrazzanoSystemNamespace,
+			Labels:    map[string]string{constants.VMOLabel: "system"},
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: config.ElasticsearchMaster.Name, Image: testOldImage}},
+				},
+			},
+		},
+	}))
+	c.statefulSetLister = statefulSetInformer.Lister()
+	return c
+}
+
+func TestReconcileOpenSearchUpgrade(t *testing.T) {
+	targetVersion, targetImage := config.ESWaitTargetVersion, config.ElasticsearchMaster.Image
+	config.ESWaitTargetVersion, config.ElasticsearchMaster.Image = "1.3.6", testNewImage
+	defer func() {
+		config.ESWaitTargetVersion, config.ElasticsearchMaster.Image = targetVersion, targetImage
+	}()
+
+	const (
+		green         = `{"status": "green"}`
+		yellow        = `{"status": "yellow"}`
+		oldNodes      = `{"nodes": {"a": {"name": "data-0", "version": "1.2.4", "roles": ["data"]}, "b": {"name": "master-0", "version": "1.2.4", "roles": ["master"]}}}`
+		dataUpgraded  = `{"nodes": {"a": {"name": "data-0", "version": "1.3.6", "roles": ["data"]}, "b": {"name": "master-0", "version": "1.2.4", "roles": ["master"]}}}`
+		upgradedNodes = `{"nodes": {"a": {"name": "data-0", "version": "1.3.6", "roles": ["data"]}, "b": {"name": "master-0", "version": "1.3.6", "roles": ["master"]}}}`
+		masterUpgrade = `{"nodes": {"a": {"name": "data-0", "version": "1.2.4", "roles": ["data"]}, "b": {"name": "master-0", "version": "1.3.6", "roles": ["master"]}}}`
+		downgrade     = `{"nodes": {"a": {"name": "data-0", "version": "2.3.0", "roles": ["data"]}}}`
+	)
+	expired := metav1.NewTime(time.Now().Add(-2 * upgradeNodeTimeout))
+	inProgress := func(phase vmcontrollerv1.UpgradePhase) *vmcontrollerv1.UpgradeStatus {
+		return &vmcontrollerv1.UpgradeStatus{
+			Phase:         phase,
+			FromVersion:   "1.2.4",
+			TargetVersion: "1.3.6",
+			FromImage:     testOldImage,
+			TargetImage:   testNewImage,
+		}
+	}
+
+	var tests = []struct {
+		name      string
+		upgrade   *vmcontrollerv1.UpgradeStatus
+		restart   *vmcontrollerv1.NodeRestartStatus
+		nodes     string
+		health    string
+		phase     vmcontrollerv1.UpgradePhase
+		fromImage string
+	}{
+		{"no upgrade when nodes run the target version", nil, nil, upgradedNodes, green, "", ""},
+		{"starts an upgrade", nil, nil, oldNodes, green, vmcontrollerv1.UpgradeInProgress, testOldImage},
+		{"rejects an unsupported upgrade", nil, nil, downgrade, green, vmcontrollerv1.UpgradeRejected, testOldImage},
+		{"pauses when the cluster is not green", inProgress(vmcontrollerv1.UpgradeInProgress), nil, dataUpgraded, yellow, vmcontrollerv1.UpgradePaused, testOldImage},
+		{"resumes when the cluster is green", inProgress(vmcontrollerv1.UpgradePaused), nil, dataUpgraded, green, vmcontrollerv1.UpgradeInProgress, testOldImage},
+		{"completes when all nodes are upgraded", inProgress(vmcontrollerv1.UpgradeInProgress), nil, upgradedNodes, green, vmcontrollerv1.UpgradeCompleted, testOldImage},
+		{
+			"rolls back when a node does not rejoin before masters are upgraded",
+			inProgress(vmcontrollerv1.UpgradeInProgress),
+			&vmcontrollerv1.NodeRestartStatus{Owner: "data-0", StartTime: &expired},
+			oldNodes,
+			yellow,
+			vmcontrollerv1.UpgradeRollingBack,
+			testOldImage,
+		},
+		{
+			"fails when a node does not rejoin after a master was upgraded",
+			inProgress(vmcontrollerv1.UpgradeInProgress),
+			&vmcontrollerv1.NodeRestartStatus{Owner: "data-0", StartTime: &expired},
+			masterUpgrade,
+			yellow,
+			vmcontrollerv1.UpgradeFailed,
+			testOldImage,
+		},
+		{"rolled back when all nodes run the previous version", inProgress(vmcontrollerv1.UpgradeRollingBack), nil, oldNodes, green, vmcontrollerv1.UpgradeRolledBack, testOldImage},
+		{"a rolled back upgrade is not retried", inProgress(vmcontrollerv1.UpgradeRolledBack), nil, oldNodes, green, vmcontrollerv1.UpgradeRolledBack, testOldImage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vmo := testvmo.DeepCopy()
+			vmo.Spec.Elasticsearch.Enabled = true
+			vmo.Status.Elasticsearch.Upgrade = tt.upgrade
+			vmo.Status.Elasticsearch.Restart = tt.restart
+			assert.NoError(t, reconcileOpenSearchUpgrade(makeUpgradeController(t, tt.nodes, tt.health), vmo))
+			upgrade := vmo.Status.Elasticsearch.Upgrade
+			if tt.phase == "" {
+				assert.Nil(t, upgrade)
+				return
+			}
+			assert.Equal(t, tt.phase, upgrade.Phase, upgrade.Message)
+			assert.Equal(t, tt.fromImage, upgrade.FromImage)
+		})
+	}
+}
+
+// TestReconcileOpenSearchUpgradeFakeOpenSearch Tests an upgrade over several reconciles of a fake OpenSearch cluster
+// GIVEN a fake OpenSearch cluster whose nodes run the previous version
+// WHEN the nodes are upgraded one by one, with the cluster turning yellow while a node restarts
+// THEN the upgrade starts, pauses while the cluster is not green, and completes once all nodes are upgraded
+func TestReconcileOpenSearchUpgradeFakeOpenSearch(t *testing.T) {
+	targetVersion, targetImage := config.ESWaitTargetVersion, config.ElasticsearchMaster.Image
+	config.ESWaitTargetVersion, config.ElasticsearchMaster.Image = "1.3.6", testNewImage
+	defer func() {
+		config.ESWaitTargetVersion, config.ElasticsearchMaster.Image = targetVersion, targetImage
+	}()
+
+	cluster := opensearchtest.NewFakeOpenSearch()
+	defer cluster.Close()
+	cluster.AddNode("data-0", "1.2.4", "data")
+	cluster.AddNode("master-0", "1.2.4", "master")
+	osClient := opensearch.NewOSClient()
+	osClient.DoHTTP = cluster.DoHTTP
+	c := makeUpgradeController(t, "", "")
+	c.osClient = osClient
+
+	vmo := testvmo.DeepCopy()
+	vmo.Spec.Elasticsearch.Enabled = true
+	reconcile := func(expected vmcontrollerv1.UpgradePhase) {
+		assert.NoError(t, reconcileOpenSearchUpgrade(c, vmo))
+		assert.Equal(t, expected, vmo.Status.Elasticsearch.Upgrade.Phase, vmo.Status.Elasticsearch.Upgrade.Message)
+	}
+
+	reconcile(vmcontrollerv1.UpgradeInProgress)
+	assert.Equal(t, testOldImage, vmo.Status.Elasticsearch.Upgrade.FromImage)
+	cluster.SetHealth("yellow")
+	cluster.AddNode("data-0", "1.3.6", "data")
+	reconcile(vmcontrollerv1.UpgradePaused)
+	cluster.SetHealth("green")
+	reconcile(vmcontrollerv1.UpgradeInProgress)
+	cluster.AddNode("master-0", "1.3.6", "master")
+	reconcile(vmcontrollerv1.UpgradeCompleted)
+	for _, node := range vmo.Status.Elasticsearch.Upgrade.Nodes {
+		assert.True(t, node.Upgraded, node.Name)
+	}
+}
+
+func TestPinOpenSearchImage(t *testing.T) {
+	targetImage := config.ElasticsearchMaster.Image
+	config.ElasticsearchMaster.Image = testNewImage
+	defer func() {
+		config.ElasticsearchMaster.Image = targetImage
+	}()
+
+	for _, phase := range []vmcontrollerv1.UpgradePhase{vmcontrollerv1.UpgradeInProgress, vmcontrollerv1.UpgradeRollingBack} {
+		vmo := testvmo.DeepCopy()
+		vmo.Status.Elasticsearch.Upgrade = &vmcontrollerv1.UpgradeStatus{Phase: phase, FromImage: testOldImage, TargetImage: testNewImage}
+		podSpec := &corev1.PodSpec{
+			InitContainers: []corev1.Container{{Image: testNewImage}},
+			Containers:     []corev1.Container{{Image: testNewImage}, {Image: "proxy"}},
+		}
+		pinOpenSearchImage(vmo, podSpec, nil)
+		expected := testNewImage
+		if phase == vmcontrollerv1.UpgradeRollingBack {
+			expected = testOldImage
+		}
+		assert.Equal(t, expected, podSpec.InitContainers[0].Image)
+		assert.Equal(t, expected, podSpec.Containers[0].Image)
+		assert.Equal(t, "proxy", podSpec.Containers[1].Image)
+	}
+
+	// a rejected upgrade from an unknown image keeps the image of the existing pod spec
+	vmo := testvmo.DeepCopy()
+	vmo.Status.Elasticsearch.Upgrade = &vmcontrollerv1.UpgradeStatus{Phase: vmcontrollerv1.UpgradeRejected, TargetImage: testNewImage}
+	podSpec := &corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "install-plugins", Image: testNewImage}},
+		Containers:     []corev1.Container{{Name: "es-master", Image: testNewImage}},
+	}
+	existing := &corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "install-plugins", Image: testOldImage}},
+		Containers:     []corev1.Container{{Name: "es-master", Image: testOldImage}},
+	}
+	pinOpenSearchImage(vmo, podSpec, existing)
+	assert.Equal(t, testOldImage, podSpec.InitContainers[0].Image)
+	assert.Equal(t, testOldImage, podSpec.Containers[0].Image)
+}
+
+func TestIsMasterUpgradeAllowed(t *testing.T) {
+	vmo := testvmo.DeepCopy()
+	vmo.Spec.Elasticsearch.DataNode.Replicas = 1
+	vmo.Spec.Elasticsearch.IngestNode.Replicas = 0
+	assert.True(t, isMasterUpgradeAllowed(vmo))
+
+	vmo.Status.Elasticsearch.Upgrade = &vmcontrollerv1.UpgradeStatus{
+		Phase: vmcontrollerv1.UpgradeInProgress,
+		Nodes: []vmcontrollerv1.NodeUpgradeStatus{
+			{Name: "data-0", Roles: []string{"data"}},
+			{Name: "master-0", Roles: []string{"master"}},
+		},
+	}
+	assert.False(t, isMasterUpgradeAllowed(vmo))
+	vmo.Status.Elasticsearch.Upgrade.Nodes[0].Upgraded = true
+	assert.True(t, isMasterUpgradeAllowed(vmo))
+	vmo.Status.Elasticsearch.Upgrade.Nodes = vmo.Status.Elasticsearch.Upgrade.Nodes[1:]
+	assert.False(t, isMasterUpgradeAllowed(vmo), "expected nodes are missing from the cluster")
+}
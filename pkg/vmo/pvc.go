@@ -27,6 +27,8 @@ import (
 func CreatePersistentVolumeClaims(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) (map[string]string, error) {
 	// Update storage with the new API
 	setPerNodeStorage(vmo)
+	// Grow data node storage that is filling up, so the PVCs are resized below to the grown size
+	autoGrowDataStorage(controller, vmo)
 	// Inspect the Storage Class to use
 	storageClass, err := determineStorageClass(controller, vmo.Spec.StorageClass)
 	if err != nil {
@@ -34,7 +36,7 @@ func CreatePersistentVolumeClaims(controller *Controller, vmo *vmcontrollerv1.Ve
 	}
 	storageClassInfo := parseStorageClassInfo(storageClass, controller.operatorConfig)
 
-	expectedPVCs, err := pvcs.New(vmo, storageClass.Name)
+	expectedPVCs, err := pvcs.New(withGrownStorage(vmo), storageClass.Name)
 	if err != nil {
 		controller.log.Errorf("Failed to create PVC specs for VMI %s: %v", vmo.Name, err)
 		return nil, err
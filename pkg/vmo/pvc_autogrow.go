@@ -0,0 +1,246 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/nodes"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+const (
+	defaultAutoGrowThresholdPercent = 80
+	defaultAutoGrowMinInterval      = time.Hour
+	// Event reasons for automatic storage growth
+	reasonStorageAutoGrow         = "StorageAutoGrow"
+	reasonStorageAutoGrowLimit    = "StorageAutoGrowLimit"
+	reasonStorageAutoGrowRejected = "StorageAutoGrowRejected"
+)
+
+//autoGrowDataStorage grows the storage size of data node pools whose disk usage crossed the autogrow threshold.
+// The grown size is recorded in the VMI status, and the PVCs are then resized by the existing PVC resize logic,
+// before the nodes reach the flood stage watermark and their indices become read-only.
+// Invalid policies are rejected in the status, and do not stop the reconcile of the VMI.
+func autoGrowDataStorage(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) {
+	if !vmo.Spec.Elasticsearch.Enabled {
+		vmo.Status.Elasticsearch.StorageGrowth = nil
+		return
+	}
+	// master eligible nodes run as StatefulSets, whose volume claim templates cannot be resized
+	for _, node := range nodes.MasterNodes(vmo) {
+		if node.AutoGrow != nil {
+			rejectAutoGrow(controller, vmo, node, "autogrow is not supported for master eligible nodes")
+		}
+	}
+	var pools []vmcontrollerv1.ElasticsearchNode
+	for _, node := range nodes.DataNodes(vmo) {
+		if node.AutoGrow == nil || node.Storage == nil || node.Storage.Size == "" || node.Replicas < 1 {
+			continue
+		}
+		if err := validateAutoGrow(node); err != nil {
+			rejectAutoGrow(controller, vmo, node, err.Error())
+			continue
+		}
+		// the policy was fixed
+		if growth := getStorageGrowthStatus(vmo, node.Name); growth != nil {
+			growth.Message = ""
+		}
+		pools = append(pools, node)
+	}
+	if len(pools) < 1 {
+		return
+	}
+	usage, err := controller.osClient.GetNodeDiskUsage(vmo)
+	if err != nil {
+		// the cluster may not be reachable yet
+		controller.log.Debugf("Unable to get OpenSearch disk usage: %v", err)
+		return
+	}
+
+	for _, node := range pools {
+		policy := node.AutoGrow
+		threshold := int(policy.ThresholdPercent)
+		if threshold < 1 {
+			threshold = defaultAutoGrowThresholdPercent
+		}
+		diskPercent := getPoolDiskUsage(vmo, node, usage)
+		if diskPercent < threshold {
+			continue
+		}
+		minInterval := defaultAutoGrowMinInterval
+		if policy.MinInterval != "" {
+			// validated above
+			minInterval, _ = time.ParseDuration(policy.MinInterval)
+		}
+		growth := getStorageGrowthStatus(vmo, node.Name)
+		if growth != nil && growth.Time != nil && time.Since(growth.Time.Time) < minInterval {
+			controller.log.Oncef("Storage of node pool %s was grown at %s, and will not grow again before %s", node.Name, growth.Time, minInterval)
+			continue
+		}
+		current := getStorageSize(vmo, node)
+		size, grown, err := getGrownStorageSize(current, policy)
+		if err != nil {
+			rejectAutoGrow(controller, vmo, node, err.Error())
+			continue
+		}
+		if !grown {
+			// the warning is rate limited like growth
+			controller.log.Oncef("Storage of node pool %s is at its maximum size %s, with disk usage at %d%%", node.Name, policy.MaxSize, diskPercent)
+			controller.recorder.Eventf(vmo, corev1.EventTypeWarning, reasonStorageAutoGrowLimit,
+				"Storage of node pool %s cannot grow beyond %s, with disk usage at %d%%", node.Name, policy.MaxSize, diskPercent)
+			setStorageGrowthStatus(vmo, node.Name, current, diskPercent)
+			continue
+		}
+		controller.log.Oncef("Growing storage of node pool %s from %s to %s, with disk usage at %d%%", node.Name, current, size, diskPercent)
+		controller.recorder.Eventf(vmo, corev1.EventTypeNormal, reasonStorageAutoGrow,
+			"Growing storage of node pool %s from %s to %s, with disk usage at %d%%", node.Name, current, size, diskPercent)
+		setStorageGrowthStatus(vmo, node.Name, size, diskPercent)
+	}
+}
+
+//validateAutoGrow checks the interval and sizes of the autogrow policy of a node pool
+func validateAutoGrow(node vmcontrollerv1.ElasticsearchNode) error {
+	policy := node.AutoGrow
+	if policy.MinInterval != "" {
+		if _, err := time.ParseDuration(policy.MinInterval); err != nil {
+			return fmt.Errorf("invalid interval %s: %v", policy.MinInterval, err)
+		}
+	}
+	if _, _, err := getGrownStorageSize(node.Storage.Size, policy); err != nil {
+		return err
+	}
+	return nil
+}
+
+//rejectAutoGrow records why the autogrow policy of a node pool is rejected, with an Event when the reason is new
+func rejectAutoGrow(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, node vmcontrollerv1.ElasticsearchNode, message string) {
+	if growth := getStorageGrowthStatus(vmo, node.Name); growth != nil && growth.Message == message {
+		return
+	}
+	controller.log.Errorf("Autogrow policy of node pool %s is rejected: %s", node.Name, message)
+	controller.recorder.Eventf(vmo, corev1.EventTypeWarning, reasonStorageAutoGrowRejected, "Autogrow policy of node pool %s is rejected: %s", node.Name, message)
+	if existing := getStorageGrowthStatus(vmo, node.Name); existing != nil {
+		// the storage keeps the size it was grown to
+		existing.Message = message
+		return
+	}
+	growth := vmcontrollerv1.StorageGrowthStatus{Node: node.Name, Message: message}
+	if node.Storage != nil {
+		growth.Size = node.Storage.Size
+	}
+	vmo.Status.Elasticsearch.StorageGrowth = append(vmo.Status.Elasticsearch.StorageGrowth, growth)
+}
+
+//getStorageSize returns the storage size of a data node pool, which is the larger of the size in the spec and the
+// size it was grown to
+func getStorageSize(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, node vmcontrollerv1.ElasticsearchNode) string {
+	if node.Storage == nil {
+		return ""
+	}
+	growth := getStorageGrowthStatus(vmo, node.Name)
+	if growth == nil {
+		return node.Storage.Size
+	}
+	specSize, err := resource.ParseQuantity(node.Storage.Size)
+	if err != nil {
+		return node.Storage.Size
+	}
+	grownSize, err := resource.ParseQuantity(growth.Size)
+	if err != nil || grownSize.Cmp(specSize) <= 0 {
+		return node.Storage.Size
+	}
+	return growth.Size
+}
+
+//withGrownStorage returns the VMI with the storage of its data node pools set to the size they were grown to.
+// The VMI is copied when a size changes, so the grown sizes never end up in the VMI spec.
+func withGrownStorage(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) *vmcontrollerv1.VerrazzanoMonitoringInstance {
+	grown := vmo
+	for i, node := range nodes.DataNodes(vmo) {
+		if node.Storage == nil || node.Storage.Size == "" {
+			continue
+		}
+		size := getStorageSize(vmo, node)
+		if size == node.Storage.Size {
+			continue
+		}
+		if grown == vmo {
+			grown = vmo.DeepCopy()
+		}
+		// the nodes of the copy are in the same order, and share their storage with the copy's spec
+		nodes.DataNodes(grown)[i].Storage.Size = size
+	}
+	return grown
+}
+
+//getPoolDiskUsage returns the highest disk usage percentage of the node pool's data nodes
+func getPoolDiskUsage(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, node vmcontrollerv1.ElasticsearchNode, usage map[string]int) int {
+	highest := 0
+	for i := 0; i < int(node.Replicas); i++ {
+		deploymentName := resources.GetMetaName(vmo.Name, fmt.Sprintf("%s-%d", node.Name, i))
+		for nodeName, percent := range usage {
+			if isDeploymentPodName(deploymentName, nodeName) && percent > highest {
+				highest = percent
+			}
+		}
+	}
+	return highest
+}
+
+//getGrownStorageSize adds the policy step to the storage size, up to the policy maximum.
+// Returns false if the storage is already at its maximum size.
+func getGrownStorageSize(size string, policy *vmcontrollerv1.StorageAutoGrow) (string, bool, error) {
+	current, err := resource.ParseQuantity(size)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid storage size %s: %v", size, err)
+	}
+	step, err := resource.ParseQuantity(policy.Step)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid step %s: %v", policy.Step, err)
+	}
+	maxSize, err := resource.ParseQuantity(policy.MaxSize)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid maximum size %s: %v", policy.MaxSize, err)
+	}
+	if step.Sign() <= 0 {
+		return "", false, fmt.Errorf("step %s must be positive", policy.Step)
+	}
+	if current.Cmp(maxSize) >= 0 {
+		return size, false, nil
+	}
+	current.Add(step)
+	if current.Cmp(maxSize) > 0 {
+		current = maxSize
+	}
+	return current.String(), true, nil
+}
+
+func getStorageGrowthStatus(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, nodeName string) *vmcontrollerv1.StorageGrowthStatus {
+	for i := range vmo.Status.Elasticsearch.StorageGrowth {
+		if vmo.Status.Elasticsearch.StorageGrowth[i].Node == nodeName {
+			return &vmo.Status.Elasticsearch.StorageGrowth[i]
+		}
+	}
+	return nil
+}
+
+func setStorageGrowthStatus(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, nodeName, size string, diskPercent int) {
+	now := metav1.Now()
+	growth := vmcontrollerv1.StorageGrowthStatus{
+		Node:        nodeName,
+		Size:        size,
+		DiskPercent: diskPercent,
+		Time:        &now,
+	}
+	if existing := getStorageGrowthStatus(vmo, nodeName); existing != nil {
+		*existing = growth
+		return
+	}
+	vmo.Status.Elasticsearch.StorageGrowth = append(vmo.Status.Elasticsearch.StorageGrowth, growth)
+}
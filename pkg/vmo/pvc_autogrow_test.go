@@ -0,0 +1,227 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"testing"
+	"time"
+)
+
+func TestAutoGrowDataStorage(t *testing.T) {
+	const allocation = `[
+		{"node": "vmi-system-es-data-0-5d8f7b9c4-abcde", "disk.percent": "85"},
+		{"node": "vmi-system-es-data-1-7c9d8f6b5-fghij", "disk.percent": "40"},
+		{"node": "vmi-system-es-data-hot-0-6b7c8d9f4-klmno", "disk.percent": "99"},
+		{"node": "UNASSIGNED", "disk.percent": null}
+	]`
+	recent := metav1.NewTime(time.Now().Add(-time.Minute))
+	var tests = []struct {
+		name      string
+		size      string
+		policy    *vmcontrollerv1.StorageAutoGrow
+		growth    []vmcontrollerv1.StorageGrowthStatus
+		finalSize string
+		event     string
+	}{
+		{
+			"no growth without a policy",
+			"50Gi",
+			nil,
+			nil,
+			"50Gi",
+			"",
+		},
+		{
+			"no growth below the threshold",
+			"50Gi",
+			&vmcontrollerv1.StorageAutoGrow{ThresholdPercent: 90, Step: "10Gi", MaxSize: "100Gi"},
+			nil,
+			"50Gi",
+			"",
+		},
+		{
+			"grows by a step above the default threshold",
+			"50Gi",
+			&vmcontrollerv1.StorageAutoGrow{Step: "10Gi", MaxSize: "100Gi"},
+			nil,
+			"60Gi",
+			"Normal StorageAutoGrow Growing storage of node pool es-data from 50Gi to 60Gi, with disk usage at 85%",
+		},
+		{
+			"grows up to the maximum size",
+			"95Gi",
+			&vmcontrollerv1.StorageAutoGrow{Step: "10Gi", MaxSize: "100Gi"},
+			nil,
+			"100Gi",
+			"Normal StorageAutoGrow Growing storage of node pool es-data from 95Gi to 100Gi, with disk usage at 85%",
+		},
+		{
+			"warns at the maximum size",
+			"100Gi",
+			&vmcontrollerv1.StorageAutoGrow{Step: "10Gi", MaxSize: "100Gi"},
+			nil,
+			"100Gi",
+			"Warning StorageAutoGrowLimit Storage of node pool es-data cannot grow beyond 100Gi, with disk usage at 85%",
+		},
+		{
+			"growth is rate limited",
+			"60Gi",
+			&vmcontrollerv1.StorageAutoGrow{Step: "10Gi", MaxSize: "100Gi"},
+			[]vmcontrollerv1.StorageGrowthStatus{{Node: "es-data", Size: "60Gi", Time: &recent}},
+			"60Gi",
+			"",
+		},
+		{
+			"grows again after the minimum interval",
+			"60Gi",
+			&vmcontrollerv1.StorageAutoGrow{Step: "10Gi", MaxSize: "100Gi", MinInterval: "30s"},
+			[]vmcontrollerv1.StorageGrowthStatus{{Node: "es-data", Size: "60Gi", Time: &recent}},
+			"70Gi",
+			"Normal StorageAutoGrow Growing storage of node pool es-data from 60Gi to 70Gi, with disk usage at 85%",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := makeOpenSearchController(map[string]string{"/_cat/allocation": allocation})
+			recorder := c.recorder.(*record.FakeRecorder)
+			vmo := testvmo.DeepCopy()
+			vmo.Spec.Elasticsearch.Enabled = true
+			vmo.Spec.Elasticsearch.DataNode = vmcontrollerv1.ElasticsearchNode{
+				Name:     "es-data",
+				Replicas: 2,
+				Storage:  &vmcontrollerv1.Storage{Size: tt.size},
+				AutoGrow: tt.policy,
+			}
+			vmo.Status.Elasticsearch.StorageGrowth = tt.growth
+			autoGrowDataStorage(c, vmo)
+			// the grown size is kept in the status, and the spec is left as is
+			assert.Equal(t, tt.size, vmo.Spec.Elasticsearch.DataNode.Storage.Size)
+			assert.Equal(t, tt.finalSize, getStorageSize(vmo, vmo.Spec.Elasticsearch.DataNode))
+			assert.Equal(t, tt.finalSize, withGrownStorage(vmo).Spec.Elasticsearch.DataNode.Storage.Size)
+			select {
+			case event := <-recorder.Events:
+				assert.Equal(t, tt.event, event)
+				growth := getStorageGrowthStatus(vmo, "es-data")
+				assert.NotNil(t, growth)
+				assert.Equal(t, tt.finalSize, growth.Size)
+				assert.Equal(t, 85, growth.DiskPercent)
+			default:
+				assert.Empty(t, tt.event)
+			}
+		})
+	}
+}
+
+func TestGetGrownStorageSize(t *testing.T) {
+	_, _, err := getGrownStorageSize("50Gi", &vmcontrollerv1.StorageAutoGrow{Step: "0", MaxSize: "100Gi"})
+	assert.Error(t, err)
+	_, _, err = getGrownStorageSize("50Gi", &vmcontrollerv1.StorageAutoGrow{Step: "10Gi", MaxSize: "lots"})
+	assert.Error(t, err)
+	size, grown, err := getGrownStorageSize("1Ti", &vmcontrollerv1.StorageAutoGrow{Step: "512Gi", MaxSize: "2Ti"})
+	assert.NoError(t, err)
+	assert.True(t, grown)
+	assert.Equal(t, "1536Gi", size)
+}
+
+// TestAutoGrowRejectedOnMasterNodes tests that the autogrow policy of master eligible nodes is rejected
+// GIVEN a master node pool with an autogrow policy
+// WHEN autoGrowDataStorage is called twice
+// THEN the rejection is recorded in the status, with a single Warning Event
+func TestAutoGrowRejectedOnMasterNodes(t *testing.T) {
+	c := makeOpenSearchController(map[string]string{})
+	recorder := c.recorder.(*record.FakeRecorder)
+	vmo := testvmo.DeepCopy()
+	vmo.Spec.Elasticsearch.Enabled = true
+	vmo.Spec.Elasticsearch.MasterNode = vmcontrollerv1.ElasticsearchNode{
+		Name:     "es-master",
+		Replicas: 3,
+		Roles:    []vmcontrollerv1.NodeRole{vmcontrollerv1.MasterRole, vmcontrollerv1.DataRole},
+		Storage:  &vmcontrollerv1.Storage{Size: "50Gi"},
+		AutoGrow: &vmcontrollerv1.StorageAutoGrow{Step: "10Gi", MaxSize: "100Gi"},
+	}
+	autoGrowDataStorage(c, vmo)
+	autoGrowDataStorage(c, vmo)
+	assert.Len(t, recorder.Events, 1)
+	assert.Equal(t, "Warning StorageAutoGrowRejected Autogrow policy of node pool es-master is rejected: autogrow is not supported for master eligible nodes", <-recorder.Events)
+	growth := getStorageGrowthStatus(vmo, "es-master")
+	assert.NotNil(t, growth)
+	assert.Equal(t, "autogrow is not supported for master eligible nodes", growth.Message)
+	assert.Equal(t, "50Gi", vmo.Spec.Elasticsearch.MasterNode.Storage.Size)
+}
+
+// TestAutoGrowRejectsInvalidPolicies tests that invalid autogrow policies are rejected without failing the reconcile
+// GIVEN data node pools with an invalid interval, step or maximum size, one of which was already grown
+// WHEN autoGrowDataStorage is called twice
+// THEN each rejection is recorded in the status with a single Warning Event, the grown size is kept,
+// and the rejection is cleared once the policy is fixed
+func TestAutoGrowRejectsInvalidPolicies(t *testing.T) {
+	c := makeOpenSearchController(map[string]string{})
+	recorder := c.recorder.(*record.FakeRecorder)
+	vmo := testvmo.DeepCopy()
+	vmo.Spec.Elasticsearch.Enabled = true
+	vmo.Spec.Elasticsearch.DataNode = vmcontrollerv1.ElasticsearchNode{
+		Name:     "es-data",
+		Replicas: 2,
+		Storage:  &vmcontrollerv1.Storage{Size: "50Gi"},
+		AutoGrow: &vmcontrollerv1.StorageAutoGrow{Step: "10Gi", MaxSize: "100Gi", MinInterval: "soon"},
+	}
+	vmo.Spec.Elasticsearch.Nodes = []vmcontrollerv1.ElasticsearchNode{
+		{
+			Name:     "es-data-hot",
+			Replicas: 1,
+			Roles:    []vmcontrollerv1.NodeRole{vmcontrollerv1.DataRole},
+			Storage:  &vmcontrollerv1.Storage{Size: "50Gi"},
+			AutoGrow: &vmcontrollerv1.StorageAutoGrow{Step: "0", MaxSize: "100Gi"},
+		},
+		{
+			Name:     "es-data-warm",
+			Replicas: 1,
+			Roles:    []vmcontrollerv1.NodeRole{vmcontrollerv1.DataRole},
+			Storage:  &vmcontrollerv1.Storage{Size: "50Gi"},
+			AutoGrow: &vmcontrollerv1.StorageAutoGrow{Step: "10Gi", MaxSize: "lots"},
+		},
+	}
+	vmo.Status.Elasticsearch.StorageGrowth = []vmcontrollerv1.StorageGrowthStatus{{Node: "es-data", Size: "60Gi", DiskPercent: 85}}
+	autoGrowDataStorage(c, vmo)
+	autoGrowDataStorage(c, vmo)
+	assert.Len(t, recorder.Events, 3)
+	for _, node := range []string{"es-data", "es-data-hot", "es-data-warm"} {
+		growth := getStorageGrowthStatus(vmo, node)
+		assert.NotNil(t, growth)
+		assert.NotEmpty(t, growth.Message)
+		assert.Contains(t, <-recorder.Events, "Warning StorageAutoGrowRejected Autogrow policy of node pool "+node+" is rejected: ")
+	}
+	assert.Equal(t, "60Gi", getStorageSize(vmo, vmo.Spec.Elasticsearch.DataNode))
+	assert.Equal(t, "50Gi", getStorageSize(vmo, vmo.Spec.Elasticsearch.Nodes[0]))
+
+	vmo.Spec.Elasticsearch.DataNode.AutoGrow.MinInterval = "1h"
+	autoGrowDataStorage(c, vmo)
+	growth := getStorageGrowthStatus(vmo, "es-data")
+	assert.Empty(t, growth.Message)
+	assert.Equal(t, "60Gi", growth.Size)
+}
+
+// TestGetStorageSize tests that the larger of the spec and the grown size is applied
+func TestGetStorageSize(t *testing.T) {
+	vmo := testvmo.DeepCopy()
+	node := vmcontrollerv1.ElasticsearchNode{Name: "es-data", Storage: &vmcontrollerv1.Storage{Size: "50Gi"}}
+	vmo.Spec.Elasticsearch.DataNode = node
+	assert.Equal(t, "50Gi", getStorageSize(vmo, node))
+	assert.Same(t, vmo, withGrownStorage(vmo))
+
+	vmo.Status.Elasticsearch.StorageGrowth = []vmcontrollerv1.StorageGrowthStatus{{Node: "es-data", Size: "60Gi"}}
+	assert.Equal(t, "60Gi", getStorageSize(vmo, node))
+	grown := withGrownStorage(vmo)
+	assert.Equal(t, "60Gi", grown.Spec.Elasticsearch.DataNode.Storage.Size)
+	assert.Equal(t, "50Gi", vmo.Spec.Elasticsearch.DataNode.Storage.Size)
+
+	// the spec wins once it is raised above the grown size
+	vmo.Spec.Elasticsearch.DataNode.Storage.Size = "80Gi"
+	assert.Equal(t, "80Gi", getStorageSize(vmo, vmo.Spec.Elasticsearch.DataNode))
+}
@@ -0,0 +1,173 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+const remoteWriteKey = "remote_write"
+
+//addRemoteWriteConfigs renders the remote storages of the VMI into the remote_write section of the Prometheus
+// configuration. When the remote storages are invalid, the error is reported in the VMI status and the remote_write
+// section of the Prometheus ConfigMap is kept as is.
+func addRemoteWriteConfigs(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, prometheusConfig string) (string, error) {
+	var remoteWrite []interface{}
+	err := validateRemoteWrite(controller, vmo)
+	if err == nil {
+		remoteWrite, err = resources.GetPrometheusRemoteWriteConfigs(vmo)
+	}
+	if err != nil {
+		controller.log.Errorf("Failed to render the remote write configs of VMI %s: %v", vmo.Name, err)
+		vmo.Status.Prometheus.RemoteWriteError = err.Error()
+		if remoteWrite, err = getExistingRemoteWriteConfigs(controller, vmo); err != nil {
+			return "", err
+		}
+	} else {
+		vmo.Status.Prometheus.RemoteWriteError = ""
+	}
+	if len(remoteWrite) == 0 {
+		return prometheusConfig, nil
+	}
+
+	var config map[interface{}]interface{}
+	if err := yaml.Unmarshal([]byte(prometheusConfig), &config); err != nil {
+		return "", err
+	}
+	config[remoteWriteKey] = remoteWrite
+	rendered, err := yaml.Marshal(&config)
+	if err != nil {
+		return "", err
+	}
+	return string(rendered), nil
+}
+
+//getExistingRemoteWriteConfigs returns the remote_write section of the Prometheus ConfigMap
+func getExistingRemoteWriteConfigs(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) ([]interface{}, error) {
+	existingConfig, err := getConfigMap(controller, vmo.Namespace, vmo.Spec.Prometheus.ConfigMap)
+	if err != nil || existingConfig == nil {
+		return nil, err
+	}
+	var config map[interface{}]interface{}
+	if err := yaml.Unmarshal([]byte(existingConfig.Data["prometheus.yml"]), &config); err != nil {
+		return nil, err
+	}
+	remoteWrite, _ := config[remoteWriteKey].([]interface{})
+	return remoteWrite, nil
+}
+
+//keepRemoteWriteSecrets keeps the remote storage volumes of the existing Prometheus pod spec while the remote storages
+// are invalid, since the remote_write section kept in the Prometheus ConfigMap references the files mounted from them,
+// and a missing Secret would keep the pod from starting
+func keepRemoteWriteSecrets(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, podSpec, existing *corev1.PodSpec) {
+	if vmo.Status.Prometheus.RemoteWriteError == "" {
+		return
+	}
+	keepVolumes(podSpec, existing, resources.RemoteWriteVolumePrefix)
+}
+
+//validateRemoteWrite checks the remote storages of the VMI, and that the Secrets they reference exist, so Prometheus
+// is not reloaded with a configuration it rejects
+func validateRemoteWrite(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	names := map[string]bool{}
+	for _, remote := range vmo.Spec.Prometheus.RemoteWrite {
+		if names[remote.Name] {
+			return fmt.Errorf("remote write %s is defined more than once", remote.Name)
+		}
+		names[remote.Name] = true
+		if u, err := url.Parse(remote.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("remote write %s has an invalid URL %s", remote.Name, remote.URL)
+		}
+
+		var secrets []*corev1.SecretKeySelector
+		if remote.BasicAuth != nil {
+			if remote.BearerTokenSecret != nil {
+				return fmt.Errorf("remote write %s has both basic auth and a bearer token", remote.Name)
+			}
+			if remote.BasicAuth.Username == "" {
+				return fmt.Errorf("remote write %s has basic auth without a username", remote.Name)
+			}
+			secrets = append(secrets, &remote.BasicAuth.PasswordSecret)
+		}
+		secrets = append(secrets, remote.BearerTokenSecret)
+		if remote.TLS != nil {
+			if (remote.TLS.CertSecret == nil) != (remote.TLS.KeySecret == nil) {
+				return fmt.Errorf("remote write %s needs both a client certificate and key", remote.Name)
+			}
+			secrets = append(secrets, remote.TLS.CASecret, remote.TLS.CertSecret, remote.TLS.KeySecret)
+		}
+		for _, secret := range secrets {
+			if err := validateRemoteWriteSecret(controller, vmo, remote.Name, secret); err != nil {
+				return err
+			}
+		}
+
+		if queue := remote.QueueConfig; queue != nil {
+			for _, duration := range []string{queue.BatchSendDeadline, queue.MinBackoff, queue.MaxBackoff} {
+				if _, err := time.ParseDuration(duration); duration != "" && err != nil {
+					return fmt.Errorf("remote write %s has an invalid queue duration %s", remote.Name, duration)
+				}
+			}
+			if queue.MaxShards > 0 && queue.MinShards > queue.MaxShards {
+				return fmt.Errorf("remote write %s has more min shards than max shards", remote.Name)
+			}
+		}
+		for i, relabel := range remote.WriteRelabelConfigs {
+			if err := validateRelabelConfig(relabel); err != nil {
+				return fmt.Errorf("write relabel config %d of remote write %s is invalid: %v", i, remote.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+//validateRemoteWriteSecret checks that the key of a Secret referenced by a remote storage exists, unless it is optional
+func validateRemoteWriteSecret(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, remote string, selector *corev1.SecretKeySelector) error {
+	if selector == nil || (selector.Optional != nil && *selector.Optional) {
+		return nil
+	}
+	secret, err := controller.secretLister.Secrets(vmo.Namespace).Get(selector.Name)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return fmt.Errorf("secret %s of remote write %s does not exist", selector.Name, remote)
+		}
+		return err
+	}
+	if _, ok := secret.Data[selector.Key]; !ok {
+		return fmt.Errorf("secret %s of remote write %s has no key %s", selector.Name, remote, selector.Key)
+	}
+	return nil
+}
+
+//validateRelabelConfig checks the fields each relabel action needs. Regexes are anchored, as they are by Prometheus.
+func validateRelabelConfig(relabel vmcontrollerv1.RelabelConfig) error {
+	if relabel.Regex != "" {
+		if _, err := regexp.Compile("^(?:" + relabel.Regex + ")$"); err != nil {
+			return err
+		}
+	}
+	switch relabel.Action {
+	case "", "replace":
+		if relabel.TargetLabel == "" {
+			return fmt.Errorf("action replace needs a target label")
+		}
+	case "hashmod":
+		if relabel.TargetLabel == "" || relabel.Modulus <= 0 {
+			return fmt.Errorf("action hashmod needs a target label and a modulus")
+		}
+	case "keep", "drop":
+		if len(relabel.SourceLabels) == 0 {
+			return fmt.Errorf("action %s needs source labels", relabel.Action)
+		}
+	}
+	return nil
+}
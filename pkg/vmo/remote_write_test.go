@@ -0,0 +1,137 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	vmctl "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+// getRemoteWrite returns the remote_write section of the Prometheus ConfigMap
+func getRemoteWrite(t *testing.T, client *fake.Clientset, vmo *vmctl.VerrazzanoMonitoringInstance) []interface{} {
+	cm, err := client.CoreV1().ConfigMaps(vmo.Namespace).Get(context.TODO(), vmo.Spec.Prometheus.ConfigMap, metav1.GetOptions{})
+	assert.NoError(t, err)
+	var config map[interface{}]interface{}
+	assert.NoError(t, yaml.Unmarshal([]byte(cm.Data["prometheus.yml"]), &config))
+	remoteWrite, _ := config["remote_write"].([]interface{})
+	return remoteWrite
+}
+
+// TestRemoteWrite tests rendering the remote storages of a VMI into the Prometheus configuration
+// GIVEN a VMI with a remote storage
+// WHEN CreateConfigmaps is called as the remote storages change
+// THEN the remote storages are rendered into the remote_write section, invalid remote storages are reported in the
+// status without changing the configuration, and the section is removed with the remote storages
+func TestRemoteWrite(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "thanos-auth", Namespace: constants.VerrazzanoSystemNamespace},
+		Data:       map[string][]byte{"password": []byte("secret")},
+	})
+	controller := &Controller{
+		kubeclientset:   client,
+		configMapLister: &simpleConfigMapLister{kubeClient: client},
+		secretLister:    &simpleSecretLister{kubeClient: client},
+		log:             vzlog.DefaultLogger(),
+	}
+	vmo := &vmctl.VerrazzanoMonitoringInstance{}
+	vmo.Name = constants.VMODefaultName
+	vmo.Namespace = constants.VerrazzanoSystemNamespace
+	vmo.Spec.Prometheus.ConfigMap = "myPrometheusConfigMap"
+	password := corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "thanos-auth"}, Key: "password"}
+	vmo.Spec.Prometheus.RemoteWrite = []vmctl.RemoteWrite{{
+		Name:      "thanos",
+		URL:       "https://thanos.example.com/api/v1/receive",
+		BasicAuth: &vmctl.RemoteWriteBasicAuth{Username: "vmi", PasswordSecret: password},
+	}}
+
+	assert.NoError(t, CreateConfigmaps(controller, vmo))
+	remoteWrite := getRemoteWrite(t, client, vmo)
+	assert.Len(t, remoteWrite, 1)
+	thanos := remoteWrite[0].(map[interface{}]interface{})
+	assert.Equal(t, "https://thanos.example.com/api/v1/receive", thanos["url"])
+	assert.Equal(t, "/etc/prometheus/remote-write/thanos/password", thanos["basic_auth"].(map[interface{}]interface{})["password_file"])
+	assert.Empty(t, vmo.Status.Prometheus.RemoteWriteError)
+
+	// an update of the remote storage is rendered
+	vmo.Spec.Prometheus.RemoteWrite[0].QueueConfig = &vmctl.RemoteWriteQueueConfig{MaxShards: 5}
+	assert.NoError(t, CreateConfigmaps(controller, vmo))
+	thanos = getRemoteWrite(t, client, vmo)[0].(map[interface{}]interface{})
+	assert.Equal(t, 5, thanos["queue_config"].(map[interface{}]interface{})["max_shards"])
+
+	invalid := []struct {
+		name   string
+		remote vmctl.RemoteWrite
+		err    string
+	}{
+		{"bad URL", vmctl.RemoteWrite{Name: "bad", URL: "thanos:9090"}, "has an invalid URL"},
+		{"missing secret key", vmctl.RemoteWrite{Name: "bad", URL: "http://thanos", BearerTokenSecret: &corev1.SecretKeySelector{LocalObjectReference: password.LocalObjectReference, Key: "token"}}, "has no key token"},
+		{"basic auth and bearer token", vmctl.RemoteWrite{Name: "bad", URL: "http://thanos", BasicAuth: &vmctl.RemoteWriteBasicAuth{Username: "vmi", PasswordSecret: password}, BearerTokenSecret: &password}, "both basic auth and a bearer token"},
+		{"bad duration", vmctl.RemoteWrite{Name: "bad", URL: "http://thanos", QueueConfig: &vmctl.RemoteWriteQueueConfig{MinBackoff: "5x"}}, "invalid queue duration 5x"},
+		{"bad regex", vmctl.RemoteWrite{Name: "bad", URL: "http://thanos", WriteRelabelConfigs: []vmctl.RelabelConfig{{SourceLabels: []string{"__name__"}, Regex: "(", Action: "drop"}}}, "write relabel config 0 of remote write bad is invalid"},
+		{"hashmod without modulus", vmctl.RemoteWrite{Name: "bad", URL: "http://thanos", WriteRelabelConfigs: []vmctl.RelabelConfig{{TargetLabel: "shard", Action: "hashmod"}}}, "needs a target label and a modulus"},
+		{"duplicate name", vmctl.RemoteWrite{Name: "thanos", URL: "http://thanos"}, "defined more than once"},
+	}
+	valid := vmo.Spec.Prometheus.RemoteWrite[0]
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			vmo.Spec.Prometheus.RemoteWrite = []vmctl.RemoteWrite{valid, tt.remote}
+			assert.NoError(t, CreateConfigmaps(controller, vmo))
+			assert.Contains(t, vmo.Status.Prometheus.RemoteWriteError, tt.err)
+			// the remote storages rendered before are kept
+			remoteWrite := getRemoteWrite(t, client, vmo)
+			assert.Len(t, remoteWrite, 1)
+			assert.Equal(t, "thanos", remoteWrite[0].(map[interface{}]interface{})["name"])
+		})
+	}
+
+	vmo.Spec.Prometheus.RemoteWrite = nil
+	assert.NoError(t, CreateConfigmaps(controller, vmo))
+	assert.Empty(t, getRemoteWrite(t, client, vmo))
+	assert.Empty(t, vmo.Status.Prometheus.RemoteWriteError)
+}
+
+// TestKeepRemoteWriteSecrets tests keeping the mounted Secrets of remote storages which are invalid
+// GIVEN a Prometheus pod spec which mounts the Secret of a remote storage
+// WHEN keepRemoteWriteSecrets is called with and without an error validating the remote storages
+// THEN the Secrets of the existing pod spec are kept while the error is reported, so a missing Secret is not mounted
+func TestKeepRemoteWriteSecrets(t *testing.T) {
+	vmo := &vmctl.VerrazzanoMonitoringInstance{}
+	newPodSpec := func(name, secret string) *corev1.PodSpec {
+		vmo.Spec.Prometheus.RemoteWrite = []vmctl.RemoteWrite{{
+			Name:              name,
+			URL:               "https://" + name + ".example.com/api/v1/receive",
+			BearerTokenSecret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: secret}, Key: "token"},
+		}}
+		podSpec := &corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "prometheus", VolumeMounts: []corev1.VolumeMount{{Name: "config-volume"}}}},
+			Volumes:    []corev1.Volume{{Name: "config-volume"}},
+		}
+		resources.AddRemoteWriteSecrets(podSpec, vmo)
+		return podSpec
+	}
+	existing := newPodSpec("thanos", "thanos-token")
+	podSpec := newPodSpec("cortex", "missing-token")
+
+	keepRemoteWriteSecrets(vmo, podSpec, existing)
+	assert.Equal(t, "remote-write-cortex", podSpec.Volumes[1].Name)
+
+	vmo.Status.Prometheus.RemoteWriteError = "failed to get Secret missing-token"
+	keepRemoteWriteSecrets(vmo, podSpec, existing)
+	assert.Equal(t, existing, podSpec)
+
+	// a new pod spec mounts no Secrets until the remote storages are valid
+	podSpec = newPodSpec("cortex", "missing-token")
+	keepRemoteWriteSecrets(vmo, podSpec, nil)
+	assert.Equal(t, []corev1.Volume{{Name: "config-volume"}}, podSpec.Volumes)
+	assert.Equal(t, []corev1.VolumeMount{{Name: "config-volume"}}, podSpec.Containers[0].VolumeMounts)
+}
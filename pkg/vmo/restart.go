@@ -0,0 +1,183 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//prepareOpenSearchNodeRestart restricts shard allocation to primaries before an OpenSearch node is restarted
+func prepareOpenSearchNodeRestart(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, owner, pod string) error {
+	controller.log.Oncef("Preparing OpenSearch cluster for the restart of %s", owner)
+	if err := controller.osClient.PrepareNodeRestart(vmo); err != nil {
+		return err
+	}
+	now := metav1.Now()
+	vmo.Status.Elasticsearch.Restart = &vmcontrollerv1.NodeRestartStatus{
+		Owner:     owner,
+		Pod:       pod,
+		StartTime: &now,
+	}
+	return nil
+}
+
+//finishOpenSearchNodeRestart re-enables shard allocation once the restarted node has rejoined the cluster
+func finishOpenSearchNodeRestart(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	restart := vmo.Status.Elasticsearch.Restart
+	if restart == nil {
+		return nil
+	}
+	if err := controller.osClient.FinishNodeRestart(vmo); err != nil {
+		return err
+	}
+	controller.log.Oncef("OpenSearch node restart of %s has finished", restart.Owner)
+	vmo.Status.Elasticsearch.Restart = nil
+	return nil
+}
+
+//finishOrphanedOpenSearchNodeRestart re-enables shard allocation if the restarted node's Deployment or StatefulSet no longer exists
+func finishOrphanedOpenSearchNodeRestart(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	restart := vmo.Status.Elasticsearch.Restart
+	if restart == nil {
+		return nil
+	}
+	if !vmo.Spec.Elasticsearch.Enabled {
+		vmo.Status.Elasticsearch.Restart = nil
+		return nil
+	}
+	_, err := controller.deploymentLister.Deployments(vmo.Namespace).Get(restart.Owner)
+	if err == nil || !k8serrors.IsNotFound(err) {
+		return err
+	}
+	_, err = controller.statefulSetLister.StatefulSets(vmo.Namespace).Get(restart.Owner)
+	if err == nil || !k8serrors.IsNotFound(err) {
+		return err
+	}
+	return finishOpenSearchNodeRestart(controller, vmo)
+}
+
+//isDeploymentRestarted returns true if the Deployment has rolled out the restarted generation and its pod is available
+func isDeploymentRestarted(deployment *appsv1.Deployment, restart *vmcontrollerv1.NodeRestartStatus) bool {
+	return deployment.Status.ObservedGeneration >= restart.Generation &&
+		deployment.Status.UpdatedReplicas == *deployment.Spec.Replicas &&
+		deployment.Status.AvailableReplicas == *deployment.Spec.Replicas
+}
+
+//restartStatefulSetPods restarts the OpenSearch statefulset pods which are not running the latest revision, one at a time.
+// Pods that are not ready are deleted right away, as they are not serving any shards.
+func restartStatefulSetPods(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, statefulSet *appsv1.StatefulSet) error {
+	updateRevision := statefulSet.Status.UpdateRevision
+	if updateRevision == "" {
+		return nil
+	}
+	pods, err := getStatefulSetPods(controller, statefulSet)
+	if err != nil {
+		return err
+	}
+
+	if restart := vmo.Status.Elasticsearch.Restart; restart != nil {
+		if restart.Owner != statefulSet.Name {
+			return nil
+		}
+		for _, pod := range pods {
+			if pod.Name == restart.Pod && pod.DeletionTimestamp == nil && isPodReady(pod) && pod.Labels[appsv1.ControllerRevisionHashLabelKey] == updateRevision {
+				// the next pod is restarted once the cluster is green again
+				return finishOpenSearchNodeRestart(controller, vmo)
+			}
+		}
+		controller.log.Progressf("Waiting for OpenSearch pod %s to rejoin the cluster", restart.Pod)
+		return nil
+	}
+
+	// during an upgrade, master nodes are upgraded after all other nodes
+	if !isMasterUpgradeAllowed(vmo) {
+		controller.log.Oncef("OpenSearch pods of %s are waiting for the non-master nodes to be upgraded", statefulSet.Name)
+		return nil
+	}
+
+	var candidates []*corev1.Pod
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || pod.Labels[appsv1.ControllerRevisionHashLabelKey] == updateRevision {
+			continue
+		}
+		if !isPodReady(pod) {
+			controller.log.Oncef("Deleting OpenSearch pod %s, which is not ready", pod.Name)
+			if err := deletePod(controller, pod); err != nil {
+				return err
+			}
+			continue
+		}
+		candidates = append(candidates, pod)
+	}
+	if len(candidates) < 1 {
+		return nil
+	}
+	candidate := candidates[0]
+	// the elected master is restarted last, so the cluster elects a new master only once
+	if len(candidates) > 1 {
+		if master, err := controller.osClient.GetElectedMaster(vmo); err == nil && candidate.Name == master {
+			candidate = candidates[1]
+		}
+	}
+	if err := controller.osClient.IsGreen(vmo); err != nil {
+		controller.log.Oncef("OpenSearch pod %s was not restarted, since the cluster is not ready", candidate.Name)
+		return nil
+	}
+	if err := prepareOpenSearchNodeRestart(controller, vmo, statefulSet.Name, candidate.Name); err != nil {
+		return err
+	}
+	return deletePod(controller, candidate)
+}
+
+//getStatefulSetPods returns the statefulset's pods, ordered from the highest ordinal to the lowest
+func getStatefulSetPods(controller *Controller, statefulSet *appsv1.StatefulSet) ([]*corev1.Pod, error) {
+	selector, err := metav1.LabelSelectorAsSelector(statefulSet.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	podList, err := controller.kubeclientset.CoreV1().Pods(statefulSet.Namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	ordinals := map[string]int{}
+	var pods []*corev1.Pod
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		ordinal, err := strconv.Atoi(strings.TrimPrefix(pod.Name, statefulSet.Name+"-"))
+		if err != nil || !strings.HasPrefix(pod.Name, statefulSet.Name+"-") {
+			continue
+		}
+		ordinals[pod.Name] = ordinal
+		pods = append(pods, pod)
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return ordinals[pods[i].Name] > ordinals[pods[j].Name]
+	})
+	return pods, nil
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func deletePod(controller *Controller, pod *corev1.Pod) error {
+	err := controller.kubeclientset.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.Name, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
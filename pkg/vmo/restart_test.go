@@ -0,0 +1,159 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+var testGreenClusterResponses = map[string]string{
+	"/_cluster/health":   `{"status": "green"}`,
+	"/_nodes/settings":   `{"nodes": {}}`,
+	"/_cluster/settings": `{}`,
+	"/_flush":            `{}`,
+}
+
+func makeStatefulSetPod(name, revision string, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: constants.VerrazzanoSystemNamespace,
+			Labels: map[string]string{
+				"app":                                 "system-es-master",
+				appsv1.ControllerRevisionHashLabelKey: revision,
+			},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func TestRestartStatefulSetPods(t *testing.T) {
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vmi-system-es-master",
+			Namespace: constants.VerrazzanoSystemNamespace,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "system-es-master"}},
+		},
+		Status: appsv1.StatefulSetStatus{UpdateRevision: "new"},
+	}
+	var tests = []struct {
+		name      string
+		pods      []*corev1.Pod
+		restart   *vmcontrollerv1.NodeRestartStatus
+		deleted   []string
+		restarted string
+	}{
+		{
+			"nothing to restart when all pods are updated",
+			[]*corev1.Pod{
+				makeStatefulSetPod("vmi-system-es-master-0", "new", true),
+				makeStatefulSetPod("vmi-system-es-master-1", "new", true),
+			},
+			nil,
+			nil,
+			"",
+		},
+		{
+			"restarts the outdated pod with the highest ordinal",
+			[]*corev1.Pod{
+				makeStatefulSetPod("vmi-system-es-master-0", "old", true),
+				makeStatefulSetPod("vmi-system-es-master-1", "old", true),
+			},
+			nil,
+			[]string{"vmi-system-es-master-1"},
+			"vmi-system-es-master-1",
+		},
+		{
+			"deletes pods that are not ready",
+			[]*corev1.Pod{
+				makeStatefulSetPod("vmi-system-es-master-0", "old", true),
+				makeStatefulSetPod("vmi-system-es-master-1", "old", false),
+			},
+			nil,
+			[]string{"vmi-system-es-master-1", "vmi-system-es-master-0"},
+			"vmi-system-es-master-0",
+		},
+		{
+			"waits for the restarted pod to rejoin",
+			[]*corev1.Pod{
+				makeStatefulSetPod("vmi-system-es-master-0", "old", true),
+				makeStatefulSetPod("vmi-system-es-master-1", "new", false),
+			},
+			&vmcontrollerv1.NodeRestartStatus{Owner: "vmi-system-es-master", Pod: "vmi-system-es-master-1"},
+			nil,
+			"vmi-system-es-master-1",
+		},
+		{
+			"finishes the restart once the pod has rejoined",
+			[]*corev1.Pod{
+				makeStatefulSetPod("vmi-system-es-master-0", "old", true),
+				makeStatefulSetPod("vmi-system-es-master-1", "new", true),
+			},
+			&vmcontrollerv1.NodeRestartStatus{Owner: "vmi-system-es-master", Pod: "vmi-system-es-master-1"},
+			nil,
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objects []runtime.Object
+			for _, pod := range tt.pods {
+				objects = append(objects, pod)
+			}
+			c := makeOpenSearchController(testGreenClusterResponses)
+			c.kubeclientset = fake.NewSimpleClientset(objects...)
+			vmo := testvmo.DeepCopy()
+			vmo.Spec.Elasticsearch.Enabled = true
+			vmo.Status.Elasticsearch.Restart = tt.restart
+
+			assert.NoError(t, restartStatefulSetPods(c, vmo, sts))
+			var deleted []string
+			for _, pod := range tt.pods {
+				if _, err := c.kubeclientset.CoreV1().Pods(pod.Namespace).Get(context.TODO(), pod.Name, metav1.GetOptions{}); err != nil {
+					deleted = append(deleted, pod.Name)
+				}
+			}
+			assert.ElementsMatch(t, tt.deleted, deleted)
+			if tt.restarted == "" {
+				assert.Nil(t, vmo.Status.Elasticsearch.Restart)
+			} else {
+				assert.Equal(t, tt.restarted, vmo.Status.Elasticsearch.Restart.Pod)
+			}
+		})
+	}
+}
+
+func TestIsDeploymentRestarted(t *testing.T) {
+	replicas := int32(1)
+	deployment := &appsv1.Deployment{
+		Spec: appsv1.DeploymentSpec{Replicas: &replicas},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 2,
+			UpdatedReplicas:    1,
+			AvailableReplicas:  1,
+		},
+	}
+	assert.True(t, isDeploymentRestarted(deployment, &vmcontrollerv1.NodeRestartStatus{Generation: 2}))
+	assert.False(t, isDeploymentRestarted(deployment, &vmcontrollerv1.NodeRestartStatus{Generation: 3}))
+	deployment.Status.AvailableReplicas = 0
+	assert.False(t, isDeploymentRestarted(deployment, &vmcontrollerv1.NodeRestartStatus{Generation: 2}))
+}
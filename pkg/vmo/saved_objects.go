@@ -0,0 +1,43 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	dashboards "github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch_dashboards"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+//provisionDashboardsSavedObjects imports the saved objects of the ConfigMaps labelled for the VMI into OpenSearch Dashboards
+func provisionDashboardsSavedObjects(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	objects, err := getDashboardsSavedObjects(controller, vmo)
+	if err != nil {
+		return err
+	}
+	return controller.osDashboardsClient.ProvisionSavedObjects(controller.log, vmo, objects)
+}
+
+//seedDashboardsTenants seeds the OpenSearch Dashboards tenants of the VMI, which must already exist
+func seedDashboardsTenants(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	objects, err := getDashboardsSavedObjects(controller, vmo)
+	if err != nil {
+		return err
+	}
+	return controller.osDashboardsClient.SeedTenants(controller.log, vmo, objects)
+}
+
+//getDashboardsSavedObjects returns the saved objects of the ConfigMaps labelled for the VMI
+func getDashboardsSavedObjects(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) ([]dashboards.ProvisionedObject, error) {
+	var configMaps []*corev1.ConfigMap
+	if vmo.Spec.Kibana.Enabled {
+		selector := labels.SelectorFromSet(map[string]string{constants.DashboardsSavedObjectsLabel: vmo.Name})
+		var err error
+		if configMaps, err = controller.configMapLister.ConfigMaps(vmo.Namespace).List(selector); err != nil {
+			return nil, err
+		}
+	}
+	return dashboards.GetSavedObjects(controller.log, configMaps)
+}
@@ -0,0 +1,237 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSavedObjectsBackupInterval = 24 * time.Hour
+	defaultSavedObjectsBackupRetain   = 7
+	savedObjectsBackupSecret          = "Secret"
+	// globalTenant is the name OpenSearch reserves for the global tenant
+	globalTenant             = "global_tenant"
+	savedObjectsExportSuffix = ".ndjson"
+	// exports are named after their time, so they sort from oldest to newest
+	savedObjectsExportTimeFormat = "20060102-150405"
+	// maxSavedObjectsExportBytes is the size limit of ConfigMaps and Secrets
+	maxSavedObjectsExportBytes = 1024 * 1024
+)
+
+//backupDashboardsSavedObjects restores the chosen export of the OpenSearch Dashboards saved objects, and exports the saved
+// objects of the global tenant and of each tenant once the backup interval has passed. Exports beyond the retained
+// number are deleted, oldest first. Exports have no owner, so they outlive the VMI.
+func backupDashboardsSavedObjects(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	backup := vmo.Spec.Kibana.Backup
+	if !vmo.Spec.Kibana.Enabled || backup == nil {
+		return nil
+	}
+	if vmo.Status.Kibana.Backup == nil {
+		vmo.Status.Kibana.Backup = &vmcontrollerv1.SavedObjectsBackupStatus{}
+	}
+	status := vmo.Status.Kibana.Backup
+
+	// a failed restore does not hold back the exports
+	var restoreErr error
+	if backup.Restore != status.Restored {
+		if backup.Restore != "" {
+			restoreErr = restoreDashboardsSavedObjects(controller, vmo, backup.Restore)
+		}
+		if restoreErr == nil {
+			status.Restored = backup.Restore
+		}
+	}
+
+	interval := defaultSavedObjectsBackupInterval
+	if backup.Interval != "" {
+		var err error
+		if interval, err = time.ParseDuration(backup.Interval); err != nil {
+			return fmt.Errorf("invalid saved objects backup interval: %v", err)
+		}
+	}
+	if last := getLastExportAttemptTime(status); last != nil && time.Since(last.Time) < interval {
+		return restoreErr
+	}
+	now := metav1.Now()
+	data, err := exportDashboardsSavedObjects(controller, vmo)
+	if err != nil {
+		return err
+	}
+	size := 0
+	for _, export := range data {
+		size += len(export)
+	}
+	if size > maxSavedObjectsExportBytes {
+		// the saved objects do not shrink by themselves, so the export is only attempted again once the interval has passed
+		status.LastExportError = fmt.Sprintf("the export of the saved objects has %d bytes, which exceeds the size limit of %d bytes", size, maxSavedObjectsExportBytes)
+		status.LastExportErrorTime = &now
+		return errors.New(status.LastExportError)
+	}
+	name, err := writeDashboardsSavedObjectsExport(controller, vmo, data, now)
+	if err != nil {
+		return err
+	}
+	status.LastExport = name
+	status.LastExportTime = &now
+	status.LastExportError = ""
+	status.LastExportErrorTime = nil
+	if err := pruneDashboardsSavedObjectsExports(controller, vmo); err != nil {
+		return err
+	}
+	return restoreErr
+}
+
+//getLastExportAttemptTime returns the time of the most recent export, or of the most recent failed export if it is later
+func getLastExportAttemptTime(status *vmcontrollerv1.SavedObjectsBackupStatus) *metav1.Time {
+	if status.LastExportErrorTime != nil && (status.LastExportTime == nil || status.LastExportTime.Before(status.LastExportErrorTime)) {
+		return status.LastExportErrorTime
+	}
+	return status.LastExportTime
+}
+
+//exportDashboardsSavedObjects exports the saved objects of each tenant, keyed by the tenant
+func exportDashboardsSavedObjects(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) (map[string][]byte, error) {
+	tenants := []string{globalTenant}
+	for _, tenant := range vmo.Spec.Kibana.Tenants {
+		tenants = append(tenants, tenant.Name)
+	}
+	data := map[string][]byte{}
+	for _, tenant := range tenants {
+		header := tenant
+		if tenant == globalTenant {
+			header = ""
+		}
+		export, err := controller.osDashboardsClient.ExportSavedObjects(vmo, header)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export the saved objects of tenant %s: %v", tenant, err)
+		}
+		data[tenant+savedObjectsExportSuffix] = export
+	}
+	return data, nil
+}
+
+//writeDashboardsSavedObjectsExport writes an export of the saved objects to a new ConfigMap or Secret, with a key per tenant
+func writeDashboardsSavedObjectsExport(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, data map[string][]byte, now metav1.Time) (string, error) {
+	meta := metav1.ObjectMeta{
+		Name:      resources.GetMetaName(vmo.Name, "saved-objects-"+now.UTC().Format(savedObjectsExportTimeFormat)),
+		Namespace: vmo.Namespace,
+		Labels:    map[string]string{constants.DashboardsSavedObjectsBackupLabel: vmo.Name},
+	}
+	var err error
+	if vmo.Spec.Kibana.Backup.Kind == savedObjectsBackupSecret {
+		secret := &corev1.Secret{ObjectMeta: meta, Type: corev1.SecretTypeOpaque, Data: data}
+		_, err = controller.kubeclientset.CoreV1().Secrets(vmo.Namespace).Create(context.TODO(), secret, metav1.CreateOptions{})
+	} else {
+		configMap := &corev1.ConfigMap{ObjectMeta: meta, Data: map[string]string{}}
+		for key, export := range data {
+			configMap.Data[key] = string(export)
+		}
+		_, err = controller.kubeclientset.CoreV1().ConfigMaps(vmo.Namespace).Create(context.TODO(), configMap, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return "", err
+	}
+	controller.log.Oncef("Exported the OpenSearch Dashboards saved objects of %d tenants to %s", len(data), meta.Name)
+	return meta.Name, nil
+}
+
+//restoreDashboardsSavedObjects imports the saved objects of an export into their tenants
+func restoreDashboardsSavedObjects(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, name string) error {
+	data := map[string]string{}
+	if vmo.Spec.Kibana.Backup.Kind == savedObjectsBackupSecret {
+		secret, err := controller.secretLister.Secrets(vmo.Namespace).Get(name)
+		if err != nil {
+			return fmt.Errorf("failed to get the saved objects export %s: %v", name, err)
+		}
+		for key, export := range secret.Data {
+			data[key] = string(export)
+		}
+	} else {
+		configMap, err := controller.configMapLister.ConfigMaps(vmo.Namespace).Get(name)
+		if err != nil {
+			return fmt.Errorf("failed to get the saved objects export %s: %v", name, err)
+		}
+		data = configMap.Data
+	}
+
+	var keys []string
+	for key := range data {
+		if strings.HasSuffix(key, savedObjectsExportSuffix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		tenant := strings.TrimSuffix(key, savedObjectsExportSuffix)
+		if tenant == globalTenant {
+			tenant = ""
+		}
+		if err := controller.osDashboardsClient.RestoreSavedObjects(controller.log, vmo, tenant, name+"/"+key, data[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//pruneDashboardsSavedObjectsExports deletes the oldest exports beyond the retained number. The export being restored is kept.
+func pruneDashboardsSavedObjectsExports(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	backup := vmo.Spec.Kibana.Backup
+	retain := defaultSavedObjectsBackupRetain
+	if backup.Retain > 0 {
+		retain = int(backup.Retain)
+	}
+	selector := labels.SelectorFromSet(map[string]string{constants.DashboardsSavedObjectsBackupLabel: vmo.Name})
+	var names []string
+	if backup.Kind == savedObjectsBackupSecret {
+		secrets, err := controller.secretLister.Secrets(vmo.Namespace).List(selector)
+		if err != nil {
+			return err
+		}
+		for _, secret := range secrets {
+			names = append(names, secret.Name)
+		}
+	} else {
+		configMaps, err := controller.configMapLister.ConfigMaps(vmo.Namespace).List(selector)
+		if err != nil {
+			return err
+		}
+		for _, configMap := range configMaps {
+			names = append(names, configMap.Name)
+		}
+	}
+	// the lister may not hold the export which was just created
+	if !contains(names, vmo.Status.Kibana.Backup.LastExport) {
+		names = append(names, vmo.Status.Kibana.Backup.LastExport)
+	}
+	sort.Strings(names)
+	for i := 0; i < len(names)-retain; i++ {
+		if names[i] == backup.Restore {
+			continue
+		}
+		var err error
+		if backup.Kind == savedObjectsBackupSecret {
+			err = controller.kubeclientset.CoreV1().Secrets(vmo.Namespace).Delete(context.TODO(), names[i], metav1.DeleteOptions{})
+		} else {
+			err = controller.kubeclientset.CoreV1().ConfigMaps(vmo.Namespace).Delete(context.TODO(), names[i], metav1.DeleteOptions{})
+		}
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete the saved objects export %s: %v", names[i], err)
+		}
+		controller.log.Oncef("Deleted the saved objects export %s, which is beyond the %d retained exports", names[i], retain)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	dashboards "github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch_dashboards"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	"io"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// savedObjectsBackupRequests records the export and import requests of each tenant
+type savedObjectsBackupRequests struct {
+	exports []string
+	imports []string
+}
+
+func newSavedObjectsBackupController(client *fake.Clientset, requests *savedObjectsBackupRequests) *Controller {
+	osd := dashboards.NewOSDashboardsClient()
+	osd.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		tenant := request.Header.Get("securitytenant")
+		body := `{"success": true, "successCount": 1}`
+		if strings.HasSuffix(request.URL.Path, "/_export") {
+			requests.exports = append(requests.exports, tenant)
+			body = `{"type": "dashboard", "id": "` + tenant + `"}` + "\n" + `{"exportedCount": 1, "missingRefCount": 0, "missingReferences": []}`
+		} else {
+			requests.imports = append(requests.imports, tenant)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	}
+	return &Controller{
+		kubeclientset:      client,
+		configMapLister:    &simpleConfigMapLister{kubeClient: client},
+		secretLister:       &simpleSecretLister{kubeClient: client},
+		osDashboardsClient: osd,
+		log:                vzlog.DefaultLogger(),
+	}
+}
+
+// TestBackupDashboardsSavedObjects Tests the scheduled export and the restore of saved objects
+// GIVEN a VMI with a tenant, and earlier exports of its saved objects
+// WHEN backupDashboardsSavedObjects is called
+// THEN the saved objects of each tenant are exported once per interval, exports beyond the retained number are deleted,
+// AND a chosen export is restored once
+func TestBackupDashboardsSavedObjects(t *testing.T) {
+	export := func(name string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: testvmo.Namespace,
+				Labels:    map[string]string{constants.DashboardsSavedObjectsBackupLabel: testvmo.Name},
+			},
+			Data: map[string]string{
+				"global_tenant.ndjson": `{"type": "dashboard", "id": "global"}`,
+				"bobs-books.ndjson":    `{"type": "dashboard", "id": "bobs-books"}`,
+			},
+		}
+	}
+	client := fake.NewSimpleClientset(export("vmi-system-saved-objects-20220101-000000"), export("vmi-system-saved-objects-20220102-000000"))
+	requests := &savedObjectsBackupRequests{}
+	c := newSavedObjectsBackupController(client, requests)
+	vmo := testvmo.DeepCopy()
+	vmo.Spec.Kibana.Enabled = true
+	vmo.Spec.Kibana.Tenants = []vmcontrollerv1.DashboardsTenant{{Name: "bobs-books"}}
+	vmo.Spec.Kibana.Backup = &vmcontrollerv1.SavedObjectsBackup{Retain: 2}
+
+	assert.NoError(t, backupDashboardsSavedObjects(c, vmo))
+	assert.Equal(t, []string{"", "bobs-books"}, requests.exports)
+	status := vmo.Status.Kibana.Backup
+	assert.NotNil(t, status.LastExportTime)
+	assert.True(t, strings.HasPrefix(status.LastExport, "vmi-system-saved-objects-"))
+	exported, err := client.CoreV1().ConfigMaps(vmo.Namespace).Get(context.TODO(), status.LastExport, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Contains(t, exported.Data["bobs-books.ndjson"], `"id": "bobs-books"`)
+	assert.Contains(t, exported.Data["global_tenant.ndjson"], `"id": ""`)
+	assert.Nil(t, exported.OwnerReferences)
+	exports, err := c.configMapLister.ConfigMaps(vmo.Namespace).List(labels.Everything())
+	assert.NoError(t, err)
+	assert.Len(t, exports, 2)
+	for _, configMap := range exports {
+		assert.NotEqual(t, "vmi-system-saved-objects-20220101-000000", configMap.Name)
+	}
+
+	// nothing is exported before the interval has passed
+	assert.NoError(t, backupDashboardsSavedObjects(c, vmo))
+	assert.Len(t, requests.exports, 2)
+
+	// a chosen export is restored into each tenant, only once
+	vmo.Spec.Kibana.Backup.Restore = "vmi-system-saved-objects-20220102-000000"
+	assert.NoError(t, backupDashboardsSavedObjects(c, vmo))
+	assert.Equal(t, []string{"bobs-books", ""}, requests.imports)
+	assert.Equal(t, vmo.Spec.Kibana.Backup.Restore, status.Restored)
+	assert.NoError(t, backupDashboardsSavedObjects(c, vmo))
+	assert.Len(t, requests.imports, 2)
+
+	// a missing export fails to restore
+	vmo.Spec.Kibana.Backup.Restore = "missing"
+	assert.Error(t, backupDashboardsSavedObjects(c, vmo))
+	assert.Equal(t, "vmi-system-saved-objects-20220102-000000", status.Restored)
+}
+
+// TestBackupDashboardsSavedObjectsSecret Tests exporting saved objects to Secrets
+// GIVEN a VMI backing up its saved objects to Secrets
+// WHEN backupDashboardsSavedObjects is called
+// THEN the saved objects are exported to a Secret
+func TestBackupDashboardsSavedObjectsSecret(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	c := newSavedObjectsBackupController(client, &savedObjectsBackupRequests{})
+	vmo := testvmo.DeepCopy()
+	vmo.Spec.Kibana.Enabled = true
+	vmo.Spec.Kibana.Backup = &vmcontrollerv1.SavedObjectsBackup{Kind: "Secret"}
+
+	assert.NoError(t, backupDashboardsSavedObjects(c, vmo))
+	secret, err := client.CoreV1().Secrets(vmo.Namespace).Get(context.TODO(), vmo.Status.Kibana.Backup.LastExport, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Contains(t, string(secret.Data["global_tenant.ndjson"]), "dashboard")
+	configMaps, err := client.CoreV1().ConfigMaps(vmo.Namespace).List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, configMaps.Items)
+
+	// nothing is backed up without a backup spec
+	vmo.Spec.Kibana.Backup = nil
+	vmo.Status.Kibana.Backup = nil
+	assert.NoError(t, backupDashboardsSavedObjects(c, vmo))
+	assert.Nil(t, vmo.Status.Kibana.Backup)
+}
+
+// TestBackupDashboardsSavedObjectsTooLarge Tests an export which exceeds the size limit
+// GIVEN saved objects which exceed the size limit of an export
+// WHEN backupDashboardsSavedObjects is called
+// THEN the failure and its time are recorded in the status, and the export is not attempted again before the interval has passed
+func TestBackupDashboardsSavedObjectsTooLarge(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	exports := 0
+	osd := dashboards.NewOSDashboardsClient()
+	osd.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		exports++
+		body := `{"type": "dashboard", "id": "` + strings.Repeat("x", maxSavedObjectsExportBytes) + `"}`
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	}
+	c := newSavedObjectsBackupController(client, &savedObjectsBackupRequests{})
+	c.osDashboardsClient = osd
+	vmo := testvmo.DeepCopy()
+	vmo.Spec.Kibana.Enabled = true
+	vmo.Spec.Kibana.Backup = &vmcontrollerv1.SavedObjectsBackup{}
+
+	assert.Error(t, backupDashboardsSavedObjects(c, vmo))
+	status := vmo.Status.Kibana.Backup
+	assert.Contains(t, status.LastExportError, "exceeds the size limit")
+	assert.NotNil(t, status.LastExportErrorTime)
+	assert.Nil(t, status.LastExportTime)
+	configMaps, err := client.CoreV1().ConfigMaps(vmo.Namespace).List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, configMaps.Items)
+
+	// the failed export is not attempted again before the interval has passed
+	assert.NoError(t, backupDashboardsSavedObjects(c, vmo))
+	assert.Equal(t, 1, exports)
+
+	// a successful export clears the failure
+	past := metav1.NewTime(status.LastExportErrorTime.Add(-defaultSavedObjectsBackupInterval))
+	status.LastExportErrorTime = &past
+	c.osDashboardsClient = newSavedObjectsBackupController(client, &savedObjectsBackupRequests{}).osDashboardsClient
+	assert.NoError(t, backupDashboardsSavedObjects(c, vmo))
+	assert.NotEmpty(t, status.LastExport)
+	assert.Empty(t, status.LastExportError)
+	assert.Nil(t, status.LastExportErrorTime)
+}
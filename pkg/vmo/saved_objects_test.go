@@ -0,0 +1,65 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	dashboards "github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch_dashboards"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	"io"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestProvisionDashboardsSavedObjects Tests provisioning the saved objects of labelled ConfigMaps
+// GIVEN ConfigMaps labelled for the VMI and for another VMI
+// WHEN provisionDashboardsSavedObjects is called
+// THEN only the saved objects of the ConfigMaps labelled for the VMI are imported
+func TestProvisionDashboardsSavedObjects(t *testing.T) {
+	savedObjectsConfigMap := func(name, vmiName, id string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: testvmo.Namespace,
+				Labels:    map[string]string{constants.DashboardsSavedObjectsLabel: vmiName},
+			},
+			Data: map[string]string{"dashboard.ndjson": `{"type": "dashboard", "id": "` + id + `"}`},
+		}
+	}
+	client := fake.NewSimpleClientset(
+		savedObjectsConfigMap("system-dashboards", testvmo.Name, "system"),
+		savedObjectsConfigMap("other-dashboards", "other", "other"),
+	)
+	osd := dashboards.NewOSDashboardsClient()
+	imports := 0
+	osd.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		imports++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"success": true, "successCount": 1}`)),
+		}, nil
+	}
+	c := &Controller{
+		configMapLister:    &simpleConfigMapLister{kubeClient: client},
+		osDashboardsClient: osd,
+		log:                vzlog.DefaultLogger(),
+	}
+	vmo := testvmo.DeepCopy()
+	vmo.Spec.Kibana.Enabled = true
+
+	assert.NoError(t, provisionDashboardsSavedObjects(c, vmo))
+	assert.Equal(t, 1, imports)
+	assert.Len(t, vmo.Status.Kibana.SavedObjects, 1)
+	assert.Equal(t, "system", vmo.Status.Kibana.SavedObjects[0].ID)
+	assert.Equal(t, "system-dashboards/dashboard.ndjson", vmo.Status.Kibana.SavedObjects[0].Source)
+
+	// unchanged objects are not imported again
+	assert.NoError(t, provisionDashboardsSavedObjects(c, vmo))
+	assert.Equal(t, 1, imports)
+}
@@ -0,0 +1,179 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const scrapeConfigsKey = "scrape_configs"
+
+//inlineCredentials are the fields of a scrape config which hold credentials, and have a *_file counterpart
+var inlineCredentials = []string{"password", "bearer_token", "credentials", "client_secret"}
+
+//mergeAdditionalScrapeConfigs appends the additional scrape configs of the VMI to the scrape configs of the Prometheus
+// configuration, and returns the merged configuration with the jobs of the additional scrape configs, and the jobs which
+// were merged before but have since been dropped. The jobs are only recorded in the VMI status once the configuration is
+// written, so dropped jobs are removed again when writing it fails. When the additional scrape configs cannot be merged,
+// the error is reported in the VMI status and the configuration is returned as is, so the scrape configs merged before are kept.
+func mergeAdditionalScrapeConfigs(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, prometheusConfig string) (string, []string, []string) {
+	status := &vmo.Status.Prometheus
+	merged, jobs, err := getMergedScrapeConfigs(controller, vmo, prometheusConfig)
+	if err != nil {
+		controller.log.Errorf("Failed to merge the additional scrape configs of VMI %s: %v", vmo.Name, err)
+		status.AdditionalScrapeConfigsError = err.Error()
+		return prometheusConfig, status.AdditionalScrapeConfigJobs, nil
+	}
+	status.AdditionalScrapeConfigsError = ""
+
+	var dropped []string
+	for _, job := range status.AdditionalScrapeConfigJobs {
+		if !contains(jobs, job) {
+			dropped = append(dropped, job)
+		}
+	}
+	return merged, jobs, dropped
+}
+
+//getMergedScrapeConfigs returns the Prometheus configuration with the additional scrape configs appended, in the order
+// of the sources and of the scrape configs in each source, and the job names of the additional scrape configs
+func getMergedScrapeConfigs(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, prometheusConfig string) (string, []string, error) {
+	if len(vmo.Spec.Prometheus.AdditionalScrapeConfigs) == 0 {
+		return prometheusConfig, nil, nil
+	}
+	var config map[interface{}]interface{}
+	if err := yaml.Unmarshal([]byte(prometheusConfig), &config); err != nil {
+		return "", nil, err
+	}
+	scrapeConfigs, _ := config[scrapeConfigsKey].([]interface{})
+	jobSources := map[string]string{}
+	for _, sc := range scrapeConfigs {
+		if scrapeConfig, ok := sc.(map[interface{}]interface{}); ok {
+			if job, ok := scrapeConfig["job_name"].(string); ok {
+				jobSources[job] = "the default scrape configs"
+			}
+		}
+	}
+
+	var jobs []string
+	for _, source := range vmo.Spec.Prometheus.AdditionalScrapeConfigs {
+		name, data, err := getScrapeConfigSource(controller, vmo, source)
+		if err != nil {
+			return "", nil, err
+		}
+		if data == "" {
+			continue
+		}
+		var additional []interface{}
+		if err := yaml.Unmarshal([]byte(data), &additional); err != nil {
+			return "", nil, fmt.Errorf("%s does not hold a list of scrape configs: %v", name, err)
+		}
+		for i, sc := range additional {
+			scrapeConfig, ok := sc.(map[interface{}]interface{})
+			if !ok {
+				return "", nil, fmt.Errorf("scrape config %d of %s is not a map", i, name)
+			}
+			job, ok := scrapeConfig["job_name"].(string)
+			if !ok || job == "" {
+				return "", nil, fmt.Errorf("scrape config %d of %s has no job_name", i, name)
+			}
+			if source.Secret != nil {
+				// the scrape configs are merged into the Prometheus ConfigMap, so the credentials of a Secret are
+				// read from its mounted keys instead
+				if field := findInlineCredential(scrapeConfig); field != "" {
+					return "", nil, fmt.Errorf("job %s of %s holds the inline credential %s, use %s_file with a key of the Secret under %s%s instead",
+						job, name, field, field, resources.ScrapeConfigSecretsPath, source.Secret.Name)
+				}
+			}
+			if other, ok := jobSources[job]; ok {
+				return "", nil, fmt.Errorf("job %s of %s is already defined by %s", job, name, other)
+			}
+			jobSources[job] = name
+			jobs = append(jobs, job)
+			scrapeConfigs = append(scrapeConfigs, scrapeConfig)
+		}
+	}
+	config[scrapeConfigsKey] = scrapeConfigs
+	merged, err := yaml.Marshal(&config)
+	if err != nil {
+		return "", nil, err
+	}
+	return string(merged), jobs, nil
+}
+
+//findInlineCredential returns the first field of a scrape config, or of the configs nested in it, which holds an inline
+// credential, or "" when the credentials are read from files
+func findInlineCredential(value interface{}) string {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		for key, nested := range v {
+			if field, ok := key.(string); ok && contains(inlineCredentials, field) {
+				return field
+			}
+			if field := findInlineCredential(nested); field != "" {
+				return field
+			}
+		}
+	case []interface{}:
+		for _, nested := range v {
+			if field := findInlineCredential(nested); field != "" {
+				return field
+			}
+		}
+	}
+	return ""
+}
+
+//keepScrapeConfigSecrets keeps the Secret volumes of the existing Prometheus pod spec while the additional scrape configs
+// cannot be merged, since the scrape configs merged before still reference the keys mounted from them, and a missing
+// Secret would keep the pod from starting
+func keepScrapeConfigSecrets(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, podSpec, existing *corev1.PodSpec) {
+	if vmo.Status.Prometheus.AdditionalScrapeConfigsError == "" {
+		return
+	}
+	keepVolumes(podSpec, existing, resources.ScrapeConfigSecretVolumePrefix)
+}
+
+//getScrapeConfigSource returns a description of the source of additional scrape configs and the data of its key. An
+// optional source which does not exist has no data.
+func getScrapeConfigSource(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, source vmcontrollerv1.ScrapeConfigSource) (string, string, error) {
+	switch {
+	case source.Secret != nil:
+		name := fmt.Sprintf("Secret %s key %s", source.Secret.Name, source.Secret.Key)
+		optional := source.Secret.Optional != nil && *source.Secret.Optional
+		secret, err := controller.secretLister.Secrets(vmo.Namespace).Get(source.Secret.Name)
+		if err != nil {
+			if k8serrors.IsNotFound(err) && optional {
+				return name, "", nil
+			}
+			return name, "", fmt.Errorf("failed to get %s: %v", name, err)
+		}
+		data, ok := secret.Data[source.Secret.Key]
+		if !ok && !optional {
+			return name, "", fmt.Errorf("%s does not exist", name)
+		}
+		return name, string(data), nil
+	case source.ConfigMap != nil:
+		name := fmt.Sprintf("ConfigMap %s key %s", source.ConfigMap.Name, source.ConfigMap.Key)
+		optional := source.ConfigMap.Optional != nil && *source.ConfigMap.Optional
+		configMap, err := controller.configMapLister.ConfigMaps(vmo.Namespace).Get(source.ConfigMap.Name)
+		if err != nil {
+			if k8serrors.IsNotFound(err) && optional {
+				return name, "", nil
+			}
+			return name, "", fmt.Errorf("failed to get %s: %v", name, err)
+		}
+		data, ok := configMap.Data[source.ConfigMap.Key]
+		if !ok && !optional {
+			return name, "", fmt.Errorf("%s does not exist", name)
+		}
+		return name, data, nil
+	}
+	return "", "", fmt.Errorf("an additional scrape config source has neither a Secret nor a ConfigMap")
+}
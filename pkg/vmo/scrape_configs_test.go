@@ -0,0 +1,192 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	vmctl "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"testing"
+)
+
+const (
+	testSecretScrapeConfigs = `
+- job_name: app-a
+  static_configs:
+  - targets: ['app-a:8080']
+- job_name: app-b
+  static_configs:
+  - targets: ['app-b:8080']
+`
+	testConfigMapScrapeConfigs = `
+- job_name: app-c
+  metrics_path: /stats
+  static_configs:
+  - targets: ['app-c:9090']
+`
+)
+
+// getScrapeJobs returns the job names of the scrape configs in the Prometheus ConfigMap, in order
+func getScrapeJobs(t *testing.T, client *fake.Clientset, vmo *vmctl.VerrazzanoMonitoringInstance) []string {
+	cm, err := client.CoreV1().ConfigMaps(vmo.Namespace).Get(context.TODO(), vmo.Spec.Prometheus.ConfigMap, metav1.GetOptions{})
+	assert.NoError(t, err)
+	var config map[interface{}]interface{}
+	assert.NoError(t, yaml.Unmarshal([]byte(cm.Data["prometheus.yml"]), &config))
+	var jobs []string
+	for _, sc := range config["scrape_configs"].([]interface{}) {
+		jobs = append(jobs, sc.(map[interface{}]interface{})["job_name"].(string))
+	}
+	return jobs
+}
+
+// TestAdditionalScrapeConfigs tests merging the additional scrape configs of a VMI into the Prometheus configuration
+// GIVEN a VMI with additional scrape configs in a Secret and a ConfigMap
+// WHEN CreateConfigmaps is called as the additional scrape configs change
+// THEN the scrape configs are appended after the default scrape configs in order, duplicate jobs are reported in the
+// status without changing the configuration, and dropped jobs are removed, also after the configuration failed to be written
+func TestAdditionalScrapeConfigs(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "scrape-secret", Namespace: constants.VerrazzanoSystemNamespace},
+			Data:       map[string][]byte{"jobs.yaml": []byte(testSecretScrapeConfigs)},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "scrape-configmap", Namespace: constants.VerrazzanoSystemNamespace},
+			Data:       map[string]string{"jobs.yaml": testConfigMapScrapeConfigs},
+		},
+	)
+	controller := &Controller{
+		kubeclientset:   client,
+		configMapLister: &simpleConfigMapLister{kubeClient: client},
+		secretLister:    &simpleSecretLister{kubeClient: client},
+		log:             vzlog.DefaultLogger(),
+	}
+	vmo := &vmctl.VerrazzanoMonitoringInstance{}
+	vmo.Name = constants.VMODefaultName
+	vmo.Namespace = constants.VerrazzanoSystemNamespace
+	vmo.Spec.Prometheus.ConfigMap = "myPrometheusConfigMap"
+	optional := true
+	secretSource := vmctl.ScrapeConfigSource{Secret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "scrape-secret"}, Key: "jobs.yaml"}}
+	configMapSource := vmctl.ScrapeConfigSource{ConfigMap: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "scrape-configmap"}, Key: "jobs.yaml"}}
+	missingSource := vmctl.ScrapeConfigSource{ConfigMap: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "missing"}, Key: "jobs.yaml", Optional: &optional}}
+	vmo.Spec.Prometheus.AdditionalScrapeConfigs = []vmctl.ScrapeConfigSource{configMapSource, secretSource, missingSource}
+
+	assert.NoError(t, CreateConfigmaps(controller, vmo))
+	jobs := getScrapeJobs(t, client, vmo)
+	assert.Equal(t, []string{"app-c", "app-a", "app-b"}, jobs[len(jobs)-3:])
+	assert.Equal(t, "prometheus", jobs[0])
+	assert.Equal(t, []string{"app-c", "app-a", "app-b"}, vmo.Status.Prometheus.AdditionalScrapeConfigJobs)
+	assert.Empty(t, vmo.Status.Prometheus.AdditionalScrapeConfigsError)
+
+	// a job which is already defined is rejected, and the configuration is kept
+	vmo.Spec.Prometheus.AdditionalScrapeConfigs = []vmctl.ScrapeConfigSource{secretSource, secretSource}
+	assert.NoError(t, CreateConfigmaps(controller, vmo))
+	assert.Equal(t, jobs, getScrapeJobs(t, client, vmo))
+	assert.Contains(t, vmo.Status.Prometheus.AdditionalScrapeConfigsError, "job app-a of Secret scrape-secret key jobs.yaml is already defined")
+	assert.Equal(t, []string{"app-c", "app-a", "app-b"}, vmo.Status.Prometheus.AdditionalScrapeConfigJobs)
+
+	// a default job cannot be redefined
+	secret, err := client.CoreV1().Secrets(vmo.Namespace).Get(context.TODO(), "scrape-secret", metav1.GetOptions{})
+	assert.NoError(t, err)
+	secret.Data["prometheus.yaml"] = []byte("- job_name: prometheus")
+	_, err = client.CoreV1().Secrets(vmo.Namespace).Update(context.TODO(), secret, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	vmo.Spec.Prometheus.AdditionalScrapeConfigs = []vmctl.ScrapeConfigSource{{Secret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "scrape-secret"}, Key: "prometheus.yaml"}}}
+	assert.NoError(t, CreateConfigmaps(controller, vmo))
+	assert.Contains(t, vmo.Status.Prometheus.AdditionalScrapeConfigsError, "is already defined by the default scrape configs")
+
+	// a missing source is reported
+	vmo.Spec.Prometheus.AdditionalScrapeConfigs = []vmctl.ScrapeConfigSource{{Secret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "scrape-secret"}, Key: "missing"}}}
+	assert.NoError(t, CreateConfigmaps(controller, vmo))
+	assert.Contains(t, vmo.Status.Prometheus.AdditionalScrapeConfigsError, "Secret scrape-secret key missing does not exist")
+
+	// the credentials of a Secret are read from its mounted keys, and may not be copied into the Prometheus ConfigMap
+	secret.Data["auth.yaml"] = []byte(`
+- job_name: app-d
+  kubernetes_sd_configs:
+  - role: pod
+    basic_auth:
+      username: app
+      password: plaintext
+`)
+	_, err = client.CoreV1().Secrets(vmo.Namespace).Update(context.TODO(), secret, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	vmo.Spec.Prometheus.AdditionalScrapeConfigs = []vmctl.ScrapeConfigSource{{Secret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "scrape-secret"}, Key: "auth.yaml"}}}
+	assert.NoError(t, CreateConfigmaps(controller, vmo))
+	assert.Contains(t, vmo.Status.Prometheus.AdditionalScrapeConfigsError, "job app-d of Secret scrape-secret key auth.yaml holds the inline credential password, use password_file with a key of the Secret under /etc/prometheus/scrape-configs/scrape-secret instead")
+	assert.Equal(t, jobs, getScrapeJobs(t, client, vmo))
+
+	// dropped jobs are still known when the configuration cannot be written, so they are removed on the next attempt
+	vmo.Spec.Prometheus.AdditionalScrapeConfigs = []vmctl.ScrapeConfigSource{secretSource}
+	failUpdates := true
+	client.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if failUpdates {
+			return true, nil, errors.New("conflict")
+		}
+		return false, nil, nil
+	})
+	assert.Error(t, CreateConfigmaps(controller, vmo))
+	assert.Equal(t, jobs, getScrapeJobs(t, client, vmo))
+	assert.Equal(t, []string{"app-c", "app-a", "app-b"}, vmo.Status.Prometheus.AdditionalScrapeConfigJobs)
+	failUpdates = false
+
+	// dropped jobs are removed
+	assert.NoError(t, CreateConfigmaps(controller, vmo))
+	jobs = getScrapeJobs(t, client, vmo)
+	assert.Equal(t, []string{"app-a", "app-b"}, jobs[len(jobs)-2:])
+	assert.NotContains(t, jobs, "app-c")
+	assert.Equal(t, []string{"app-a", "app-b"}, vmo.Status.Prometheus.AdditionalScrapeConfigJobs)
+	assert.Empty(t, vmo.Status.Prometheus.AdditionalScrapeConfigsError)
+
+	vmo.Spec.Prometheus.AdditionalScrapeConfigs = nil
+	assert.NoError(t, CreateConfigmaps(controller, vmo))
+	assert.NotContains(t, getScrapeJobs(t, client, vmo), "app-a")
+	assert.Empty(t, vmo.Status.Prometheus.AdditionalScrapeConfigJobs)
+}
+
+// TestKeepScrapeConfigSecrets tests keeping the mounted Secrets of additional scrape configs which cannot be merged
+// GIVEN a Prometheus pod spec which mounts the Secret of an additional scrape config
+// WHEN keepScrapeConfigSecrets is called with and without an error merging the additional scrape configs
+// THEN the Secrets of the existing pod spec are kept while the error is reported, and replaced otherwise
+func TestKeepScrapeConfigSecrets(t *testing.T) {
+	vmo := &vmctl.VerrazzanoMonitoringInstance{}
+	newPodSpec := func(secrets ...string) *corev1.PodSpec {
+		vmo.Spec.Prometheus.AdditionalScrapeConfigs = nil
+		for _, secret := range secrets {
+			vmo.Spec.Prometheus.AdditionalScrapeConfigs = append(vmo.Spec.Prometheus.AdditionalScrapeConfigs,
+				vmctl.ScrapeConfigSource{Secret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: secret}, Key: "jobs.yaml"}})
+		}
+		podSpec := &corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "prometheus", VolumeMounts: []corev1.VolumeMount{{Name: "config-volume"}}}},
+			Volumes:    []corev1.Volume{{Name: "config-volume"}},
+		}
+		resources.AddScrapeConfigSecrets(podSpec, vmo)
+		return podSpec
+	}
+	existing := newPodSpec("old-jobs")
+	podSpec := newPodSpec("new-jobs")
+
+	keepScrapeConfigSecrets(vmo, podSpec, existing)
+	assert.Equal(t, "new-jobs", podSpec.Volumes[1].Secret.SecretName)
+
+	vmo.Status.Prometheus.AdditionalScrapeConfigsError = "Secret new-jobs key jobs.yaml does not exist"
+	keepScrapeConfigSecrets(vmo, podSpec, existing)
+	assert.Equal(t, existing, podSpec)
+
+	// a new pod spec mounts no Secrets until the additional scrape configs are merged
+	podSpec = newPodSpec("new-jobs")
+	keepScrapeConfigSecrets(vmo, podSpec, nil)
+	assert.Equal(t, []corev1.Volume{{Name: "config-volume"}}, podSpec.Volumes)
+	assert.Equal(t, []corev1.VolumeMount{{Name: "config-volume"}}, podSpec.Containers[0].VolumeMounts)
+}
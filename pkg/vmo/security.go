@@ -0,0 +1,77 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"fmt"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+//configureSecurityAndTenants configures the security plugin, and then the tenants, in one goroutine, as both update the
+// security configuration of OpenSearch, and concurrent updates may conflict. Both are configured from a copy of the VMI,
+// which changes while they are configured. The returned channels should each be read for exactly one response.
+func configureSecurityAndTenants(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) (chan error, chan error) {
+	securityChannel := make(chan error, 1)
+	tenantsChannel := make(chan error, 1)
+	credentials, credentialsErr := getInternalUserCredentials(controller, vmo)
+	vmoCopy := vmo.DeepCopy()
+	go func() {
+		if credentialsErr != nil {
+			securityChannel <- fmt.Errorf("failed to get OpenSearch internal user passwords: %v", credentialsErr)
+		} else {
+			securityChannel <- <-controller.osClient.ConfigureSecurity(controller.log, vmoCopy, credentials)
+		}
+		tenantsChannel <- <-controller.osClient.ConfigureTenants(controller.log, vmoCopy)
+	}()
+	return securityChannel, tenantsChannel
+}
+
+//getInternalUserCredentials reads the passwords of the OpenSearch internal users from their Secrets.
+// Users whose optional password Secret or key does not exist are left out.
+func getInternalUserCredentials(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) (map[string]opensearch.InternalUserCredentials, error) {
+	credentials := map[string]opensearch.InternalUserCredentials{}
+	security := vmo.Spec.Elasticsearch.Security
+	if !vmo.Spec.Elasticsearch.Enabled || security == nil {
+		return credentials, nil
+	}
+	for _, user := range security.InternalUsers {
+		secret, password, err := getSecretKeyValue(controller, vmo.Namespace, user.PasswordSecret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the password of OpenSearch user %s: %v", user.Username, err)
+		}
+		if secret == nil {
+			continue
+		}
+		credentials[user.Username] = opensearch.InternalUserCredentials{
+			Password: string(password),
+			// the password is updated whenever the Secret changes
+			PasswordVersion: secret.ResourceVersion,
+		}
+	}
+	return credentials, nil
+}
+
+//getSecretKeyValue returns the Secret and the value of its key. The Secret is nil if the reference is optional,
+// and the Secret or key does not exist.
+func getSecretKeyValue(controller *Controller, namespace string, ref corev1.SecretKeySelector) (*corev1.Secret, []byte, error) {
+	optional := ref.Optional != nil && *ref.Optional
+	secret, err := controller.secretLister.Secrets(namespace).Get(ref.Name)
+	if err != nil {
+		if k8serrors.IsNotFound(err) && optional {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		if optional {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("secret %s has no key %s", ref.Name, ref.Key)
+	}
+	return secret, value, nil
+}
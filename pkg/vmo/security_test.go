@@ -0,0 +1,118 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch"
+	"io"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestGetInternalUserCredentials Tests reading the passwords of OpenSearch internal users
+// GIVEN internal users with password Secrets
+// WHEN getInternalUserCredentials is called
+// THEN the passwords are read from the Secrets, and a missing required Secret or key is an error
+func TestGetInternalUserCredentials(t *testing.T) {
+	optional := true
+	passwordRef := func(name, key string, optional *bool) corev1.SecretKeySelector {
+		return corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			Key:                  key,
+			Optional:             optional,
+		}
+	}
+	var tests = []struct {
+		name        string
+		users       []vmcontrollerv1.SecurityInternalUser
+		credentials map[string]opensearch.InternalUserCredentials
+		isError     bool
+	}{
+		{
+			"reads the password from the Secret",
+			[]vmcontrollerv1.SecurityInternalUser{{Username: "reader", PasswordSecret: passwordRef("reader-password", "password", nil)}},
+			map[string]opensearch.InternalUserCredentials{"reader": {Password: "secret", PasswordVersion: "5"}},
+			false,
+		},
+		{
+			"missing Secret is an error",
+			[]vmcontrollerv1.SecurityInternalUser{{Username: "reader", PasswordSecret: passwordRef("missing", "password", nil)}},
+			nil,
+			true,
+		},
+		{
+			"missing key is an error",
+			[]vmcontrollerv1.SecurityInternalUser{{Username: "reader", PasswordSecret: passwordRef("reader-password", "missing", nil)}},
+			nil,
+			true,
+		},
+		{
+			"optional Secret may be missing",
+			[]vmcontrollerv1.SecurityInternalUser{{Username: "reader", PasswordSecret: passwordRef("missing", "password", &optional)}},
+			map[string]opensearch.InternalUserCredentials{},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "reader-password", Namespace: testvmo.Namespace, ResourceVersion: "5"},
+				Data:       map[string][]byte{"password": []byte("secret")},
+			})
+			c := &Controller{secretLister: &simpleSecretLister{kubeClient: client}}
+			vmo := testvmo.DeepCopy()
+			vmo.Spec.Elasticsearch.Enabled = true
+			vmo.Spec.Elasticsearch.Security = &vmcontrollerv1.OpenSearchSecurity{InternalUsers: tt.users}
+			credentials, err := getInternalUserCredentials(c, vmo)
+			if tt.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.credentials, credentials)
+		})
+	}
+}
+
+// TestConfigureSecurityAndTenants Tests that the security plugin is configured before the tenants
+// GIVEN a VMI with a security role and a tenant
+// WHEN configureSecurityAndTenants is called
+// THEN the tenants are only configured once the security configuration has finished
+func TestConfigureSecurityAndTenants(t *testing.T) {
+	var requests []string
+	osClient := opensearch.NewOSClient()
+	osClient.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		requests = append(requests, request.Method+" "+strings.TrimPrefix(request.URL.Path, "/_plugins/_security/api/"))
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+	}
+	c := makeOpenSearchController(map[string]string{})
+	c.osClient = osClient
+	c.secretLister = &simpleSecretLister{kubeClient: fake.NewSimpleClientset()}
+	vmo := testvmo.DeepCopy()
+	vmo.Spec.Elasticsearch.Enabled = true
+	vmo.Spec.Elasticsearch.Security = &vmcontrollerv1.OpenSearchSecurity{Roles: []vmcontrollerv1.SecurityRole{{Name: "reader"}}}
+	vmo.Spec.Kibana.Tenants = []vmcontrollerv1.DashboardsTenant{{Name: "bobs-books", Groups: []string{"bobs-books-admins"}}}
+
+	securityChannel, tenantsChannel := configureSecurityAndTenants(c, vmo)
+	assert.NoError(t, <-tenantsChannel)
+	assert.NoError(t, <-securityChannel)
+	assert.Equal(t, []string{
+		"GET roles",
+		"PUT roles/reader",
+		"GET rolesmapping",
+		"GET internalusers",
+		"GET tenants",
+		"PUT tenants/bobs-books",
+		"GET roles",
+		"PUT roles/vmi_tenant_bobs-books_rw",
+		"GET rolesmapping",
+		"PUT rolesmapping/vmi_tenant_bobs-books_rw",
+	}, requests)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/statefulsets"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 )
@@ -34,11 +35,28 @@ func CreateStatefulSets(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMo
 		controller.log.Errorf("Failed to create StatefulSet specs for VMI %s: %v", vmo.Name, err)
 		return false, err
 	}
+	for _, sts := range expectedList {
+		var existing *corev1.PodSpec
+		for _, existingSts := range existingList {
+			if existingSts.Name == sts.Name {
+				existing = &existingSts.Spec.Template.Spec
+			}
+		}
+		pinOpenSearchImage(vmo, &sts.Spec.Template.Spec, existing)
+	}
 
 	// Loop through the existing stateful sets and create/update as needed
 	controller.log.Oncef("Creating/updating Statefulsets for VMI %s", vmo.Name)
 	plan := statefulsets.CreatePlan(controller.log, existingList, expectedList)
 
+	// A statefulset node that is expected again no longer needs to be drained or excluded from voting
+	if err := cancelStatefulSetNodeDrain(controller, vmo, expectedList); err != nil {
+		return false, err
+	}
+	if err := cancelVotingConfigExclusions(controller, vmo, expectedList); err != nil {
+		return false, err
+	}
+
 	for _, sts := range plan.Create {
 		if _, err := controller.kubeclientset.AppsV1().StatefulSets(vmo.Namespace).Create(context.TODO(), sts, metav1.CreateOptions{}); err != nil {
 			return plan.ExistingCluster, logReturnError(controller.log, sts, err)
@@ -62,6 +80,15 @@ func CreateStatefulSets(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMo
 		}
 	}
 
+	// OpenSearch statefulsets use the OnDelete update strategy, so the operator restarts outdated pods
+	for _, sts := range latestList {
+		if statefulsets.IsOpenSearchMasterStatefulSet(vmo.Name, sts) {
+			if err := restartStatefulSetPods(controller, vmo, sts); err != nil {
+				return plan.ExistingCluster, err
+			}
+		}
+	}
+
 	for _, sts := range plan.Delete {
 		if err := scaleDownStatefulSet(controller, expectedList, sts, vmo); err != nil {
 			return plan.ExistingCluster, err
@@ -98,6 +125,21 @@ func updateStatefulSet(c *Controller, sts *appsv1.StatefulSet, vmo *vmcontroller
 		if err := c.osClient.IsGreen(vmo); err != nil {
 			return err
 		}
+		// OpenSearch nodes are removed one at a time, once they can safely leave the cluster
+		if statefulsets.IsOpenSearchMasterStatefulSet(vmo.Name, sts) {
+			existing, err := c.statefulSetLister.StatefulSets(vmo.Namespace).Get(sts.Name)
+			if err != nil {
+				return err
+			}
+			if *existing.Spec.Replicas > *sts.Spec.Replicas {
+				lastOrdinal := *existing.Spec.Replicas - 1
+				removable, err := prepareStatefulSetNodeRemoval(c, vmo, sts, lastOrdinal)
+				if err != nil || !removable {
+					return err
+				}
+				*sts.Spec.Replicas = lastOrdinal
+			}
+		}
 	}
 
 	if _, err := c.kubeclientset.AppsV1().StatefulSets(vmo.Namespace).Update(context.TODO(), sts, metav1.UpdateOptions{}); err != nil {
@@ -131,6 +173,14 @@ func scaleDownStatefulSet(c *Controller, expectedList []*appsv1.StatefulSet, sta
 		return err
 	}
 
+	// The last node must be able to safely leave the cluster before it is removed
+	if statefulsets.IsOpenSearchMasterStatefulSet(vmo.Name, statefulSet) {
+		removable, err := prepareStatefulSetNodeRemoval(c, vmo, statefulSet, *statefulSet.Spec.Replicas-1)
+		if err != nil || !removable {
+			return err
+		}
+	}
+
 	// If the statefulset has multiple replicas, scale it down. this allows existing data to be migrated to another node on the cluster.
 	// If the statefulset already has one replica, then it can be deleted.
 	if *statefulSet.Spec.Replicas > 1 {
@@ -145,6 +195,19 @@ func scaleDownStatefulSet(c *Controller, expectedList []*appsv1.StatefulSet, sta
 	return nil
 }
 
+//prepareStatefulSetNodeRemoval drains the node's shards and removes it from the voting configuration.
+// Returns true once the node can leave the cluster.
+func prepareStatefulSetNodeRemoval(c *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, statefulSet *appsv1.StatefulSet, ordinal int32) (bool, error) {
+	nodeName := getStatefulSetNodeName(statefulSet, ordinal)
+	if statefulsets.IsOpenSearchDataStatefulSet(vmo.Name, statefulSet) {
+		drained, err := drainOpenSearchNode(c, vmo, statefulSet.Name, nodeName)
+		if err != nil || !drained {
+			return false, err
+		}
+	}
+	return excludeMasterNode(c, vmo, nodeName)
+}
+
 // Update each PVC metadata.ownerReferences field to refer to the StatefulSet (STS).
 // PVCs are created automatically by Kubernetes when the STS is created
 // because the STS has a volumeClaimTemplate.  However, the PVCs are not deleted
@@ -81,3 +81,11 @@ func chooseRandomElementFromSlice(slice []string) string {
 	}
 	return ""
 }
+
+// Returns a channel holding the error, for configuration which fails before it can be started asynchronously.
+// The error is then read with the result of the configuration, after the deployments are created.
+func errorChannel(err error) chan error {
+	ch := make(chan error, 1)
+	ch <- err
+	return ch
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+//excludeMasterNode removes a master eligible node from the voting configuration, so the cluster keeps its quorum when the node leaves.
+// Only one node is excluded at a time. Returns true once another node has been elected master.
+func excludeMasterNode(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, nodeName string) (bool, error) {
+	exclusions := vmo.Status.Elasticsearch.VotingConfigExclusions
+	if !resources.SliceContains(exclusions, nodeName) {
+		if len(exclusions) > 0 {
+			controller.log.Oncef("OpenSearch node %s is waiting for nodes %v to leave the cluster", nodeName, exclusions)
+			return false, nil
+		}
+		controller.log.Oncef("Excluding OpenSearch node %s from the voting configuration", nodeName)
+		if err := controller.osClient.AddVotingConfigExclusion(vmo, nodeName); err != nil {
+			return false, err
+		}
+		vmo.Status.Elasticsearch.VotingConfigExclusions = append(exclusions, nodeName)
+	}
+
+	// wait for the cluster to elect a master that is not leaving
+	master, err := controller.osClient.GetElectedMaster(vmo)
+	if err != nil {
+		return false, err
+	}
+	if master == "" || master == nodeName {
+		controller.log.Progressf("Waiting for OpenSearch to elect a new master before removing node %s", nodeName)
+		return false, nil
+	}
+	return true, nil
+}
+
+//finishVotingConfigExclusions clears the voting configuration exclusions once the excluded nodes have left the cluster
+func finishVotingConfigExclusions(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	exclusions := vmo.Status.Elasticsearch.VotingConfigExclusions
+	if len(exclusions) < 1 {
+		return nil
+	}
+	if !vmo.Spec.Elasticsearch.Enabled {
+		vmo.Status.Elasticsearch.VotingConfigExclusions = nil
+		return nil
+	}
+	for _, nodeName := range exclusions {
+		inCluster, err := controller.osClient.IsNodeInCluster(vmo, nodeName)
+		if err != nil || inCluster {
+			return err
+		}
+	}
+	return clearVotingConfigExclusions(controller, vmo)
+}
+
+//cancelVotingConfigExclusions clears the voting configuration exclusions if an excluded node is expected to stay in the cluster
+func cancelVotingConfigExclusions(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, expectedList []*appsv1.StatefulSet) error {
+	for _, nodeName := range vmo.Status.Elasticsearch.VotingConfigExclusions {
+		if isExpectedStatefulSetNode(expectedList, nodeName) {
+			controller.log.Oncef("OpenSearch node %s is expected, cancelling voting configuration exclusions", nodeName)
+			return clearVotingConfigExclusions(controller, vmo)
+		}
+	}
+	return nil
+}
+
+func clearVotingConfigExclusions(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	if err := controller.osClient.ClearVotingConfigExclusions(vmo); err != nil {
+		return err
+	}
+	vmo.Status.Elasticsearch.VotingConfigExclusions = nil
+	return nil
+}
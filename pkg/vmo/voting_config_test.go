@@ -0,0 +1,88 @@
+// Copyright (C) 2022, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func TestExcludeMasterNode(t *testing.T) {
+	var tests = []struct {
+		name       string
+		exclusions []string
+		master     string
+		removable  bool
+		expected   []string
+	}{
+		{
+			"excludes the node and waits for a new master",
+			nil,
+			`[{"node": "master-2"}]`,
+			false,
+			[]string{"master-2"},
+		},
+		{
+			"node is removable once another master is elected",
+			[]string{"master-2"},
+			`[{"node": "master-0"}]`,
+			true,
+			[]string{"master-2"},
+		},
+		{
+			"waits for a previously excluded node to leave the cluster",
+			[]string{"master-4"},
+			`[{"node": "master-0"}]`,
+			false,
+			[]string{"master-4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vmo := testvmo.DeepCopy()
+			vmo.Spec.Elasticsearch.Enabled = true
+			vmo.Status.Elasticsearch.VotingConfigExclusions = tt.exclusions
+			c := makeOpenSearchController(map[string]string{"/_cat/master": tt.master})
+			removable, err := excludeMasterNode(c, vmo, "master-2")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.removable, removable)
+			assert.Equal(t, tt.expected, vmo.Status.Elasticsearch.VotingConfigExclusions)
+		})
+	}
+}
+
+func TestFinishVotingConfigExclusions(t *testing.T) {
+	vmo := testvmo.DeepCopy()
+	vmo.Spec.Elasticsearch.Enabled = true
+	vmo.Status.Elasticsearch.VotingConfigExclusions = []string{"master-2"}
+
+	c := makeOpenSearchController(map[string]string{"/_cat/nodes": `[{"name": "master-0"}, {"name": "master-2"}]`})
+	assert.NoError(t, finishVotingConfigExclusions(c, vmo))
+	assert.Equal(t, []string{"master-2"}, vmo.Status.Elasticsearch.VotingConfigExclusions)
+
+	c = makeOpenSearchController(map[string]string{"/_cat/nodes": `[{"name": "master-0"}]`})
+	assert.NoError(t, finishVotingConfigExclusions(c, vmo))
+	assert.Empty(t, vmo.Status.Elasticsearch.VotingConfigExclusions)
+}
+
+func TestCancelVotingConfigExclusions(t *testing.T) {
+	replicas := int32(3)
+	expected := []*appsv1.StatefulSet{{
+		ObjectMeta: metav1.ObjectMeta{Name: "master"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+	}}
+	c := makeOpenSearchController(nil)
+
+	vmo := testvmo.DeepCopy()
+	vmo.Status.Elasticsearch.VotingConfigExclusions = []string{"master-3"}
+	assert.NoError(t, cancelVotingConfigExclusions(c, vmo, expected))
+	assert.Equal(t, []string{"master-3"}, vmo.Status.Elasticsearch.VotingConfigExclusions)
+
+	vmo.Status.Elasticsearch.VotingConfigExclusions = []string{"master-2"}
+	assert.NoError(t, cancelVotingConfigExclusions(c, vmo, expected))
+	assert.Empty(t, vmo.Status.Elasticsearch.VotingConfigExclusions)
+}